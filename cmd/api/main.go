@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,24 +12,56 @@ import (
 	"time"
 
 	"github.com/agunghallmanmaliki/amartha/internal/config"
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/grpcapi"
 	"github.com/agunghallmanmaliki/amartha/internal/handler"
+	"github.com/agunghallmanmaliki/amartha/internal/handler/middleware"
+	"github.com/agunghallmanmaliki/amartha/internal/health"
+	"github.com/agunghallmanmaliki/amartha/internal/logging"
+	"github.com/agunghallmanmaliki/amartha/internal/ratelimit"
 	"github.com/agunghallmanmaliki/amartha/internal/repository/postgres"
+	"github.com/agunghallmanmaliki/amartha/internal/scan"
 	"github.com/agunghallmanmaliki/amartha/internal/service"
-	"github.com/agunghallmanmaliki/amartha/internal/storage/local"
+	"github.com/agunghallmanmaliki/amartha/internal/signing"
+	"github.com/agunghallmanmaliki/amartha/internal/storage/driver"
+	"github.com/agunghallmanmaliki/amartha/internal/telemetry"
+	"github.com/agunghallmanmaliki/amartha/internal/upload"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
 )
 
+const rateLimitPruneInterval = 10 * time.Minute
+
 func main() {
 	// Initialize logger
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	logger := slog.New(logging.NewContextHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
-	}))
+	})))
 
 	// Load configuration
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
 
-	// Initialize database
 	ctx := context.Background()
-	db, err := postgres.NewDB(ctx, cfg.DatabaseURL)
+
+	// Initialize tracing
+	shutdownTracing, err := telemetry.Setup(ctx, telemetry.Config{
+		ServiceName:  cfg.ServiceName,
+		Exporter:     cfg.TracingExporter,
+		OTLPEndpoint: cfg.OTLPEndpoint,
+		OTLPInsecure: cfg.OTLPInsecure,
+		SampleRatio:  cfg.TraceSampleRatio,
+	})
+	if err != nil {
+		logger.Error("failed to initialize tracing", "error", err)
+		os.Exit(1)
+	}
+
+	// Initialize database
+	db, err := postgres.NewDB(ctx, cfg.DatabaseURL, logger)
 	if err != nil {
 		logger.Error("failed to connect to database", "error", err)
 		os.Exit(1)
@@ -35,7 +69,7 @@ func main() {
 	defer db.Close()
 
 	// Initialize storage
-	storage, err := local.NewLocalStorage(cfg.StoragePath, cfg.ServerHost)
+	storage, uploads, err := driver.New(cfg, postgres.NewDataKeyRepository(db), logger)
 	if err != nil {
 		logger.Error("failed to initialize storage", "error", err)
 		os.Exit(1)
@@ -43,27 +77,119 @@ func main() {
 
 	// Initialize repositories
 	loanRepo := postgres.NewLoanRepository(db)
+	productRepo := postgres.NewLoanProductRepository(db)
 	approvalRepo := postgres.NewApprovalRepository(db)
 	investmentRepo := postgres.NewInvestmentRepository(db)
 	disbursementRepo := postgres.NewDisbursementRepository(db)
+	documentRepo := postgres.NewDocumentRepository(db)
+	previewRepo := postgres.NewPreviewRepository(db)
+
+	// Initialize agreement letter signing
+	var signer *signing.Signer
+	if cfg.AgreementSigningKeyFile != "" {
+		signer, err = signing.LoadSigner(cfg.AgreementSigningKeyFile, cfg.AgreementSigningCertFile)
+		if err != nil {
+			logger.Error("failed to initialize agreement signing", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("signing agreement letters", "fingerprint", signer.Fingerprint())
+	} else {
+		logger.Warn("AGREEMENT_SIGNING_KEY_FILE is not set; agreement letters will not be signed")
+	}
 
 	// Initialize services
 	emailService := service.NewMockEmailService(logger)
 	loanService := service.NewLoanService(
 		loanRepo,
+		productRepo,
 		approvalRepo,
 		investmentRepo,
 		disbursementRepo,
+		documentRepo,
+		previewRepo,
 		db,
+		storage,
 		emailService,
+		domain.PhotoPolicy{MaxAge: cfg.PhotoMaxAge, MaxClockSkew: cfg.PhotoMaxClockSkew, MaxDistanceMeters: cfg.PhotoMaxDistanceMeters},
+		signer,
 		logger,
 	)
 
+	// Initialize malware scanning
+	var scanner scan.Scanner
+	if cfg.ClamdAddress != "" {
+		clamd, err := scan.NewClamd(cfg.ClamdAddress, cfg.ClamdTimeout)
+		if err != nil {
+			logger.Error("failed to initialize malware scanner", "error", err)
+			os.Exit(1)
+		}
+		scanner = clamd
+	} else {
+		logger.Warn("CLAMD_ADDRESS is not set; uploads will not be scanned for malware")
+	}
+	uploadGate := scan.NewGate(scanner, storage, cfg.ScanFailOpen, logger)
+
 	// Initialize handlers
-	loanHandler := handler.NewLoanHandler(loanService, storage, cfg.MaxFileSize)
+	pictureProof := upload.PictureProof(cfg.MaxPictureProofSize)
+	signedAgreement := upload.SignedAgreement(cfg.MaxSignedAgreementSize)
+	loanHandler := handler.NewLoanHandler(loanService, storage, uploadGate, cfg.MaxFileSize, cfg.MaxBatchRows, cfg.SignedURLTTL, pictureProof, signedAgreement)
+
+	// Initialize health checks
+	checker := health.NewChecker(cfg.HealthCheckTimeout, logger)
+	checker.Register("database", db.Ping)
+	checker.Register("storage", storage.CheckWritable)
+	if expected, err := postgres.LatestMigrationVersion(cfg.MigrationsPath); err != nil {
+		logger.Warn("migration check disabled", "error", err)
+	} else {
+		checker.Register("migrations", db.MigrationCheck(expected))
+	}
+	checker.Register("notifications", func(ctx context.Context) error {
+		if pending := loanService.PendingNotifications(); pending > cfg.NotificationBacklogLimit {
+			return fmt.Errorf("%d investor notifications pending (limit %d)", pending, cfg.NotificationBacklogLimit)
+		}
+		return nil
+	})
+
+	// Initialize rate limiting
+	var rateLimiter *middleware.RateLimiter
+	pruneCtx, stopPruning := context.WithCancel(context.Background())
+	defer stopPruning()
+	if cfg.RateLimitEnabled {
+		var store ratelimit.Store = ratelimit.NewMemoryStore(cfg.RateLimitMaxBuckets)
+		if cfg.RateLimitStore == "postgres" {
+			pgStore := postgres.NewRateLimitStore(db)
+			go pruneRateLimitBuckets(pruneCtx, pgStore, ratelimit.LongestPeriod(cfg.RateLimitPolicies), logger)
+			store = pgStore
+		}
+		rateLimiter = middleware.NewRateLimiter(store, cfg.RateLimitPolicies, logger)
+	}
+
+	// Initialize browser access
+	var cors *middleware.CORS
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		cors, err = middleware.NewCORS(middleware.CORSConfig{
+			AllowedOrigins:   cfg.CORSAllowedOrigins,
+			AllowedMethods:   cfg.CORSAllowedMethods,
+			AllowedHeaders:   cfg.CORSAllowedHeaders,
+			ExposedHeaders:   cfg.CORSExposedHeaders,
+			AllowCredentials: cfg.CORSAllowCredentials,
+			MaxAge:           cfg.CORSMaxAge,
+		})
+		if err != nil {
+			logger.Error("failed to initialize CORS", "error", err)
+			os.Exit(1)
+		}
+	}
+	securityHeaders := middleware.SecurityHeadersConfig{
+		HSTSMaxAge:            cfg.HSTSMaxAge,
+		HSTSIncludeSubdomains: cfg.HSTSIncludeSubdomains,
+		ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+		FrameOptions:          cfg.FrameOptions,
+		ReferrerPolicy:        cfg.ReferrerPolicy,
+	}
 
 	// Setup router
-	router := handler.NewRouter(loanHandler, logger)
+	router := handler.NewRouter(loanHandler, checker, rateLimiter, cors, securityHeaders, uploads, cfg.TrustedProxies, logger)
 	httpHandler := router.Setup()
 
 	// Create server
@@ -84,12 +210,42 @@ func main() {
 		}
 	}()
 
+	// Start gRPC server on its own port
+	var grpcServer *grpc.Server
+	var grpcHealth *grpchealth.Server
+	if cfg.GRPCEnabled {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			logger.Error("failed to listen for gRPC", "error", err)
+			os.Exit(1)
+		}
+
+		loanServer := grpcapi.NewLoanServer(loanService, storage, uploadGate, pictureProof, signedAgreement, logger)
+		grpcServer, grpcHealth = grpcapi.NewServer(loanServer, cfg.TrustedProxies, logger)
+
+		go func() {
+			logger.Info("starting gRPC server", "port", cfg.GRPCPort)
+			if err := grpcServer.Serve(listener); err != nil {
+				logger.Error("gRPC server failed", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Info("shutting down server...")
+	stopPruning()
+
+	// Fail readiness first so load balancers stop routing new traffic here
+	checker.SetReady(false)
+	if grpcHealth != nil {
+		grpcHealth.Shutdown()
+	}
+	time.Sleep(cfg.ShutdownDrainDelay)
 
 	// Graceful shutdown
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -99,5 +255,45 @@ func main() {
 		logger.Error("server forced to shutdown", "error", err)
 	}
 
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", "error", err)
+	}
+
 	logger.Info("server stopped")
 }
+
+// pruneRateLimitBuckets periodically deletes Postgres buckets idle for longer
+// than retention, until ctx is cancelled.
+func pruneRateLimitBuckets(ctx context.Context, store *postgres.RateLimitStore, retention time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(rateLimitPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			deleted, err := store.DeleteIdle(ctx, now.Add(-retention))
+			if err != nil {
+				logger.Error("failed to prune rate limit buckets", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				logger.Debug("pruned rate limit buckets", "deleted", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Command storagectl runs maintenance tasks against the configured storage
+// backend. It reads the same environment as the API.
+//
+//	storagectl gc [-dry-run] [-delete] [-grace 24h]
+//	storagectl rotate-keys [-dry-run]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/config"
+	"github.com/agunghallmanmaliki/amartha/internal/logging"
+	"github.com/agunghallmanmaliki/amartha/internal/repository/postgres"
+	"github.com/agunghallmanmaliki/amartha/internal/storage/driver"
+	"github.com/agunghallmanmaliki/amartha/internal/storage/encrypted"
+	"github.com/agunghallmanmaliki/amartha/internal/storage/gc"
+)
+
+func main() {
+	logger := slog.New(logging.NewContextHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	})))
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "gc":
+		err = runGC(ctx, os.Args[2:], logger)
+	case "rotate-keys":
+		err = runRotateKeys(ctx, os.Args[2:], logger)
+	default:
+		usage()
+	}
+	if err != nil {
+		logger.Error(os.Args[1]+" failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: storagectl gc [-dry-run] [-delete] [-grace 24h]")
+	fmt.Fprintln(os.Stderr, "       storagectl rotate-keys [-dry-run]")
+	os.Exit(2)
+}
+
+// runGC reconciles storage against the approvals, disbursements and loans
+// that reference it.
+func runGC(ctx context.Context, args []string, logger *slog.Logger) error {
+	flags := flag.NewFlagSet("gc", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would change without changing it")
+	deleteOrphans := flags.Bool("delete", false, "delete unreferenced committed files instead of quarantining them")
+	grace := flags.Duration("grace", 24*time.Hour, "leave unreferenced files younger than this alone")
+	flags.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	db, err := postgres.NewDB(ctx, cfg.DatabaseURL, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	store, _, err := driver.New(cfg, postgres.NewDataKeyRepository(db), logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	collector := gc.NewCollector(store, postgres.NewStoredFileRepository(db), *grace, logger)
+	report, err := collector.Run(ctx, gc.Options{DryRun: *dryRun, Delete: *deleteOrphans})
+	logger.Info("storage gc finished",
+		"dry_run", *dryRun,
+		"promoted", report.Promoted,
+		"expired", report.Expired,
+		"quarantined", report.Quarantined,
+		"deleted", report.Deleted,
+		"skipped", report.Skipped,
+		"failed", report.Failed,
+	)
+	if err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d files could not be collected", report.Failed)
+	}
+	return nil
+}
+
+// runRotateKeys rewraps the data keys of encrypted files with the current
+// master key, so older master keys can be removed from the keyring.
+func runRotateKeys(ctx context.Context, args []string, logger *slog.Logger) error {
+	flags := flag.NewFlagSet("rotate-keys", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would change without changing it")
+	flags.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	keyring, err := driver.Keyring(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load keyring: %w", err)
+	}
+	if keyring == nil {
+		return errors.New("encryption at rest is not configured")
+	}
+
+	db, err := postgres.NewDB(ctx, cfg.DatabaseURL, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	rotator := encrypted.NewRotator(keyring, postgres.NewDataKeyRepository(db), logger)
+	report, err := rotator.Run(ctx, *dryRun)
+	logger.Info("key rotation finished",
+		"dry_run", *dryRun,
+		"master_key_id", keyring.Current(),
+		"rewrapped", report.Rewrapped,
+		"skipped", report.Skipped,
+		"failed", report.Failed,
+	)
+	if err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d data keys could not be rewrapped", report.Failed)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import "testing"
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{"defaults", nil, false},
+		{"credentials with listed origins", map[string]string{
+			"CORS_ALLOWED_ORIGINS": "https://backoffice.amartha.com", "CORS_ALLOW_CREDENTIALS": "true",
+		}, false},
+		{"credentials with any origin", map[string]string{
+			"CORS_ALLOWED_ORIGINS": "https://backoffice.amartha.com,*", "CORS_ALLOW_CREDENTIALS": "true",
+		}, true},
+		{"trusted proxies", map[string]string{"TRUSTED_PROXIES": "10.0.0.0/8, 192.168.1.10"}, false},
+		{"invalid trusted proxy", map[string]string{"TRUSTED_PROXIES": "gateway.internal"}, true},
+		{"rate limit policies", map[string]string{"RATE_LIMIT_POLICIES": "default=100/1m"}, false},
+		{"invalid rate limit policies", map[string]string{"RATE_LIMIT_POLICIES": "default=abc/1m"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && cfg == nil {
+				t.Error("expected a config")
+			}
+		})
+	}
+}
+
+func TestLoadDefaultCORSHeaders(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, header := range cfg.CORSAllowedHeaders {
+		if header == "X-Actor-ID" || header == "X-Actor-Role" {
+			t.Errorf("expected %s not to be allowed cross-origin by default", header)
+		}
+	}
+}
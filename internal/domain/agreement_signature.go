@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// AgreementSignature is our detached signature over a loan's agreement
+// letter. Signature signs the letter's SHA-256 digest, SHA256 in hex, with
+// the key whose certificate, or public key if it has none, has the
+// fingerprint SignerFingerprint.
+type AgreementSignature struct {
+	SHA256            string
+	Signature         []byte
+	SignerFingerprint string
+	SignedAt          time.Time
+}
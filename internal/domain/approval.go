@@ -10,16 +10,18 @@ type Approval struct {
 	ID               uuid.UUID
 	LoanID           uuid.UUID
 	FieldValidatorID string
-	PictureProofURL  string
+	PictureProof     StoredFile
+	Photo            PhotoMetadata
 	ApprovedAt       time.Time
 }
 
-func NewApproval(loanID uuid.UUID, fieldValidatorID, pictureProofURL string) *Approval {
+func NewApproval(loanID uuid.UUID, fieldValidatorID string, pictureProof StoredFile, photo PhotoMetadata) *Approval {
 	return &Approval{
 		ID:               uuid.New(),
 		LoanID:           loanID,
 		FieldValidatorID: fieldValidatorID,
-		PictureProofURL:  pictureProofURL,
+		PictureProof:     pictureProof,
+		Photo:            photo,
 		ApprovedAt:       time.Now(),
 	}
 }
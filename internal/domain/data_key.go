@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// DataKey is the key a stored file is encrypted with, wrapped by the master
+// key MasterKeyID names. StorageKey is the key of the file in storage.
+type DataKey struct {
+	StorageKey  string
+	MasterKeyID string
+	Wrapped     []byte
+	CreatedAt   time.Time
+	RotatedAt   *time.Time
+}
@@ -0,0 +1,266 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DecimalScale is the number of fractional digits a Decimal holds, matching
+// the DECIMAL(10, 4) columns rates and ROIs are stored in.
+const DecimalScale = 4
+
+const decimalFactor = 10000
+
+// maxDecimalUnits bounds Decimals to what DECIMAL(10, 4) can store.
+const maxDecimalUnits = 1e10 - 1
+
+var (
+	ErrInvalidDecimal    = errors.New("invalid decimal")
+	ErrDecimalPrecision  = errors.New("decimal has more than 4 fractional digits")
+	ErrDecimalOutOfRange = errors.New("decimal is out of range")
+)
+
+// Decimal is an exact fixed-point number with DecimalScale fractional
+// digits, used for rates and ROIs. The zero value is 0.
+type Decimal struct {
+	units int64
+}
+
+// DecimalFromUnits returns units × 10^-4, e.g. DecimalFromUnits(1500) is 0.15.
+func DecimalFromUnits(units int64) (Decimal, error) {
+	if units > maxDecimalUnits || units < -maxDecimalUnits {
+		return Decimal{}, ErrDecimalOutOfRange
+	}
+	return Decimal{units: units}, nil
+}
+
+// ParseDecimal parses a plain decimal such as "0.15", "-2" or "12.5000".
+// Exponents are not accepted, and neither are digits beyond DecimalScale, so
+// parsing never rounds.
+func ParseDecimal(s string) (Decimal, error) {
+	if s == "" {
+		return Decimal{}, ErrInvalidDecimal
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart, hasPoint := strings.Cut(s, ".")
+	if intPart == "" || (hasPoint && fracPart == "") {
+		return Decimal{}, ErrInvalidDecimal
+	}
+	if !allDigits(intPart) || !allDigits(fracPart) {
+		return Decimal{}, ErrInvalidDecimal
+	}
+
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > DecimalScale {
+		return Decimal{}, ErrDecimalPrecision
+	}
+	intPart = strings.TrimLeft(intPart, "0")
+	if len(intPart) > 10-DecimalScale {
+		return Decimal{}, ErrDecimalOutOfRange
+	}
+
+	var units int64
+	for _, c := range intPart {
+		units = units*10 + int64(c-'0')
+	}
+	for i := 0; i < DecimalScale; i++ {
+		units *= 10
+		if i < len(fracPart) {
+			units += int64(fracPart[i] - '0')
+		}
+	}
+	if negative {
+		units = -units
+	}
+	return Decimal{units: units}, nil
+}
+
+// MustParseDecimal is ParseDecimal for constants; it panics on error.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic("domain: " + err.Error() + ": " + s)
+	}
+	return d
+}
+
+func allDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Units returns d × 10^4.
+func (d Decimal) Units() int64 {
+	return d.units
+}
+
+// String formats d without trailing zeros, e.g. "0.15" or "2".
+func (d Decimal) String() string {
+	units := d.units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+
+	s := sign + strconv.FormatInt(units/decimalFactor, 10)
+	if frac := units % decimalFactor; frac != 0 {
+		digits := strconv.FormatInt(frac+decimalFactor, 10)[1:]
+		s += "." + strings.TrimRight(digits, "0")
+	}
+	return s
+}
+
+// Float64 returns the nearest float64, for display and metrics only.
+func (d Decimal) Float64() float64 {
+	return float64(d.units) / decimalFactor
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	switch {
+	case d.units < other.units:
+		return -1
+	case d.units > other.units:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (d Decimal) IsZero() bool {
+	return d.units == 0
+}
+
+func (d Decimal) Sign() int {
+	return d.Cmp(Decimal{})
+}
+
+// Add returns d + other, or ErrDecimalOutOfRange if the sum does not fit.
+func (d Decimal) Add(other Decimal) (Decimal, error) {
+	return DecimalFromUnits(d.units + other.units)
+}
+
+// Sub returns d - other, or ErrDecimalOutOfRange if the difference does not
+// fit.
+func (d Decimal) Sub(other Decimal) (Decimal, error) {
+	return DecimalFromUnits(d.units - other.units)
+}
+
+// MulInt returns amount × d rounded to the nearest integer, ties to even, as
+// used for interest and returns on amounts in the smallest currency unit.
+// It returns ErrDecimalOutOfRange if the result does not fit in an int64.
+func (d Decimal) MulInt(amount int64) (int64, error) {
+	negative := (amount < 0) != (d.units < 0)
+
+	hi, lo := bits.Mul64(absUint(amount), absUint(d.units))
+	if hi >= decimalFactor {
+		return 0, ErrDecimalOutOfRange
+	}
+	quo, rem := bits.Div64(hi, lo, decimalFactor)
+
+	if rem > decimalFactor/2 || (rem == decimalFactor/2 && quo%2 == 1) {
+		quo++
+	}
+
+	if negative {
+		if quo > uint64(math.MaxInt64)+1 {
+			return 0, ErrDecimalOutOfRange
+		}
+		return -int64(quo-1) - 1, nil
+	}
+	if quo > math.MaxInt64 {
+		return 0, ErrDecimalOutOfRange
+	}
+	return int64(quo), nil
+}
+
+func absUint(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
+
+// MarshalJSON writes d as a JSON number with its exact digits.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding a decimal, e.g.
+// 0.15 or "0.15".
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// ScanNumeric implements pgtype.NumericScanner so pgx can scan NUMERIC
+// columns into a Decimal.
+func (d *Decimal) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid {
+		return errors.New("cannot scan NULL into Decimal")
+	}
+	if v.NaN || v.InfinityModifier != pgtype.Finite {
+		return ErrInvalidDecimal
+	}
+
+	units := new(big.Int)
+	if v.Int != nil {
+		units.Set(v.Int)
+	}
+	if shift := int64(v.Exp) + DecimalScale; shift >= 0 {
+		units.Mul(units, new(big.Int).Exp(big.NewInt(10), big.NewInt(shift), nil))
+	} else {
+		var rem big.Int
+		units.QuoRem(units, new(big.Int).Exp(big.NewInt(10), big.NewInt(-shift), nil), &rem)
+		if rem.Sign() != 0 {
+			return ErrDecimalPrecision
+		}
+	}
+	if !units.IsInt64() {
+		return ErrDecimalOutOfRange
+	}
+
+	parsed, err := DecimalFromUnits(units.Int64())
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// NumericValue implements pgtype.NumericValuer so a Decimal can be passed
+// as a NUMERIC query argument.
+func (d Decimal) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(d.units), Exp: -DecimalScale, Valid: true}, nil
+}
@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"testing/quick"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// decimalOf maps an arbitrary int64 into the range a Decimal can hold.
+func decimalOf(n int64) Decimal {
+	return Decimal{units: n % (maxDecimalUnits + 1)}
+}
+
+func TestDecimalStringRoundTrip(t *testing.T) {
+	f := func(n int64) bool {
+		d := decimalOf(n)
+		parsed, err := ParseDecimal(d.String())
+		return err == nil && parsed == d
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecimalJSONRoundTrip(t *testing.T) {
+	f := func(n int64) bool {
+		d := decimalOf(n)
+
+		data, err := json.Marshal(d)
+		if err != nil {
+			return false
+		}
+		var fromNumber Decimal
+		if err := json.Unmarshal(data, &fromNumber); err != nil {
+			return false
+		}
+
+		var fromString Decimal
+		if err := json.Unmarshal([]byte(`"`+d.String()+`"`), &fromString); err != nil {
+			return false
+		}
+
+		return fromNumber == d && fromString == d
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecimalNumericRoundTrip(t *testing.T) {
+	f := func(n int64, extraZeros uint8) bool {
+		d := decimalOf(n)
+
+		v, err := d.NumericValue()
+		if err != nil {
+			return false
+		}
+		var scanned Decimal
+		if err := scanned.ScanNumeric(v); err != nil || scanned != d {
+			return false
+		}
+
+		// PostgreSQL may return the same value with a different exponent,
+		// e.g. 0.1500 as 15 × 10^-2 or 150000 × 10^-6.
+		k := int64(extraZeros % 8)
+		scaled := pgtype.Numeric{
+			Int:   new(big.Int).Mul(v.Int, new(big.Int).Exp(big.NewInt(10), big.NewInt(k), nil)),
+			Exp:   v.Exp - int32(k),
+			Valid: true,
+		}
+		var rescanned Decimal
+		return rescanned.ScanNumeric(scaled) == nil && rescanned == d
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecimalMulInt(t *testing.T) {
+	f := func(n int64, amount int64) bool {
+		d := decimalOf(n)
+		amount %= 1 << 40
+
+		got, err := d.MulInt(amount)
+		if err != nil {
+			return false
+		}
+
+		exact := new(big.Rat).SetFrac(
+			new(big.Int).Mul(big.NewInt(amount), big.NewInt(d.units)),
+			big.NewInt(decimalFactor),
+		)
+		return got == roundHalfEven(exact)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func roundHalfEven(r *big.Rat) int64 {
+	num, den := r.Num(), r.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+	switch twice.Cmp(den) {
+	case 1:
+		quo.Add(quo, big.NewInt(int64(num.Sign())))
+	case 0:
+		if quo.Bit(0) == 1 {
+			quo.Add(quo, big.NewInt(int64(num.Sign())))
+		}
+	}
+	return quo.Int64()
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		err      error
+	}{
+		{"0.15", 1500, nil},
+		{"12.5000", 125000, nil},
+		{"-2", -20000, nil},
+		{"+0.0001", 1, nil},
+		{"999999.9999", maxDecimalUnits, nil},
+		{"0.00001", 0, ErrDecimalPrecision},
+		{"1000000", 0, ErrDecimalOutOfRange},
+		{"1e-2", 0, ErrInvalidDecimal},
+		{".5", 0, ErrInvalidDecimal},
+		{"5.", 0, ErrInvalidDecimal},
+		{"", 0, ErrInvalidDecimal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := ParseDecimal(tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if d.Units() != tt.expected {
+				t.Errorf("expected %d units, got %d", tt.expected, d.Units())
+			}
+		})
+	}
+}
+
+func TestDecimalMulIntRounding(t *testing.T) {
+	tests := []struct {
+		rate     string
+		amount   int64
+		expected int64
+	}{
+		{"0.15", 1000000, 150000},
+		{"0.1", 3, 0},
+		{"0.5", 1, 0},
+		{"0.5", 3, 2},
+		{"0.5", -3, -2},
+		{"0.0001", 9223372036854775807, 922337203685478},
+	}
+
+	for _, tt := range tests {
+		got, err := MustParseDecimal(tt.rate).MulInt(tt.amount)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.expected {
+			t.Errorf("%s × %d: expected %d, got %d", tt.rate, tt.amount, tt.expected, got)
+		}
+	}
+
+	if _, err := MustParseDecimal("2").MulInt(9223372036854775807); !errors.Is(err, ErrDecimalOutOfRange) {
+		t.Errorf("expected ErrDecimalOutOfRange, got %v", err)
+	}
+}
@@ -7,19 +7,19 @@ import (
 )
 
 type Disbursement struct {
-	ID                 uuid.UUID
-	LoanID             uuid.UUID
-	FieldOfficerID     string
-	SignedAgreementURL string
-	DisbursedAt        time.Time
+	ID              uuid.UUID
+	LoanID          uuid.UUID
+	FieldOfficerID  string
+	SignedAgreement StoredFile
+	DisbursedAt     time.Time
 }
 
-func NewDisbursement(loanID uuid.UUID, fieldOfficerID, signedAgreementURL string) *Disbursement {
+func NewDisbursement(loanID uuid.UUID, fieldOfficerID string, signedAgreement StoredFile) *Disbursement {
 	return &Disbursement{
-		ID:                 uuid.New(),
-		LoanID:             loanID,
-		FieldOfficerID:     fieldOfficerID,
-		SignedAgreementURL: signedAgreementURL,
-		DisbursedAt:        time.Now(),
+		ID:              uuid.New(),
+		LoanID:          loanID,
+		FieldOfficerID:  fieldOfficerID,
+		SignedAgreement: signedAgreement,
+		DisbursedAt:     time.Now(),
 	}
 }
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DocumentPurpose says what a document is for; it matches the form field the
+// document is uploaded in.
+type DocumentPurpose string
+
+const (
+	DocumentPictureProof    DocumentPurpose = "picture_proof"
+	DocumentSignedAgreement DocumentPurpose = "signed_agreement"
+)
+
+// Document is one version of a file attached to a loan. Superseding a
+// document adds the next version and keeps the old one, pointing at its
+// successor.
+type Document struct {
+	ID           uuid.UUID
+	LoanID       uuid.UUID
+	Purpose      DocumentPurpose
+	Version      int
+	File         StoredFile
+	UploadedBy   string
+	SupersededBy *uuid.UUID
+	CreatedAt    time.Time
+}
+
+func NewDocument(loanID uuid.UUID, purpose DocumentPurpose, uploadedBy string, file StoredFile) *Document {
+	return &Document{
+		ID:         uuid.New(),
+		LoanID:     loanID,
+		Purpose:    purpose,
+		Version:    1,
+		File:       file,
+		UploadedBy: uploadedBy,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// Action returns the loan action a document of the purpose is uploaded for.
+// Replacing it takes a role that may perform that action.
+func (p DocumentPurpose) Action() LoanAction {
+	if p == DocumentSignedAgreement {
+		return LoanActionDisburse
+	}
+	return LoanActionApprove
+}
+
+// Supersede returns the next version of the document and marks this one as
+// replaced by it. Only the current version can be superseded.
+func (d *Document) Supersede(uploadedBy string, file StoredFile) (*Document, error) {
+	if d.SupersededBy != nil {
+		return nil, ErrDocumentSuperseded
+	}
+	next := NewDocument(d.LoanID, d.Purpose, uploadedBy, file)
+	next.Version = d.Version + 1
+	d.SupersededBy = &next.ID
+	return next, nil
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDocumentSupersede(t *testing.T) {
+	first := NewDocument(uuid.New(), DocumentPictureProof, "validator-1", StoredFile{Key: "a.jpg"})
+
+	second, err := first.Supersede("validator-2", StoredFile{Key: "b.jpg"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.Version != 2 || second.LoanID != first.LoanID || second.Purpose != first.Purpose {
+		t.Errorf("expected version 2 of the same loan document, got %+v", second)
+	}
+	if first.SupersededBy == nil || *first.SupersededBy != second.ID {
+		t.Errorf("expected first version to point at %s, got %v", second.ID, first.SupersededBy)
+	}
+	if second.SupersededBy != nil {
+		t.Error("expected new version to be current")
+	}
+
+	if _, err := first.Supersede("validator-3", StoredFile{Key: "c.jpg"}); !errors.Is(err, ErrDocumentSuperseded) {
+		t.Errorf("expected ErrDocumentSuperseded, got %v", err)
+	}
+}
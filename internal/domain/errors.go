@@ -13,4 +13,23 @@ var (
 	ErrInvalidAmount         = errors.New("invalid amount")
 	ErrApprovalNotFound      = errors.New("approval not found")
 	ErrDisbursementNotFound  = errors.New("disbursement not found")
+	ErrLoanProductNotFound   = errors.New("loan product not found")
+	ErrLoanProductUnavailable = errors.New("loan product does not exist or is not active")
+	ErrLoanProductCodeTaken  = errors.New("loan product code is already in use")
+	ErrLoanProductInUse      = errors.New("loan product is referenced by loans")
+	ErrInvalidLoanProduct    = errors.New("invalid loan product")
+	ErrLoanTermsOutOfRange   = errors.New("loan terms are outside the product's limits")
+	ErrFileTooLarge          = errors.New("file exceeds maximum size")
+	ErrUnsupportedFileType   = errors.New("file type is not allowed")
+	ErrFileExtensionMismatch = errors.New("file extension does not match its content")
+	ErrDocumentNotFound      = errors.New("document not found")
+	ErrDocumentSuperseded    = errors.New("document has been superseded")
+	ErrDocumentTampered      = errors.New("document does not match its checksum")
+	ErrPictureProofRejected  = errors.New("picture proof does not meet the photo policy")
+	ErrPreviewNotFound       = errors.New("preview not found")
+	ErrDataKeyNotFound       = errors.New("data key not found")
+	ErrFileInfected          = errors.New("file contains malware")
+	ErrScanUnavailable       = errors.New("file could not be scanned for malware")
+	ErrAgreementLetterNotFound = errors.New("loan has no agreement letter")
+	ErrRoleNotPermitted      = errors.New("role may not perform this operation")
 )
@@ -24,17 +24,23 @@ var ValidTransitions = map[LoanState]LoanState{
 type Loan struct {
 	ID                 uuid.UUID
 	BorrowerID         string
+	BorrowerName       string
+	Notes              string
 	PrincipalAmount    int64
-	Rate               float64
-	ROI                float64
+	Rate               Decimal
+	ROI                Decimal
+	ProductID          *uuid.UUID
+	TenorMonths        int
+	BorrowerLocation   *GeoPoint
 	State              LoanState
-	AgreementLetterURL *string
+	AgreementLetterKey *string
+	AgreementSignature *AgreementSignature
 	TotalInvested      int64
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
 }
 
-func NewLoan(borrowerID string, principalAmount int64, rate, roi float64) *Loan {
+func NewLoan(borrowerID string, principalAmount int64, rate, roi Decimal) *Loan {
 	now := time.Now()
 	return &Loan{
 		ID:              uuid.New(),
@@ -63,6 +69,18 @@ func (l *Loan) TransitionTo(newState LoanState) error {
 	return nil
 }
 
+// Interest is the borrower's interest on the principal, principal × rate,
+// rounded to the smallest currency unit.
+func (l *Loan) Interest() (int64, error) {
+	return l.Rate.MulInt(l.PrincipalAmount)
+}
+
+// InvestorReturn is what investors earn on amount, amount × ROI, rounded to
+// the smallest currency unit.
+func (l *Loan) InvestorReturn(amount int64) (int64, error) {
+	return l.ROI.MulInt(amount)
+}
+
 func (l *Loan) RemainingAmount() int64 {
 	return l.PrincipalAmount - l.TotalInvested
 }
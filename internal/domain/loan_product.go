@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoanProduct is a catalogue entry that bounds the terms of the loans
+// proposed under it. Rates and ROIs are fractions, e.g. 0.15 for 15%.
+type LoanProduct struct {
+	ID                uuid.UUID
+	Code              string
+	Name              string
+	MinPrincipal      int64
+	MaxPrincipal      int64
+	TenorMonths       []int
+	MinRate           Decimal
+	MaxRate           Decimal
+	MinROI            Decimal
+	MaxROI            Decimal
+	RequiredDocuments []string
+	Active            bool
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func NewLoanProduct(code, name string) *LoanProduct {
+	now := time.Now()
+	return &LoanProduct{
+		ID:        uuid.New(),
+		Code:      code,
+		Name:      name,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Violation is a single business rule broken by an input field.
+type Violation struct {
+	Field   string
+	Rule    string
+	Param   string
+	Message string
+}
+
+// ViolationError reports every rule an input broke. It unwraps to Err, a
+// sentinel identifying the kind of input that was rejected.
+type ViolationError struct {
+	Err        error
+	Violations []Violation
+}
+
+func (e *ViolationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return e.Err.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *ViolationError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks that the product's ranges are well formed and that at
+// least one loan could satisfy them. It returns a *ViolationError wrapping
+// ErrInvalidLoanProduct.
+func (p *LoanProduct) Validate() error {
+	var violations []Violation
+
+	if p.MinPrincipal > p.MaxPrincipal {
+		violations = append(violations, Violation{
+			Field: "max_principal", Rule: "gtefield", Param: "min_principal",
+			Message: "max_principal must be greater than or equal to min_principal",
+		})
+	}
+	if p.MinRate.Cmp(p.MaxRate) > 0 {
+		violations = append(violations, Violation{
+			Field: "max_rate", Rule: "gtefield", Param: "min_rate",
+			Message: "max_rate must be greater than or equal to min_rate",
+		})
+	}
+	if p.MinROI.Cmp(p.MaxROI) > 0 {
+		violations = append(violations, Violation{
+			Field: "max_roi", Rule: "gtefield", Param: "min_roi",
+			Message: "max_roi must be greater than or equal to min_roi",
+		})
+	}
+	if p.MinROI.Cmp(p.MaxRate) > 0 {
+		violations = append(violations, Violation{
+			Field: "min_roi", Rule: "ltefield", Param: "max_rate",
+			Message: "min_roi must be less than or equal to max_rate",
+		})
+	}
+
+	if len(violations) > 0 {
+		return &ViolationError{Err: ErrInvalidLoanProduct, Violations: violations}
+	}
+	return nil
+}
+
+// CheckTerms checks a proposed loan against the product. Regardless of the
+// product, the ROI paid to investors may never exceed the borrower's rate.
+// It returns a *ViolationError wrapping ErrLoanTermsOutOfRange.
+func (p *LoanProduct) CheckTerms(principal int64, tenorMonths int, rate, roi Decimal) error {
+	var violations []Violation
+
+	if principal < p.MinPrincipal || principal > p.MaxPrincipal {
+		violations = append(violations, Violation{
+			Field: "principal_amount", Rule: "range",
+			Param:   fmt.Sprintf("%d-%d", p.MinPrincipal, p.MaxPrincipal),
+			Message: fmt.Sprintf("principal_amount must be between %d and %d", p.MinPrincipal, p.MaxPrincipal),
+		})
+	}
+	if !p.allowsTenor(tenorMonths) {
+		tenors := make([]string, len(p.TenorMonths))
+		for i, t := range p.TenorMonths {
+			tenors[i] = strconv.Itoa(t)
+		}
+		violations = append(violations, Violation{
+			Field: "tenor_months", Rule: "oneof", Param: strings.Join(tenors, " "),
+			Message: "tenor_months must be one of: " + strings.Join(tenors, " "),
+		})
+	}
+	if rate.Cmp(p.MinRate) < 0 || rate.Cmp(p.MaxRate) > 0 {
+		violations = append(violations, Violation{
+			Field: "rate", Rule: "range", Param: p.MinRate.String() + "-" + p.MaxRate.String(),
+			Message: "rate must be between " + p.MinRate.String() + " and " + p.MaxRate.String(),
+		})
+	}
+	if roi.Cmp(p.MinROI) < 0 || roi.Cmp(p.MaxROI) > 0 {
+		violations = append(violations, Violation{
+			Field: "roi", Rule: "range", Param: p.MinROI.String() + "-" + p.MaxROI.String(),
+			Message: "roi must be between " + p.MinROI.String() + " and " + p.MaxROI.String(),
+		})
+	}
+	if roi.Cmp(rate) > 0 {
+		violations = append(violations, Violation{
+			Field: "roi", Rule: "ltefield", Param: "rate",
+			Message: "roi must be less than or equal to rate",
+		})
+	}
+
+	if len(violations) > 0 {
+		return &ViolationError{Err: ErrLoanTermsOutOfRange, Violations: violations}
+	}
+	return nil
+}
+
+func (p *LoanProduct) allowsTenor(months int) bool {
+	for _, t := range p.TenorMonths {
+		if t == months {
+			return true
+		}
+	}
+	return false
+}
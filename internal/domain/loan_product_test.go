@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func testProduct() *LoanProduct {
+	p := NewLoanProduct("micro-12", "Micro business")
+	p.MinPrincipal = 1000000
+	p.MaxPrincipal = 10000000
+	p.TenorMonths = []int{6, 12}
+	p.MinRate, p.MaxRate = MustParseDecimal("0.10"), MustParseDecimal("0.20")
+	p.MinROI, p.MaxROI = MustParseDecimal("0.05"), MustParseDecimal("0.15")
+	return p
+}
+
+func TestLoanProductCheckTerms(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal int64
+		tenor     int
+		rate      string
+		roi       string
+		fields    []string
+	}{
+		{"within limits", 5000000, 12, "0.15", "0.12", nil},
+		{"at the bounds", 1000000, 6, "0.20", "0.15", nil},
+		{"principal too small", 999999, 12, "0.15", "0.12", []string{"principal_amount"}},
+		{"principal too large", 10000001, 12, "0.15", "0.12", []string{"principal_amount"}},
+		{"tenor not offered", 5000000, 9, "0.15", "0.12", []string{"tenor_months"}},
+		{"rate out of range", 5000000, 12, "0.25", "0.12", []string{"rate"}},
+		{"roi exceeds rate", 5000000, 12, "0.11", "0.12", []string{"roi"}},
+		{"roi out of range and above rate", 5000000, 12, "0.15", "0.16", []string{"roi", "roi"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testProduct().CheckTerms(tt.principal, tt.tenor, MustParseDecimal(tt.rate), MustParseDecimal(tt.roi))
+			if tt.fields == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrLoanTermsOutOfRange) {
+				t.Fatalf("expected ErrLoanTermsOutOfRange, got %v", err)
+			}
+			var verr *ViolationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected *ViolationError, got %T", err)
+			}
+			if len(verr.Violations) != len(tt.fields) {
+				t.Fatalf("expected %d violations, got %d: %v", len(tt.fields), len(verr.Violations), err)
+			}
+			for i, field := range tt.fields {
+				if verr.Violations[i].Field != field {
+					t.Errorf("expected violation %d on %s, got %s", i, field, verr.Violations[i].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestLoanProductValidate(t *testing.T) {
+	p := testProduct()
+	if err := p.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	p.MinPrincipal = p.MaxPrincipal + 1
+	p.MinROI = MustParseDecimal("0.21")
+	p.MaxROI = p.MinROI
+
+	var verr *ViolationError
+	if err := p.Validate(); !errors.As(err, &verr) || !errors.Is(err, ErrInvalidLoanProduct) {
+		t.Fatalf("expected ErrInvalidLoanProduct, got %v", err)
+	}
+	if len(verr.Violations) != 2 {
+		t.Errorf("expected 2 violations, got %d: %v", len(verr.Violations), verr)
+	}
+}
@@ -5,7 +5,7 @@ import (
 )
 
 func TestNewLoan(t *testing.T) {
-	loan := NewLoan("borrower-123", 1000000, 0.15, 0.12)
+	loan := NewLoan("borrower-123", 1000000, MustParseDecimal("0.15"), MustParseDecimal("0.12"))
 
 	if loan.BorrowerID != "borrower-123" {
 		t.Errorf("expected borrower_id to be 'borrower-123', got '%s'", loan.BorrowerID)
@@ -144,3 +144,56 @@ func TestRemainingAmount(t *testing.T) {
 		t.Errorf("expected remaining amount to be 700000, got %d", loan.RemainingAmount())
 	}
 }
+
+func TestAvailableActions(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    LoanState
+		role     Role
+		expected LoanAction
+	}{
+		{"validator approves proposed", LoanStateProposed, RoleFieldValidator, LoanActionApprove},
+		{"investor cannot approve", LoanStateProposed, RoleInvestor, ""},
+		{"investor invests approved", LoanStateApproved, RoleInvestor, LoanActionInvest},
+		{"officer disburses invested", LoanStateInvested, RoleFieldOfficer, LoanActionDisburse},
+		{"admin disburses invested", LoanStateInvested, RoleAdmin, LoanActionDisburse},
+		{"nothing after disbursed", LoanStateDisbursed, RoleAdmin, ""},
+		{"no role", LoanStateProposed, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := &Loan{State: tt.state, PrincipalAmount: 1000000}
+			actions := loan.AvailableActions(tt.role)
+
+			if tt.expected == "" {
+				if len(actions) != 0 {
+					t.Errorf("expected no actions, got %v", actions)
+				}
+				return
+			}
+			if len(actions) != 1 || actions[0] != tt.expected {
+				t.Errorf("expected [%s], got %v", tt.expected, actions)
+			}
+		})
+	}
+}
+
+func TestRoleIsStaff(t *testing.T) {
+	tests := []struct {
+		role     Role
+		expected bool
+	}{
+		{RoleFieldValidator, true},
+		{RoleFieldOfficer, true},
+		{RoleAdmin, true},
+		{RoleInvestor, false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.IsStaff(); got != tt.expected {
+			t.Errorf("expected IsStaff(%q) to be %v, got %v", tt.role, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// earthRadiusMeters is the mean radius used for distances between points.
+const earthRadiusMeters = 6371008.8
+
+// GeoPoint is a position in decimal degrees.
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// DistanceMeters is the great-circle distance between p and q.
+func (p GeoPoint) DistanceMeters(q GeoPoint) float64 {
+	lat1, lat2 := p.Latitude*math.Pi/180, q.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (q.Longitude - p.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// PhotoMetadata is what a photo records about when, where and with what it
+// was taken. Fields the photo does not record are nil or empty.
+type PhotoMetadata struct {
+	CapturedAt  *time.Time
+	Location    *GeoPoint
+	DeviceMake  string
+	DeviceModel string
+}
+
+// PhotoPolicy limits how old a picture proof may be and how far from the
+// borrower it may have been taken. Zero disables a check. With an age limit,
+// a photo may also claim to be at most MaxClockSkew newer than now, allowing
+// for a device clock running slightly ahead.
+type PhotoPolicy struct {
+	MaxAge            time.Duration
+	MaxClockSkew      time.Duration
+	MaxDistanceMeters float64
+}
+
+// Check reports the ways photo, approved at now for loan, breaks the policy
+// as a *ViolationError wrapping ErrPictureProofRejected. A photo that does
+// not record what a check needs fails it; the distance check is skipped for
+// loans without a borrower location.
+func (p PhotoPolicy) Check(photo PhotoMetadata, loan *Loan, now time.Time) error {
+	var violations []Violation
+
+	if p.MaxAge > 0 {
+		switch {
+		case photo.CapturedAt == nil:
+			violations = append(violations, Violation{
+				Field: "picture_proof", Rule: "captured_at",
+				Message: "picture_proof must record when it was taken",
+			})
+		case photo.CapturedAt.After(now.Add(p.MaxClockSkew)):
+			violations = append(violations, Violation{
+				Field: "picture_proof", Rule: "captured_at_future", Param: p.MaxClockSkew.String(),
+				Message: "picture_proof must not have been taken after approval",
+			})
+		case now.Sub(*photo.CapturedAt) > p.MaxAge:
+			violations = append(violations, Violation{
+				Field: "picture_proof", Rule: "max_age", Param: p.MaxAge.String(),
+				Message: fmt.Sprintf("picture_proof must have been taken within %s of approval", p.MaxAge),
+			})
+		}
+	}
+
+	if p.MaxDistanceMeters > 0 && loan.BorrowerLocation != nil {
+		param := strconv.FormatFloat(p.MaxDistanceMeters, 'f', -1, 64)
+		switch {
+		case photo.Location == nil:
+			violations = append(violations, Violation{
+				Field: "picture_proof", Rule: "location",
+				Message: "picture_proof must record where it was taken",
+			})
+		case photo.Location.DistanceMeters(*loan.BorrowerLocation) > p.MaxDistanceMeters:
+			violations = append(violations, Violation{
+				Field: "picture_proof", Rule: "max_distance", Param: param,
+				Message: fmt.Sprintf("picture_proof must have been taken within %sm of the borrower's location", param),
+			})
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ViolationError{Err: ErrPictureProofRejected, Violations: violations}
+}
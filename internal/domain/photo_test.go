@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGeoPointDistanceMeters(t *testing.T) {
+	// Monas to Bundaran HI, Jakarta.
+	monas := GeoPoint{Latitude: -6.175392, Longitude: 106.827153}
+	bundaranHI := GeoPoint{Latitude: -6.194941, Longitude: 106.823036}
+
+	if d := monas.DistanceMeters(monas); d != 0 {
+		t.Errorf("expected 0m to the same point, got %f", d)
+	}
+	if d := monas.DistanceMeters(bundaranHI); math.Abs(d-2222) > 10 {
+		t.Errorf("expected about 2222m, got %f", d)
+	}
+}
+
+func TestPhotoPolicyCheck(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Hour)
+	old := now.Add(-72 * time.Hour)
+	skewed := now.Add(time.Minute)
+	future := now.Add(time.Hour)
+	home := GeoPoint{Latitude: -6.175392, Longitude: 106.827153}
+	near := GeoPoint{Latitude: -6.176, Longitude: 106.8275}
+	far := GeoPoint{Latitude: -6.194941, Longitude: 106.823036}
+
+	policy := PhotoPolicy{MaxAge: 24 * time.Hour, MaxClockSkew: 5 * time.Minute, MaxDistanceMeters: 500}
+
+	tests := []struct {
+		name     string
+		policy   PhotoPolicy
+		photo    PhotoMetadata
+		location *GeoPoint
+		rules    []string
+	}{
+		{"within limits", policy, PhotoMetadata{CapturedAt: &recent, Location: &near}, &home, nil},
+		{"disabled", PhotoPolicy{}, PhotoMetadata{}, &home, nil},
+		{"clock slightly ahead", policy, PhotoMetadata{CapturedAt: &skewed, Location: &near}, &home, nil},
+		{"taken in the future", policy, PhotoMetadata{CapturedAt: &future, Location: &near}, &home, []string{"captured_at_future"}},
+		{"too old", policy, PhotoMetadata{CapturedAt: &old, Location: &near}, &home, []string{"max_age"}},
+		{"too far", policy, PhotoMetadata{CapturedAt: &recent, Location: &far}, &home, []string{"max_distance"}},
+		{"no metadata", policy, PhotoMetadata{}, &home, []string{"captured_at", "location"}},
+		{"no borrower location", policy, PhotoMetadata{CapturedAt: &recent}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := &Loan{BorrowerLocation: tt.location}
+			err := tt.policy.Check(tt.photo, loan, now)
+			if tt.rules == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrPictureProofRejected) {
+				t.Fatalf("expected ErrPictureProofRejected, got %v", err)
+			}
+			var verr *ViolationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected *ViolationError, got %T", err)
+			}
+			if len(verr.Violations) != len(tt.rules) {
+				t.Fatalf("expected %d violations, got %d: %v", len(tt.rules), len(verr.Violations), err)
+			}
+			for i, rule := range tt.rules {
+				if verr.Violations[i].Rule != rule {
+					t.Errorf("expected violation %d to break %s, got %s", i, rule, verr.Violations[i].Rule)
+				}
+			}
+		})
+	}
+}
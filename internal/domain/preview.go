@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Preview is a small rendering of an uploaded file, kept in storage beside
+// it: a thumbnail of an image or a summary card of a PDF. SourceKey is the
+// storage key of the file it previews; PageCount is set for PDFs.
+type Preview struct {
+	SourceKey string
+	File      StoredFile
+	Width     int
+	Height    int
+	PageCount int
+	CreatedAt time.Time
+}
@@ -0,0 +1,77 @@
+package domain
+
+// Role is the caller's role as asserted by the API gateway.
+type Role string
+
+const (
+	RoleAdmin          Role = "admin"
+	RoleFieldValidator Role = "field_validator"
+	RoleInvestor       Role = "investor"
+	RoleFieldOfficer   Role = "field_officer"
+)
+
+// StaffRoles are the roles of Amartha staff, who may read and replace loan
+// documents.
+var StaffRoles = []Role{RoleFieldValidator, RoleFieldOfficer, RoleAdmin}
+
+// IsStaff reports whether r is one of StaffRoles.
+func (r Role) IsStaff() bool {
+	for _, staff := range StaffRoles {
+		if r == staff {
+			return true
+		}
+	}
+	return false
+}
+
+// LoanAction is a caller-initiated operation that moves a loan forward.
+type LoanAction string
+
+const (
+	LoanActionApprove  LoanAction = "approve"
+	LoanActionInvest   LoanAction = "invest"
+	LoanActionDisburse LoanAction = "disburse"
+)
+
+// TransitionActions names the action that moves a loan into each state.
+// Investing only reaches LoanStateInvested once the loan is fully funded.
+var TransitionActions = map[LoanState]LoanAction{
+	LoanStateApproved:  LoanActionApprove,
+	LoanStateInvested:  LoanActionInvest,
+	LoanStateDisbursed: LoanActionDisburse,
+}
+
+var rolePermissions = map[Role][]LoanAction{
+	RoleAdmin:          {LoanActionApprove, LoanActionInvest, LoanActionDisburse},
+	RoleFieldValidator: {LoanActionApprove},
+	RoleInvestor:       {LoanActionInvest},
+	RoleFieldOfficer:   {LoanActionDisburse},
+}
+
+func (r Role) Can(action LoanAction) bool {
+	for _, a := range rolePermissions[r] {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// AvailableActions returns the actions role may take on the loan in its
+// current state, derived from ValidTransitions.
+func (l *Loan) AvailableActions(role Role) []LoanAction {
+	next, ok := ValidTransitions[l.State]
+	if !ok {
+		return nil
+	}
+
+	action, ok := TransitionActions[next]
+	if !ok || !role.Can(action) {
+		return nil
+	}
+	if action == LoanActionInvest && (!l.CanAcceptInvestment() || l.RemainingAmount() <= 0) {
+		return nil
+	}
+
+	return []LoanAction{action}
+}
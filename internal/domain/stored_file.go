@@ -0,0 +1,12 @@
+package domain
+
+// StoredFile is an uploaded file kept in storage. ContentType is detected
+// from the file's content, not taken from the client. SHA256 is the hex
+// digest of the content; it is set on uploads and on documents, and empty
+// for documents stored before checksums were recorded.
+type StoredFile struct {
+	Key         string
+	ContentType string
+	Size        int64
+	SHA256      string
+}
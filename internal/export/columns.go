@@ -0,0 +1,51 @@
+package export
+
+import (
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+)
+
+// LoanColumns is the loan export schema. Columns may be added but existing
+// names, order and types must not change; see docs/export-schema.md.
+var LoanColumns = []Column[*domain.Loan]{
+	{Name: "id", Kind: KindString, Value: func(l *domain.Loan) any { return l.ID.String() }},
+	{Name: "borrower_id", Kind: KindString, Value: func(l *domain.Loan) any { return l.BorrowerID }},
+	{Name: "principal_amount", Kind: KindNumber, Value: func(l *domain.Loan) any { return l.PrincipalAmount }},
+	{Name: "rate", Kind: KindNumber, Value: func(l *domain.Loan) any { return l.Rate }},
+	{Name: "roi", Kind: KindNumber, Value: func(l *domain.Loan) any { return l.ROI }},
+	{Name: "state", Kind: KindString, Value: func(l *domain.Loan) any { return string(l.State) }},
+	{Name: "total_invested", Kind: KindNumber, Value: func(l *domain.Loan) any { return l.TotalInvested }},
+	{Name: "remaining_amount", Kind: KindNumber, Value: func(l *domain.Loan) any { return l.RemainingAmount() }},
+	{Name: "agreement_letter_url", Kind: KindString, Value: func(l *domain.Loan) any {
+		if l.AgreementLetterKey == nil {
+			return ""
+		}
+		return "/api/v1/loans/" + l.ID.String() + "/agreement-letter"
+	}},
+	{Name: "created_at", Kind: KindString, Value: func(l *domain.Loan) any { return formatTime(l.CreatedAt) }},
+	{Name: "updated_at", Kind: KindString, Value: func(l *domain.Loan) any { return formatTime(l.UpdatedAt) }},
+	{Name: "borrower_name", Kind: KindString, Value: func(l *domain.Loan) any { return l.BorrowerName }},
+	{Name: "notes", Kind: KindString, Value: func(l *domain.Loan) any { return l.Notes }},
+	{Name: "product_id", Kind: KindString, Value: func(l *domain.Loan) any {
+		if l.ProductID == nil {
+			return ""
+		}
+		return l.ProductID.String()
+	}},
+	{Name: "tenor_months", Kind: KindNumber, Value: func(l *domain.Loan) any { return int64(l.TenorMonths) }},
+}
+
+// InvestmentColumns is the investment export schema, with the same
+// stability guarantees as LoanColumns.
+var InvestmentColumns = []Column[*domain.Investment]{
+	{Name: "id", Kind: KindString, Value: func(i *domain.Investment) any { return i.ID.String() }},
+	{Name: "loan_id", Kind: KindString, Value: func(i *domain.Investment) any { return i.LoanID.String() }},
+	{Name: "investor_id", Kind: KindString, Value: func(i *domain.Investment) any { return i.InvestorID }},
+	{Name: "amount", Kind: KindNumber, Value: func(i *domain.Investment) any { return i.Amount }},
+	{Name: "created_at", Kind: KindString, Value: func(i *domain.Investment) any { return formatTime(i.CreatedAt) }},
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+)
+
+// csvFlushEvery bounds how many rows are buffered before being pushed to the
+// client.
+const csvFlushEvery = 500
+
+type csvWriter struct {
+	w       io.Writer
+	csv     *csv.Writer
+	record  []string
+	pending int
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: w, csv: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteHeader(names []string) error {
+	c.record = make([]string, len(names))
+	return c.csv.Write(names)
+}
+
+func (c *csvWriter) WriteRow(kinds []Kind, values []any) error {
+	for i, v := range values {
+		c.record[i] = formatValue(v)
+		if kinds[i] != KindNumber {
+			c.record[i] = neutralizeFormula(c.record[i])
+		}
+	}
+	if err := c.csv.Write(c.record); err != nil {
+		return err
+	}
+
+	c.pending++
+	if c.pending >= csvFlushEvery {
+		c.pending = 0
+		return c.flush()
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	return c.flush()
+}
+
+func (c *csvWriter) flush() error {
+	c.csv.Flush()
+	if err := c.csv.Error(); err != nil {
+		return err
+	}
+	if f, ok := c.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// neutralizeFormula prefixes text that a spreadsheet would read as a formula
+// with ', so client-supplied values such as notes are shown rather than run
+// when the file is opened. XLSX needs no such prefix; its text is written as
+// inline strings, which are never evaluated.
+func neutralizeFormula(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + s
+	}
+	return s
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case domain.Decimal:
+		return v.String()
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,120 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Kind tells spreadsheet writers how to type a cell.
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindNumber
+)
+
+// Column is one exported field of T. Value returns a string for KindString
+// columns and an int64, float64 or domain.Decimal for KindNumber columns.
+type Column[T any] struct {
+	Name  string
+	Kind  Kind
+	Value func(T) any
+}
+
+// RowWriter writes a table row by row. Close must be called to finish the
+// document; nothing after Close is guaranteed to be flushed before it.
+type RowWriter interface {
+	WriteHeader(names []string) error
+	WriteRow(kinds []Kind, values []any) error
+	Close() error
+}
+
+func NewRowWriter(format string, w io.Writer, sheetName string) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, sheetName), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func ContentType(format string) string {
+	switch format {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// SelectColumns returns the named columns in the requested order, or every
+// column when names is empty. A column may be named only once.
+func SelectColumns[T any](all []Column[T], names []string) ([]Column[T], error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	byName := make(map[string]Column[T], len(all))
+	for _, c := range all {
+		byName[c.Name] = c
+	}
+
+	selected := make([]Column[T], 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		c, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate column %q", name)
+		}
+		seen[name] = true
+		selected = append(selected, c)
+	}
+	return selected, nil
+}
+
+// Table streams rows of T through a RowWriter using a fixed column set.
+type Table[T any] struct {
+	writer  RowWriter
+	columns []Column[T]
+	kinds   []Kind
+	values  []any
+}
+
+func NewTable[T any](writer RowWriter, columns []Column[T]) (*Table[T], error) {
+	names := make([]string, len(columns))
+	kinds := make([]Kind, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+		kinds[i] = c.Kind
+	}
+
+	if err := writer.WriteHeader(names); err != nil {
+		return nil, err
+	}
+
+	return &Table[T]{
+		writer:  writer,
+		columns: columns,
+		kinds:   kinds,
+		values:  make([]any, len(columns)),
+	}, nil
+}
+
+func (t *Table[T]) Write(item T) error {
+	for i, c := range t.columns {
+		t.values[i] = c.Value(item)
+	}
+	return t.writer.WriteRow(t.kinds, t.values)
+}
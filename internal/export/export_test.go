@@ -0,0 +1,206 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+)
+
+type row struct {
+	name   string
+	amount int64
+	rate   domain.Decimal
+}
+
+var rowColumns = []Column[row]{
+	{Name: "name", Kind: KindString, Value: func(r row) any { return r.name }},
+	{Name: "amount", Kind: KindNumber, Value: func(r row) any { return r.amount }},
+	{Name: "rate", Kind: KindNumber, Value: func(r row) any { return r.rate }},
+}
+
+func columnNames[T any](columns []Column[T]) string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSelectColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    []string
+		expected string
+		wantErr  bool
+	}{
+		{"all by default", nil, "name,amount,rate", false},
+		{"requested order", []string{"rate", "name"}, "rate,name", false},
+		{"trims spaces", []string{" amount", "name "}, "amount,name", false},
+		{"unknown column", []string{"name", "ssn"}, "", true},
+		{"duplicate column", []string{"name", "amount", "name"}, "", true},
+		{"duplicate after trimming", []string{"name", " name"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := SelectColumns(rowColumns, tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && columnNames(columns) != tt.expected {
+				t.Errorf("expected columns %s, got %s", tt.expected, columnNames(columns))
+			}
+		})
+	}
+}
+
+func writeTable(t *testing.T, format string, rows []row) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, err := NewRowWriter(format, &buf, "Loans & Co")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := NewTable(writer, rowColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rows {
+		if err := table.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+var sampleRows = []row{
+	{"Siti, \"Ani\"", 1000000, domain.MustParseDecimal("0.15")},
+	{"<Budi>", 250000, domain.MustParseDecimal("0.1225")},
+}
+
+func TestCSVWriter(t *testing.T) {
+	got := string(writeTable(t, FormatCSV, sampleRows))
+	expected := "name,amount,rate\n\"Siti, \"\"Ani\"\"\",1000000,0.15\n<Budi>,250000,0.1225\n"
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+var formulaRows = []row{
+	{"=HYPERLINK(\"http://evil.example\",\"Click\")", 1, domain.MustParseDecimal("0.1")},
+	{"@SUM(A1:A2)", -2, domain.MustParseDecimal("-0.1")},
+	{"+1", 3, domain.MustParseDecimal("0.1")},
+	{"-1", 4, domain.MustParseDecimal("0.1")},
+	{"\tcmd", 5, domain.MustParseDecimal("0.1")},
+	{"\rcmd", 6, domain.MustParseDecimal("0.1")},
+	{"Budi = Ani", 7, domain.MustParseDecimal("0.1")},
+}
+
+func TestCSVWriterNeutralizesFormulas(t *testing.T) {
+	got := string(writeTable(t, FormatCSV, formulaRows))
+	expected := "name,amount,rate\n" +
+		"\"'=HYPERLINK(\"\"http://evil.example\"\",\"\"Click\"\")\",1,0.1\n" +
+		"'@SUM(A1:A2),-2,-0.1\n" +
+		"'+1,3,0.1\n" +
+		"'-1,4,0.1\n" +
+		"'\tcmd,5,0.1\n" +
+		"\"'\rcmd\",6,0.1\n" +
+		"Budi = Ani,7,0.1\n"
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestXLSXWriterKeepsFormulasAsText(t *testing.T) {
+	data := writeTable(t, FormatXLSX, formulaRows[:2])
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("expected a zip archive: %v", err)
+	}
+	f, err := archive.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(f)
+	sheet := string(body)
+
+	if strings.Contains(sheet, "<f>") {
+		t.Errorf("expected no formula cells, got %s", sheet)
+	}
+	for _, cell := range []string{
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">=HYPERLINK(&#34;http://evil.example&#34;,&#34;Click&#34;)</t></is></c>`,
+		`<c r="A3" t="inlineStr"><is><t xml:space="preserve">@SUM(A1:A2)</t></is></c>`,
+		`<c r="B3"><v>-2</v></c>`,
+	} {
+		if !strings.Contains(sheet, cell) {
+			t.Errorf("expected sheet to contain %s", cell)
+		}
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	data := writeTable(t, FormatXLSX, sampleRows)
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("expected a zip archive: %v", err)
+	}
+	parts := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(r)
+		r.Close()
+		parts[f.Name] = string(body)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels", "xl/workbook.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("expected part %s", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Loans &amp; Co"`) {
+		t.Errorf("expected an escaped sheet name, got %s", parts["xl/workbook.xml"])
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, cell := range []string{
+		`<c r="A1" t="inlineStr"><is><t xml:space="preserve">name</t></is></c>`,
+		`<c r="B1" t="inlineStr"><is><t xml:space="preserve">amount</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">Siti, &#34;Ani&#34;</t></is></c>`,
+		`<c r="B2"><v>1000000</v></c>`,
+		`<c r="C3"><v>0.1225</v></c>`,
+		`<c r="A3" t="inlineStr"><is><t xml:space="preserve">&lt;Budi&gt;</t></is></c>`,
+	} {
+		if !strings.Contains(sheet, cell) {
+			t.Errorf("expected sheet to contain %s", cell)
+		}
+	}
+	if !strings.HasSuffix(sheet, "</sheetData></worksheet>") {
+		t.Errorf("expected a closed worksheet, got %s", sheet[len(sheet)-40:])
+	}
+}
+
+func TestNewRowWriterUnknownFormat(t *testing.T) {
+	if _, err := NewRowWriter("ods", io.Discard, "Loans"); err == nil {
+		t.Error("expected error for an unsupported format")
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for i, expected := range tests {
+		if got := columnName(i); got != expected {
+			t.Errorf("expected column %d to be %s, got %s", i, expected, got)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// xlsxFlushEvery bounds how many rows are compressed before being pushed to
+// the client.
+const xlsxFlushEvery = 500
+
+// xlsxWriter writes a single-sheet workbook directly to w. The sheet is
+// streamed as one zip entry with inline strings, so memory use does not grow
+// with the number of rows.
+type xlsxWriter struct {
+	w         io.Writer
+	zip       *zip.Writer
+	sheet     *bufio.Writer
+	sheetName string
+	row       int
+	err       error
+}
+
+func newXLSXWriter(w io.Writer, sheetName string) *xlsxWriter {
+	return &xlsxWriter{w: w, zip: zip.NewWriter(w), sheetName: sheetName}
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`</Relationships>`
+
+func (x *xlsxWriter) WriteHeader(names []string) error {
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/workbook.xml", x.workbook()},
+	}
+	for _, part := range parts {
+		f, err := x.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return err
+		}
+	}
+
+	f, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = bufio.NewWriter(f)
+	x.writeString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	kinds := make([]Kind, len(names))
+	values := make([]any, len(names))
+	for i, name := range names {
+		values[i] = name
+	}
+	return x.WriteRow(kinds, values)
+}
+
+func (x *xlsxWriter) WriteRow(kinds []Kind, values []any) error {
+	x.row++
+	rowRef := strconv.Itoa(x.row)
+
+	x.writeString(`<row r="` + rowRef + `">`)
+	for i, v := range values {
+		ref := columnName(i) + rowRef
+		if kinds[i] == KindNumber && v != nil {
+			x.writeString(`<c r="` + ref + `"><v>` + formatValue(v) + `</v></c>`)
+			continue
+		}
+		x.writeString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+		x.writeEscaped(formatValue(v))
+		x.writeString(`</t></is></c>`)
+	}
+	x.writeString(`</row>`)
+
+	if x.err == nil && x.row%xlsxFlushEvery == 0 {
+		x.flush()
+	}
+	return x.err
+}
+
+func (x *xlsxWriter) Close() error {
+	x.writeString(`</sheetData></worksheet>`)
+	if x.err != nil {
+		return x.err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+func (x *xlsxWriter) workbook() string {
+	var name strings.Builder
+	xml.EscapeText(&name, []byte(x.sheetName))
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+}
+
+func (x *xlsxWriter) flush() {
+	if x.err = x.sheet.Flush(); x.err != nil {
+		return
+	}
+	if x.err = x.zip.Flush(); x.err != nil {
+		return
+	}
+	if f, ok := x.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (x *xlsxWriter) writeString(s string) {
+	if x.err == nil {
+		_, x.err = x.sheet.WriteString(s)
+	}
+}
+
+func (x *xlsxWriter) writeEscaped(s string) {
+	if x.err == nil {
+		x.err = xml.EscapeText(x.sheet, []byte(s))
+	}
+}
+
+// columnName converts a zero-based column index to its A1 letters.
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
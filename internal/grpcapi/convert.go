@@ -0,0 +1,107 @@
+package grpcapi
+
+import (
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	loanv1 "github.com/agunghallmanmaliki/amartha/pkg/pb/loan/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var loanStates = map[domain.LoanState]loanv1.LoanState{
+	domain.LoanStateProposed:  loanv1.LoanState_LOAN_STATE_PROPOSED,
+	domain.LoanStateApproved:  loanv1.LoanState_LOAN_STATE_APPROVED,
+	domain.LoanStateInvested:  loanv1.LoanState_LOAN_STATE_INVESTED,
+	domain.LoanStateDisbursed: loanv1.LoanState_LOAN_STATE_DISBURSED,
+}
+
+func toProtoLoan(loan *domain.Loan) *loanv1.Loan {
+	var productID *string
+	if loan.ProductID != nil {
+		id := loan.ProductID.String()
+		productID = &id
+	}
+
+	return &loanv1.Loan{
+		Id:                 loan.ID.String(),
+		BorrowerId:         loan.BorrowerID,
+		BorrowerName:       loan.BorrowerName,
+		Notes:              loan.Notes,
+		PrincipalAmount:    loan.PrincipalAmount,
+		Rate:               loan.Rate.String(),
+		Roi:                loan.ROI.String(),
+		ProductId:          productID,
+		TenorMonths:        int32(loan.TenorMonths),
+		State:              loanStates[loan.State],
+		AgreementLetterUrl: agreementLetterURL(loan),
+		TotalInvested:      loan.TotalInvested,
+		RemainingAmount:    loan.RemainingAmount(),
+		CreatedAt:          timestamppb.New(loan.CreatedAt),
+		UpdatedAt:          timestamppb.New(loan.UpdatedAt),
+		BorrowerLocation:   toProtoGeoPoint(loan.BorrowerLocation),
+		AgreementSignature: toProtoAgreementSignature(loan.AgreementSignature),
+	}
+}
+
+// agreementLetterURL returns the REST path that serves the loan's agreement
+// letter, or nil before disbursement.
+func agreementLetterURL(loan *domain.Loan) *string {
+	if loan.AgreementLetterKey == nil {
+		return nil
+	}
+	path := "/api/v1/loans/" + loan.ID.String() + "/agreement-letter"
+	return &path
+}
+
+func toProtoAgreementSignature(s *domain.AgreementSignature) *loanv1.AgreementSignature {
+	if s == nil {
+		return nil
+	}
+	return &loanv1.AgreementSignature{
+		Sha256:            s.SHA256,
+		Signature:         s.Signature,
+		SignerFingerprint: s.SignerFingerprint,
+		SignedAt:          timestamppb.New(s.SignedAt),
+	}
+}
+
+func toProtoGeoPoint(p *domain.GeoPoint) *loanv1.GeoPoint {
+	if p == nil {
+		return nil
+	}
+	return &loanv1.GeoPoint{Latitude: p.Latitude, Longitude: p.Longitude}
+}
+
+func toProtoLoans(loans []*domain.Loan) []*loanv1.Loan {
+	result := make([]*loanv1.Loan, len(loans))
+	for i, loan := range loans {
+		result[i] = toProtoLoan(loan)
+	}
+	return result
+}
+
+func toProtoInvestment(inv *domain.Investment) *loanv1.Investment {
+	return &loanv1.Investment{
+		Id:         inv.ID.String(),
+		LoanId:     inv.LoanID.String(),
+		InvestorId: inv.InvestorID,
+		Amount:     inv.Amount,
+		CreatedAt:  timestamppb.New(inv.CreatedAt),
+	}
+}
+
+func toProtoInvestments(investments []*domain.Investment) []*loanv1.Investment {
+	result := make([]*loanv1.Investment, len(investments))
+	for i, inv := range investments {
+		result[i] = toProtoInvestment(inv)
+	}
+	return result
+}
+
+// fromProtoState returns nil for LOAN_STATE_UNSPECIFIED, meaning any state.
+func fromProtoState(state loanv1.LoanState) *domain.LoanState {
+	for s, ps := range loanStates {
+		if ps == state {
+			return &s
+		}
+	}
+	return nil
+}
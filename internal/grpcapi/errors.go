@@ -0,0 +1,126 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain identifies this service in google.rpc.ErrorInfo details.
+const errorDomain = "loan.amartha"
+
+// serviceError maps domain errors returned by LoanService to gRPC statuses.
+// Reasons match the problem codes the REST API returns for the same error.
+func serviceError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrLoanNotFound):
+		return newStatus(codes.NotFound, "NOT_FOUND", "Loan not found")
+	case errors.Is(err, domain.ErrInvalidStateTransition):
+		return newStatus(codes.FailedPrecondition, "INVALID_STATE_TRANSITION", "Invalid state transition")
+	case errors.Is(err, domain.ErrInvestmentExceedsLimit):
+		return newStatus(codes.FailedPrecondition, "INVESTMENT_EXCEEDS_LIMIT", "Investment amount exceeds remaining principal")
+	case errors.Is(err, domain.ErrLoanNotApproved):
+		return newStatus(codes.FailedPrecondition, "LOAN_NOT_APPROVED", "Loan must be in approved state to accept investments")
+	case errors.Is(err, domain.ErrLoanNotInvested):
+		return newStatus(codes.FailedPrecondition, "LOAN_NOT_INVESTED", "Loan must be in invested state to disburse")
+	case errors.Is(err, domain.ErrLoanAlreadyApproved):
+		return newStatus(codes.FailedPrecondition, "LOAN_ALREADY_APPROVED", "Loan is already approved")
+	case errors.Is(err, domain.ErrLoanAlreadyDisbursed):
+		return newStatus(codes.FailedPrecondition, "LOAN_ALREADY_DISBURSED", "Loan is already disbursed")
+	case errors.Is(err, domain.ErrInvalidAmount):
+		return newStatus(codes.InvalidArgument, "INVALID_AMOUNT", "Amount must be greater than zero")
+	case errors.Is(err, domain.ErrApprovalNotFound):
+		return newStatus(codes.NotFound, "NOT_FOUND", "Loan has not been approved")
+	case errors.Is(err, domain.ErrDisbursementNotFound):
+		return newStatus(codes.NotFound, "NOT_FOUND", "Loan has not been disbursed")
+	case errors.Is(err, domain.ErrAgreementLetterNotFound):
+		return newStatus(codes.NotFound, "NOT_FOUND", "Loan has no agreement letter yet")
+	case errors.Is(err, domain.ErrDocumentNotFound):
+		return newStatus(codes.NotFound, "NOT_FOUND", "Document not found")
+	case errors.Is(err, domain.ErrLoanProductNotFound):
+		return newStatus(codes.NotFound, "NOT_FOUND", "Loan product not found")
+	case errors.Is(err, domain.ErrLoanProductUnavailable):
+		return violationStatus(codes.FailedPrecondition, "LOAN_PRODUCT_UNAVAILABLE", "Loan product does not exist or is not active", err)
+	case errors.Is(err, domain.ErrLoanTermsOutOfRange):
+		return violationStatus(codes.FailedPrecondition, "LOAN_TERMS_OUT_OF_RANGE", "Loan terms are outside the product's limits", err)
+	case errors.Is(err, domain.ErrFileTooLarge):
+		return violationStatus(codes.InvalidArgument, "FILE_TOO_LARGE", "File exceeds the maximum size", err)
+	case errors.Is(err, domain.ErrUnsupportedFileType):
+		return violationStatus(codes.InvalidArgument, "UNSUPPORTED_FILE_TYPE", "File type is not allowed", err)
+	case errors.Is(err, domain.ErrFileExtensionMismatch):
+		return violationStatus(codes.InvalidArgument, "FILE_EXTENSION_MISMATCH", "File extension does not match its content", err)
+	case errors.Is(err, domain.ErrPictureProofRejected):
+		return violationStatus(codes.FailedPrecondition, "PICTURE_PROOF_REJECTED", "Picture proof does not meet the photo policy", err)
+	case errors.Is(err, domain.ErrFileInfected):
+		return violationStatus(codes.InvalidArgument, "FILE_INFECTED", "File contains malware", err)
+	case errors.Is(err, domain.ErrScanUnavailable):
+		return newStatus(codes.Unavailable, "SCAN_UNAVAILABLE", "File could not be scanned for malware; try again later")
+	default:
+		return newStatus(codes.Internal, "INTERNAL_ERROR", "An internal error occurred")
+	}
+}
+
+// newStatus builds a status carrying an ErrorInfo with the given reason.
+func newStatus(code codes.Code, reason, message string) error {
+	st, err := status.New(code, message).WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorDomain,
+	})
+	if err != nil {
+		return status.Error(code, message)
+	}
+	return st.Err()
+}
+
+// violationStatus is newStatus with the rules listed in a
+// *domain.ViolationError attached as a BadRequest detail.
+func violationStatus(code codes.Code, reason, message string, err error) error {
+	var verr *domain.ViolationError
+	if !errors.As(err, &verr) {
+		return newStatus(code, reason, message)
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(verr.Violations))
+	for i, v := range verr.Violations {
+		violations[i] = &errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Message}
+	}
+
+	st, detailErr := status.New(code, message).WithDetails(
+		&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain},
+		&errdetails.BadRequest{FieldViolations: violations},
+	)
+	if detailErr != nil {
+		return status.Error(code, message)
+	}
+	return st.Err()
+}
+
+// invalidArgument reports field-level validation failures as a BadRequest
+// detail, mirroring the errors array of REST validation problems.
+func invalidArgument(fieldErrors ...httperror.FieldError) error {
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       fe.Field,
+			Description: fe.Message,
+		})
+	}
+
+	st, err := status.New(codes.InvalidArgument, "Request validation failed").WithDetails(
+		&errdetails.ErrorInfo{Reason: "VALIDATION_ERROR", Domain: errorDomain},
+		&errdetails.BadRequest{FieldViolations: violations},
+	)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "Request validation failed")
+	}
+	return st.Err()
+}
+
+// validationError converts validator output into an InvalidArgument status.
+func validationError(err error) error {
+	return invalidArgument(httperror.FromValidator(err).Errors...)
+}
@@ -0,0 +1,98 @@
+package grpcapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServiceError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{"not found", domain.ErrLoanNotFound, codes.NotFound, "NOT_FOUND"},
+		{"wrapped not found", fmt.Errorf("failed to get loan: %w", domain.ErrLoanNotFound), codes.NotFound, "NOT_FOUND"},
+		{"approval not found", domain.ErrApprovalNotFound, codes.NotFound, "NOT_FOUND"},
+		{"disbursement not found", domain.ErrDisbursementNotFound, codes.NotFound, "NOT_FOUND"},
+		{"agreement letter not found", domain.ErrAgreementLetterNotFound, codes.NotFound, "NOT_FOUND"},
+		{"document not found", domain.ErrDocumentNotFound, codes.NotFound, "NOT_FOUND"},
+		{"loan product not found", fmt.Errorf("failed to get product: %w", domain.ErrLoanProductNotFound), codes.NotFound, "NOT_FOUND"},
+		{"invalid transition", domain.ErrInvalidStateTransition, codes.FailedPrecondition, "INVALID_STATE_TRANSITION"},
+		{"exceeds limit", domain.ErrInvestmentExceedsLimit, codes.FailedPrecondition, "INVESTMENT_EXCEEDS_LIMIT"},
+		{"not approved", domain.ErrLoanNotApproved, codes.FailedPrecondition, "LOAN_NOT_APPROVED"},
+		{"not invested", domain.ErrLoanNotInvested, codes.FailedPrecondition, "LOAN_NOT_INVESTED"},
+		{"already approved", domain.ErrLoanAlreadyApproved, codes.FailedPrecondition, "LOAN_ALREADY_APPROVED"},
+		{"already disbursed", domain.ErrLoanAlreadyDisbursed, codes.FailedPrecondition, "LOAN_ALREADY_DISBURSED"},
+		{"invalid amount", domain.ErrInvalidAmount, codes.InvalidArgument, "INVALID_AMOUNT"},
+		{"picture proof rejected", &domain.ViolationError{Err: domain.ErrPictureProofRejected}, codes.FailedPrecondition, "PICTURE_PROOF_REJECTED"},
+		{"file infected", &domain.ViolationError{Err: domain.ErrFileInfected}, codes.InvalidArgument, "FILE_INFECTED"},
+		{"scan unavailable", fmt.Errorf("%w: connection refused", domain.ErrScanUnavailable), codes.Unavailable, "SCAN_UNAVAILABLE"},
+		{"unknown", errors.New("boom"), codes.Internal, "INTERNAL_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(serviceError(tt.err))
+			if st.Code() != tt.code {
+				t.Errorf("expected code %v, got %v", tt.code, st.Code())
+			}
+
+			var reason string
+			for _, d := range st.Details() {
+				if info, ok := d.(*errdetails.ErrorInfo); ok {
+					reason = info.Reason
+				}
+			}
+			if reason != tt.reason {
+				t.Errorf("expected reason %s, got %s", tt.reason, reason)
+			}
+		})
+	}
+}
+
+func TestChunkReader(t *testing.T) {
+	chunks := [][]byte{[]byte("hello "), {}, []byte("world")}
+	recv := func() ([]byte, error) {
+		if len(chunks) == 0 {
+			return nil, io.EOF
+		}
+		chunk := chunks[0]
+		chunks = chunks[1:]
+		return chunk, nil
+	}
+
+	reader := &chunkReader{recv: recv}
+	if empty, err := reader.empty(); err != nil || empty {
+		t.Fatalf("expected non-empty stream, got empty=%v err=%v", empty, err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, reader); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "hello world" {
+		t.Errorf("expected %q, got %q", "hello world", buf.String())
+	}
+}
+
+func TestChunkReaderLimit(t *testing.T) {
+	recv := func() ([]byte, error) {
+		return make([]byte, 64), nil
+	}
+
+	reader := &chunkReader{recv: recv, limit: 100}
+	_, err := io.Copy(io.Discard, reader)
+	if !errors.Is(err, errFileTooLarge) {
+		t.Errorf("expected errFileTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"net/netip"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryInterceptor carries the request id and actor from incoming metadata
+// into the context, recovers panics and logs each call, like the HTTP
+// RequestID, Actor, Recovery and Logger middleware. As over HTTP, only
+// trusted proxies may forward the actor.
+func UnaryInterceptor(trusted requestctx.TrustedProxies, logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		ctx, err = withRequestInfo(ctx, info.FullMethod, trusted)
+		start := time.Now()
+
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(ctx, logger, p)
+			}
+			logCall(ctx, logger, info.FullMethod, start, err)
+		}()
+
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor is the streaming counterpart of UnaryInterceptor.
+func StreamInterceptor(trusted requestctx.TrustedProxies, logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx, err := withRequestInfo(ss.Context(), info.FullMethod, trusted)
+		start := time.Now()
+
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(ctx, logger, p)
+			}
+			logCall(ctx, logger, info.FullMethod, start, err)
+		}()
+
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withRequestInfo stores requestctx.Info for the call and echoes the request
+// id in the response header metadata. Calls carrying actor metadata from a
+// peer that is not a trusted proxy are refused with PermissionDenied; the
+// returned context carries the request info either way.
+func withRequestInfo(ctx context.Context, method string, trusted requestctx.TrustedProxies) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := firstValue(md, requestctx.HeaderRequestID)
+	if !requestctx.ValidRequestID(requestID) {
+		requestID = uuid.New().String()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(requestctx.HeaderRequestID, requestID))
+
+	info := &requestctx.Info{RequestID: requestID, Route: method}
+	ctx = requestctx.NewContext(ctx, info)
+
+	addr := peerAddr(ctx)
+	if !trusted.Trusts(addr) {
+		if len(md.Get(requestctx.HeaderActorID)) > 0 || len(md.Get(requestctx.HeaderActorRole)) > 0 {
+			return ctx, newStatus(codes.PermissionDenied, "FORBIDDEN", "Actor metadata is only accepted from trusted proxies")
+		}
+		if addr.IsValid() {
+			info.ClientIP = addr.String()
+		}
+		return ctx, nil
+	}
+
+	info.ActorID = firstValue(md, requestctx.HeaderActorID)
+	info.ActorRole = firstValue(md, requestctx.HeaderActorRole)
+	info.ClientIP = trusted.ClientIP(addr, md.Get(requestctx.HeaderForwardedFor)).String()
+	return ctx, nil
+}
+
+// peerAddr returns the caller's address, or the zero Addr if the transport
+// does not give one.
+func peerAddr(ctx context.Context) netip.Addr {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return netip.Addr{}
+	}
+	addrPort, err := netip.ParseAddrPort(p.Addr.String())
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addrPort.Addr().Unmap()
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(strings.ToLower(key)); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func recovered(ctx context.Context, logger *slog.Logger, p any) error {
+	logger.ErrorContext(ctx, "panic recovered",
+		"error", p,
+		"stack", string(debug.Stack()),
+	)
+	return status.Error(codes.Internal, "An internal error occurred")
+}
+
+func logCall(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error) {
+	logger.InfoContext(ctx, "rpc completed",
+		"method", method,
+		"code", status.Code(err).String(),
+		"duration", time.Since(start).String(),
+	)
+}
+
+// serverStream overrides the stream context so handlers see request info.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,52 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestWithRequestInfo(t *testing.T) {
+	trusted, err := requestctx.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		peer         string
+		md           metadata.MD
+		wantCode     codes.Code
+		wantRole     string
+		wantClientIP string
+	}{
+		{"trusted proxy", "10.0.0.5", metadata.Pairs("x-actor-role", "admin", "x-forwarded-for", "203.0.113.9"), codes.OK, "admin", "203.0.113.9"},
+		{"untrusted peer", "203.0.113.9", metadata.Pairs("x-request-id", "req-1"), codes.OK, "", "203.0.113.9"},
+		{"spoofed role", "203.0.113.9", metadata.Pairs("x-actor-role", "admin"), codes.PermissionDenied, "", "203.0.113.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(tt.peer), Port: 41000}})
+
+			ctx, err := withRequestInfo(ctx, "/loan.v1.LoanService/CreateLoanProduct", trusted)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("expected code %v, got %v", tt.wantCode, code)
+			}
+			info := requestctx.FromContext(ctx)
+			if info == nil || info.RequestID == "" {
+				t.Fatalf("expected request info, got %+v", info)
+			}
+			if tt.wantCode == codes.OK && (info.ActorRole != tt.wantRole || info.ClientIP != tt.wantClientIP) {
+				t.Errorf("expected role %q client %q, got %+v", tt.wantRole, tt.wantClientIP, info)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/photo"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/internal/scan"
+	"github.com/agunghallmanmaliki/amartha/internal/service"
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+	"github.com/agunghallmanmaliki/amartha/internal/upload"
+	"github.com/agunghallmanmaliki/amartha/internal/validation"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+	loanv1 "github.com/agunghallmanmaliki/amartha/pkg/pb/loan/v1"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LoanServer implements loanv1.LoanServiceServer on top of the same
+// LoanService and storage used by the REST handlers.
+type LoanServer struct {
+	loanv1.UnimplementedLoanServiceServer
+
+	loanService *service.LoanService
+	storage     storage.Storage
+	uploads     *scan.Gate
+	validator   *validator.Validate
+	logger      *slog.Logger
+
+	pictureProof    upload.Policy
+	signedAgreement upload.Policy
+}
+
+func NewLoanServer(loanService *service.LoanService, storage storage.Storage, uploads *scan.Gate, pictureProof, signedAgreement upload.Policy, logger *slog.Logger) *LoanServer {
+	return &LoanServer{
+		loanService:     loanService,
+		storage:         storage,
+		uploads:         uploads,
+		validator:       validation.New(),
+		logger:          logger,
+		pictureProof:    pictureProof,
+		signedAgreement: signedAgreement,
+	}
+}
+
+func (s *LoanServer) CreateLoan(ctx context.Context, req *loanv1.CreateLoanRequest) (*loanv1.Loan, error) {
+	rate, err := parseDecimal("rate", req.GetRate())
+	if err != nil {
+		return nil, err
+	}
+	roi, err := parseDecimal("roi", req.GetRoi())
+	if err != nil {
+		return nil, err
+	}
+
+	input := validation.Loan{
+		BorrowerID:      req.GetBorrowerId(),
+		BorrowerName:    req.GetBorrowerName(),
+		Notes:           req.GetNotes(),
+		PrincipalAmount: req.GetPrincipalAmount(),
+		Rate:            rate,
+		ROI:             roi,
+		ProductID:       req.GetProductId(),
+		TenorMonths:     int(req.GetTenorMonths()),
+	}
+	if location := req.GetBorrowerLocation(); location != nil {
+		input.BorrowerLocation = &validation.Location{Latitude: &location.Latitude, Longitude: &location.Longitude}
+	}
+	if err := s.validator.Struct(input); err != nil {
+		return nil, validationError(err)
+	}
+
+	loan, err := s.loanService.CreateLoan(ctx, input.ToInput())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return toProtoLoan(loan), nil
+}
+
+func (s *LoanServer) GetLoan(ctx context.Context, req *loanv1.GetLoanRequest) (*loanv1.Loan, error) {
+	loanID, err := parseLoanID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	loan, err := s.loanService.GetLoan(ctx, loanID)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return toProtoLoan(loan), nil
+}
+
+func (s *LoanServer) ListLoans(ctx context.Context, req *loanv1.ListLoansRequest) (*loanv1.ListLoansResponse, error) {
+	filter := repository.LoanFilter{
+		Limit:  10,
+		Offset: 0,
+		State:  fromProtoState(req.GetState()),
+	}
+	if req.GetLimit() > 0 {
+		filter.Limit = int(req.GetLimit())
+	}
+	if req.GetOffset() > 0 {
+		filter.Offset = int(req.GetOffset())
+	}
+
+	loans, total, err := s.loanService.ListLoans(ctx, filter)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &loanv1.ListLoansResponse{
+		Loans:  toProtoLoans(loans),
+		Total:  total,
+		Limit:  int32(filter.Limit),
+		Offset: int32(filter.Offset),
+	}, nil
+}
+
+func (s *LoanServer) ApproveLoan(stream loanv1.LoanService_ApproveLoanServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	meta := first.GetMetadata()
+	if meta == nil {
+		return newStatus(codes.InvalidArgument, "INVALID_STREAM", "First message must carry approval metadata")
+	}
+
+	loanID, err := parseLoanID(meta.GetLoanId())
+	if err != nil {
+		return err
+	}
+	if meta.GetFieldValidatorId() == "" {
+		return invalidArgument(httperror.Required("field_validator_id"))
+	}
+	if meta.GetPictureProof() == nil {
+		return invalidArgument(httperror.Required("picture_proof"))
+	}
+
+	ctx := stream.Context()
+	pictureProof, file, err := s.saveUpload(ctx, s.pictureProof, meta.GetPictureProof().GetFilename(), func() ([]byte, error) {
+		msg, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if msg.GetMetadata() != nil {
+			return nil, errUnexpectedMetadata
+		}
+		return msg.GetChunk(), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	loan, err := s.loanService.ApproveLoan(ctx, loanID, meta.GetFieldValidatorId(), pictureProof, photo.Metadata(file.Head()))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return stream.SendAndClose(toProtoLoan(loan))
+}
+
+func (s *LoanServer) AddInvestment(ctx context.Context, req *loanv1.AddInvestmentRequest) (*loanv1.AddInvestmentResponse, error) {
+	loanID, err := parseLoanID(req.GetLoanId())
+	if err != nil {
+		return nil, err
+	}
+
+	input := validation.Investment{
+		InvestorID: req.GetInvestorId(),
+		Amount:     req.GetAmount(),
+	}
+	if err := s.validator.Struct(input); err != nil {
+		return nil, validationError(err)
+	}
+
+	loan, investment, err := s.loanService.AddInvestment(ctx, loanID, input.InvestorID, input.Amount)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &loanv1.AddInvestmentResponse{
+		Loan:       toProtoLoan(loan),
+		Investment: toProtoInvestment(investment),
+	}, nil
+}
+
+func (s *LoanServer) ListInvestments(ctx context.Context, req *loanv1.ListInvestmentsRequest) (*loanv1.ListInvestmentsResponse, error) {
+	loanID, err := parseLoanID(req.GetLoanId())
+	if err != nil {
+		return nil, err
+	}
+
+	investments, err := s.loanService.ListInvestments(ctx, loanID)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &loanv1.ListInvestmentsResponse{Investments: toProtoInvestments(investments)}, nil
+}
+
+func (s *LoanServer) DisburseLoan(stream loanv1.LoanService_DisburseLoanServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	meta := first.GetMetadata()
+	if meta == nil {
+		return newStatus(codes.InvalidArgument, "INVALID_STREAM", "First message must carry disbursement metadata")
+	}
+
+	loanID, err := parseLoanID(meta.GetLoanId())
+	if err != nil {
+		return err
+	}
+	if meta.GetFieldOfficerId() == "" {
+		return invalidArgument(httperror.Required("field_officer_id"))
+	}
+	if meta.GetSignedAgreement() == nil {
+		return invalidArgument(httperror.Required("signed_agreement"))
+	}
+
+	ctx := stream.Context()
+	signedAgreement, _, err := s.saveUpload(ctx, s.signedAgreement, meta.GetSignedAgreement().GetFilename(), func() ([]byte, error) {
+		msg, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if msg.GetMetadata() != nil {
+			return nil, errUnexpectedMetadata
+		}
+		return msg.GetChunk(), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	loan, err := s.loanService.DisburseLoan(ctx, loanID, meta.GetFieldOfficerId(), signedAgreement)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return stream.SendAndClose(toProtoLoan(loan))
+}
+
+// saveUpload checks the remaining chunks of an upload against policy and
+// streams them into storage, returning the saved file for callers that read
+// its metadata.
+func (s *LoanServer) saveUpload(ctx context.Context, policy upload.Policy, filename string, recv func() ([]byte, error)) (domain.StoredFile, *upload.File, error) {
+	reader := &chunkReader{recv: recv, limit: policy.MaxSize}
+
+	empty, err := reader.empty()
+	if err != nil {
+		return domain.StoredFile{}, nil, uploadError(ctx, policy, err)
+	}
+	if empty {
+		return domain.StoredFile{}, nil, invalidArgument(httperror.Required(policy.Field))
+	}
+
+	file, err := policy.Open(filename, reader)
+	if err != nil {
+		return domain.StoredFile{}, nil, uploadError(ctx, policy, err)
+	}
+
+	key, err := s.uploads.Save(ctx, policy.Field, file.Name(), file)
+	if err != nil {
+		if file.Err() != nil {
+			err = file.Err()
+		}
+		s.logger.ErrorContext(ctx, "failed to save upload", "field", policy.Field, "error", err)
+		return domain.StoredFile{}, nil, uploadError(ctx, policy, err)
+	}
+	return file.Stored(key), file, nil
+}
+
+func uploadError(ctx context.Context, policy upload.Policy, err error) error {
+	switch {
+	case errors.Is(err, errFileTooLarge):
+		return serviceError(policy.TooLarge())
+	case errors.Is(err, domain.ErrFileTooLarge),
+		errors.Is(err, domain.ErrUnsupportedFileType),
+		errors.Is(err, domain.ErrFileExtensionMismatch),
+		errors.Is(err, domain.ErrFileInfected),
+		errors.Is(err, domain.ErrScanUnavailable):
+		return serviceError(err)
+	case errors.Is(err, errUnexpectedMetadata):
+		return newStatus(codes.InvalidArgument, "INVALID_STREAM", "Metadata must only be sent in the first message")
+	case ctx.Err() != nil:
+		return status.FromContextError(ctx.Err()).Err()
+	default:
+		return newStatus(codes.Internal, "STORAGE_ERROR", "Failed to save file")
+	}
+}
+
+func parseLoanID(id string) (uuid.UUID, error) {
+	loanID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, newStatus(codes.InvalidArgument, "INVALID_ID", "Invalid loan ID format")
+	}
+	return loanID, nil
+}
+
+// parseDecimal parses a decimal string field. An empty value parses as zero
+// so the request validator reports it as missing, as it does for REST.
+func parseDecimal(field, value string) (domain.Decimal, error) {
+	if value == "" {
+		return domain.Decimal{}, nil
+	}
+	v, err := domain.ParseDecimal(value)
+	if err != nil {
+		return domain.Decimal{}, invalidArgument(httperror.FieldError{
+			Field:   field,
+			Rule:    "decimal",
+			Message: field + " must be a decimal number with at most 4 fractional digits",
+		})
+	}
+	return v, nil
+}
@@ -0,0 +1,35 @@
+package grpcapi
+
+import (
+	"log/slog"
+
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	loanv1 "github.com/agunghallmanmaliki/amartha/pkg/pb/loan/v1"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer returns a gRPC server exposing the loan service, the standard
+// health service and reflection. Calls are traced with the global OpenTelemetry
+// provider. The returned health server should be switched to NOT_SERVING
+// (Shutdown) before the server is stopped. Only trustedProxies may forward
+// the caller's identity.
+func NewServer(loanServer *LoanServer, trustedProxies requestctx.TrustedProxies, logger *slog.Logger) (*grpc.Server, *health.Server) {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(UnaryInterceptor(trustedProxies, logger)),
+		grpc.ChainStreamInterceptor(StreamInterceptor(trustedProxies, logger)),
+	)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(loanv1.LoanService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	loanv1.RegisterLoanServiceServer(server, loanServer)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return server, healthServer
+}
@@ -0,0 +1,55 @@
+package grpcapi
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	errFileTooLarge       = errors.New("file exceeds maximum size")
+	errUnexpectedMetadata = errors.New("metadata sent after file chunks")
+)
+
+// chunkReader presents the chunks of a client-streamed upload as an
+// io.Reader so they can be piped into storage.Storage.Save without buffering
+// the whole file. recv returns io.EOF once the client closes the stream.
+type chunkReader struct {
+	recv  func() ([]byte, error)
+	buf   []byte
+	read  int64
+	limit int64
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	r.read += int64(n)
+	if r.limit > 0 && r.read > r.limit {
+		return n, errFileTooLarge
+	}
+	return n, nil
+}
+
+// empty reports whether the client closed the stream without sending any file
+// data. It reads ahead at most one chunk.
+func (r *chunkReader) empty() (bool, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.recv()
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		r.buf = chunk
+	}
+	return false, nil
+}
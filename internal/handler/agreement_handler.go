@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/agunghallmanmaliki/amartha/internal/handler/dto"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+)
+
+// VerifyAgreementLetter reports whether the document in the signed_agreement
+// form field is the agreement letter issued for the loan, and whether our
+// signature over the letter holds for it.
+func (h *LoanHandler) VerifyAgreementLetter(w http.ResponseWriter, r *http.Request) {
+	loanID, err := h.extractLoanID(r)
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format"))
+		return
+	}
+
+	if !h.parseUploadForm(w, r, h.signedAgreement) {
+		return
+	}
+	document, _, err := r.FormFile(h.signedAgreement.Field)
+	if err != nil {
+		httperror.WriteError(w, httperror.Validation(httperror.Required(h.signedAgreement.Field)))
+		return
+	}
+	defer document.Close()
+
+	verification, err := h.loanService.VerifyAgreementLetter(r.Context(), loanID, document)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	dto.WriteJSON(w, http.StatusOK, dto.ToAgreementVerificationResponse(verification))
+}
+
+// GetSigningCertificate serves the PEM certificate, or public key, agreement
+// letters are signed with, so that signatures can be checked offline.
+func (h *LoanHandler) GetSigningCertificate(w http.ResponseWriter, r *http.Request) {
+	certificate := h.loanService.SigningCertificate()
+	if certificate == nil {
+		httperror.WriteError(w, httperror.NotFound("Agreement letters are not signed"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-pem-file")
+	w.WriteHeader(http.StatusOK)
+	w.Write(certificate)
+}
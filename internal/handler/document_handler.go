@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/handler/dto"
+	"github.com/agunghallmanmaliki/amartha/internal/photo"
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"github.com/agunghallmanmaliki/amartha/internal/upload"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+	"github.com/google/uuid"
+)
+
+// ListDocuments lists every version of the loan's documents, by purpose and
+// then version.
+func (h *LoanHandler) ListDocuments(w http.ResponseWriter, r *http.Request) {
+	loanID, err := h.extractLoanID(r)
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format"))
+		return
+	}
+
+	documents, err := h.loanService.ListDocuments(r.Context(), loanID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	dto.WriteJSON(w, http.StatusOK, dto.ToDocumentResponses(documents))
+}
+
+func (h *LoanHandler) GetDocument(w http.ResponseWriter, r *http.Request) {
+	documentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid document ID format"))
+		return
+	}
+
+	document, err := h.loanService.GetDocument(r.Context(), documentID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	dto.WriteJSON(w, http.StatusOK, dto.ToDocumentResponse(document))
+}
+
+// GetDocumentContent serves a document once its content has been checked
+// against the recorded checksum, which is also sent as Repr-Digest so the
+// client can check what it received. If the content served fails the check,
+// the connection is aborted.
+func (h *LoanHandler) GetDocumentContent(w http.ResponseWriter, r *http.Request) {
+	documentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid document ID format"))
+		return
+	}
+
+	document, content, err := h.loanService.OpenDocument(r.Context(), documentID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+	defer content.Close()
+
+	// Rows from before content types and sizes were recorded have neither
+	contentType := document.File.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	filename := fmt.Sprintf("%s-v%d%s", document.Purpose, document.Version, filepath.Ext(document.File.Key))
+	w.Header().Set("Content-Type", contentType)
+	if document.File.Size > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(document.File.Size, 10))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if sum, err := hex.DecodeString(document.File.SHA256); err == nil && len(sum) > 0 {
+		w.Header().Set("Repr-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(sum)+":")
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, content); err != nil {
+		// The status is already sent; cut the connection so content that
+		// failed its checksum part way is not taken for the whole document.
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// SupersedeDocument uploads the next version of a document. The file goes in
+// the form field named after the document's purpose and is checked against
+// the same policy as the original upload. The uploader is the actor
+// forwarded by the API gateway.
+func (h *LoanHandler) SupersedeDocument(w http.ResponseWriter, r *http.Request) {
+	documentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid document ID format"))
+		return
+	}
+
+	var uploadedBy string
+	if info := requestctx.FromContext(r.Context()); info != nil {
+		uploadedBy = info.ActorID
+	}
+	if uploadedBy == "" {
+		httperror.WriteError(w, httperror.Forbidden("The caller's identity was not forwarded"))
+		return
+	}
+
+	document, err := h.loanService.GetDocument(r.Context(), documentID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+	policy := h.documentPolicy(document.Purpose)
+
+	if !h.parseUploadForm(w, r, policy) {
+		return
+	}
+
+	stored, file, ok := h.saveUpload(w, r, policy)
+	if !ok {
+		return
+	}
+
+	next, err := h.loanService.SupersedeDocument(r.Context(), documentID, actorRole(r), uploadedBy, stored, photo.Metadata(file.Head()))
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	dto.WriteJSON(w, http.StatusCreated, dto.ToDocumentResponse(next))
+}
+
+func (h *LoanHandler) documentPolicy(purpose domain.DocumentPurpose) upload.Policy {
+	if purpose == domain.DocumentSignedAgreement {
+		return h.signedAgreement
+	}
+	return h.pictureProof
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/handler/middleware"
+	"github.com/agunghallmanmaliki/amartha/internal/health"
+	"github.com/agunghallmanmaliki/amartha/internal/repository/repotest"
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"github.com/agunghallmanmaliki/amartha/internal/service"
+	"github.com/agunghallmanmaliki/amartha/internal/storage/local"
+	"github.com/agunghallmanmaliki/amartha/internal/upload"
+	"github.com/google/uuid"
+)
+
+// newDocumentHandler returns a handler over in-memory repositories and local
+// storage holding content, committed under the returned key.
+func newDocumentHandler(t *testing.T, name, content string) (*LoanHandler, *repotest.Repositories, string) {
+	t.Helper()
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repos := repotest.New()
+
+	store, err := local.NewLocalStorage(t.TempDir(), "http://localhost:8080", []byte(strings.Repeat("k", 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := store.Save(ctx, name, strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Promote(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := service.NewLoanService(repos.Loans, repos.Products, repos.Approvals, repos.Investments,
+		repos.Disbursements, repos.Documents, repos.Previews, repos.TxManager, store,
+		service.NewMockEmailService(logger), domain.PhotoPolicy{}, nil, logger)
+	h := NewLoanHandler(svc, store, nil, 1<<20, 10, time.Minute, upload.Policy{}, upload.Policy{})
+	return h, repos, key
+}
+
+func TestGetDocumentContentLegacy(t *testing.T) {
+	h, repos, key := newDocumentHandler(t, "proof.jpg", "legacy proof")
+
+	// Rows backfilled by the migrations have no content type, size or checksum
+	document := domain.NewDocument(uuid.New(), domain.DocumentPictureProof, "validator-1", domain.StoredFile{Key: key})
+	if err := repos.Documents.Create(context.Background(), document); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/documents/"+document.ID.String()+"/content", nil)
+	req.SetPathValue("id", document.ID.String())
+	rec := httptest.NewRecorder()
+	h.GetDocumentContent(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("expected content type application/octet-stream, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Length"); got != "" {
+		t.Errorf("expected no Content-Length, got %q", got)
+	}
+	if got := rec.Header().Get("Repr-Digest"); got != "" {
+		t.Errorf("expected no Repr-Digest, got %q", got)
+	}
+	if rec.Body.String() != "legacy proof" {
+		t.Errorf("expected the stored content, got %q", rec.Body.String())
+	}
+}
+
+func TestDocumentRoutesAccess(t *testing.T) {
+	h, _, _ := newBatchHandler(t, 1<<20, 10)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	trusted, err := requestctx.ParseTrustedProxies([]string{"192.0.2.0/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := NewRouter(h, health.NewChecker(time.Second, logger), nil, nil, middleware.SecurityHeadersConfig{}, nil, trusted, logger).Setup()
+
+	investor := map[string]string{requestctx.HeaderActorID: "investor-1", requestctx.HeaderActorRole: "investor"}
+	officer := map[string]string{requestctx.HeaderActorID: "officer-1", requestctx.HeaderActorRole: "field_officer"}
+	id := uuid.NewString()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{"list without actor", http.MethodGet, "/api/v1/loans/" + id + "/documents", nil, http.StatusForbidden},
+		{"list as investor", http.MethodGet, "/api/v1/loans/" + id + "/documents", investor, http.StatusForbidden},
+		{"list as field officer", http.MethodGet, "/api/v1/loans/" + id + "/documents", officer, http.StatusNotFound},
+		{"get as investor", http.MethodGet, "/api/v1/documents/" + id, investor, http.StatusForbidden},
+		{"get as field officer", http.MethodGet, "/api/v1/documents/" + id, officer, http.StatusNotFound},
+		{"content without actor", http.MethodGet, "/api/v1/documents/" + id + "/content", nil, http.StatusForbidden},
+		{"content as investor", http.MethodGet, "/api/v1/documents/" + id + "/content", investor, http.StatusForbidden},
+		{"content as field officer", http.MethodGet, "/api/v1/documents/" + id + "/content", officer, http.StatusNotFound},
+		{"supersede without actor", http.MethodPost, "/api/v1/documents/" + id + "/versions", nil, http.StatusForbidden},
+		{"supersede as investor", http.MethodPost, "/api/v1/documents/" + id + "/versions", investor, http.StatusForbidden},
+		{"supersede without identity", http.MethodPost, "/api/v1/documents/" + id + "/versions", map[string]string{requestctx.HeaderActorRole: "field_officer"}, http.StatusForbidden},
+		{"supersede as field officer", http.MethodPost, "/api/v1/documents/" + id + "/versions", officer, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
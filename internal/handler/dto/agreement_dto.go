@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/service"
+)
+
+// SignatureResponse is our detached signature over an agreement letter: the
+// base64 signature of the letter's SHA-256 digest, checkable against the
+// certificate served at /api/v1/agreement-letters/signing-certificate.
+type SignatureResponse struct {
+	SHA256            string    `json:"sha256"`
+	Signature         []byte    `json:"signature"`
+	SignerFingerprint string    `json:"signer_fingerprint"`
+	SignedAt          time.Time `json:"signed_at"`
+}
+
+func ToSignatureResponse(signature *domain.AgreementSignature) *SignatureResponse {
+	if signature == nil {
+		return nil
+	}
+	return &SignatureResponse{
+		SHA256:            signature.SHA256,
+		Signature:         signature.Signature,
+		SignerFingerprint: signature.SignerFingerprint,
+		SignedAt:          signature.SignedAt,
+	}
+}
+
+// AgreementVerificationResponse reports whether a document is the agreement
+// letter issued for a loan. SHA256 is the digest of the document given.
+type AgreementVerificationResponse struct {
+	LoanID          string             `json:"loan_id"`
+	SHA256          string             `json:"sha256"`
+	Matches         bool               `json:"matches"`
+	SignatureStatus string             `json:"signature_status"`
+	Signature       *SignatureResponse `json:"signature,omitempty"`
+}
+
+func ToAgreementVerificationResponse(verification *service.AgreementVerification) *AgreementVerificationResponse {
+	return &AgreementVerificationResponse{
+		LoanID:          verification.LoanID.String(),
+		SHA256:          verification.SHA256,
+		Matches:         verification.Matches,
+		SignatureStatus: string(verification.SignatureStatus),
+		Signature:       ToSignatureResponse(verification.Signature),
+	}
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "github.com/agunghallmanmaliki/amartha/pkg/httperror"
+
+type CreateLoanBatchRequest struct {
+	Mode  string              `json:"mode"`
+	Loans []CreateLoanRequest `json:"loans"`
+}
+
+const (
+	BatchRowCreated    = "created"
+	BatchRowFailed     = "failed"
+	BatchRowNotCreated = "not_created"
+)
+
+// BatchRowResult reports one input row. Row is the 1-based position in the
+// JSON array, or the line number for CSV uploads.
+type BatchRowResult struct {
+	Row    int                    `json:"row"`
+	Status string                 `json:"status"`
+	Loan   *LoanResponse          `json:"loan,omitempty"`
+	Code   string                 `json:"code,omitempty"`
+	Detail string                 `json:"detail,omitempty"`
+	Errors []httperror.FieldError `json:"errors,omitempty"`
+}
+
+type BatchReport struct {
+	Mode    string            `json:"mode"`
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Results []*BatchRowResult `json:"results"`
+}
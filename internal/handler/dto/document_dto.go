@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+)
+
+// DocumentResponse is one version of a loan document. SHA256 is omitted for
+// documents stored before checksums were recorded.
+type DocumentResponse struct {
+	ID           string          `json:"id"`
+	LoanID       string          `json:"loan_id"`
+	Purpose      string          `json:"purpose"`
+	Version      int             `json:"version"`
+	Current      bool            `json:"current"`
+	ContentType  string          `json:"content_type"`
+	Size         int64           `json:"size"`
+	SHA256       string          `json:"sha256,omitempty"`
+	UploadedBy   string          `json:"uploaded_by"`
+	SupersededBy *string         `json:"superseded_by,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	Links        map[string]Link `json:"_links"`
+}
+
+func ToDocumentResponse(document *domain.Document) *DocumentResponse {
+	self := "/api/v1/documents/" + document.ID.String()
+	response := &DocumentResponse{
+		ID:          document.ID.String(),
+		LoanID:      document.LoanID.String(),
+		Purpose:     string(document.Purpose),
+		Version:     document.Version,
+		Current:     document.SupersededBy == nil,
+		ContentType: document.File.ContentType,
+		Size:        document.File.Size,
+		SHA256:      document.File.SHA256,
+		UploadedBy:  document.UploadedBy,
+		CreatedAt:   document.CreatedAt,
+		Links: map[string]Link{
+			"self":    {Href: self},
+			"content": {Href: self + "/content"},
+			"loan":    {Href: "/api/v1/loans/" + document.LoanID.String()},
+		},
+	}
+	if document.SupersededBy != nil {
+		next := document.SupersededBy.String()
+		response.SupersededBy = &next
+		response.Links["superseded_by"] = Link{Href: "/api/v1/documents/" + next}
+	}
+	return response
+}
+
+func ToDocumentResponses(documents []*domain.Document) []*DocumentResponse {
+	responses := make([]*DocumentResponse, len(documents))
+	for i, document := range documents {
+		responses[i] = ToDocumentResponse(document)
+	}
+	return responses
+}
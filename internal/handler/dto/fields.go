@@ -0,0 +1,176 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Fieldset is a sparse fieldset parsed from ?fields=. It selects members of
+// each response data object; dotted names select inside nested objects, e.g.
+// "loan.id,rank" on search results. Arrays are filtered element by element.
+type Fieldset struct {
+	names    []string
+	children map[string]*Fieldset
+}
+
+// ParseFieldset parses a comma-separated list of field names. Names are the
+// JSON member names of the response, lower case with underscores.
+func ParseFieldset(s string) (*Fieldset, error) {
+	fs := &Fieldset{children: make(map[string]*Fieldset)}
+	for _, path := range strings.Split(s, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		node := fs
+		for _, name := range strings.Split(path, ".") {
+			if !validFieldName(name) {
+				return nil, errors.New("invalid field name " + `"` + path + `"`)
+			}
+			node = node.child(name)
+		}
+	}
+	if len(fs.names) == 0 {
+		return nil, errors.New("no fields given")
+	}
+	return fs, nil
+}
+
+func (fs *Fieldset) child(name string) *Fieldset {
+	if c, ok := fs.children[name]; ok {
+		return c
+	}
+	c := &Fieldset{children: make(map[string]*Fieldset)}
+	fs.names = append(fs.names, name)
+	fs.children[name] = c
+	return c
+}
+
+func validFieldName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns data reduced to the selected fields, in the order they were
+// requested. Unknown fields are ignored.
+func (fs *Fieldset) apply(data interface{}) (json.RawMessage, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return fs.filter(raw)
+}
+
+func (fs *Fieldset) filter(raw json.RawMessage) (json.RawMessage, error) {
+	if len(fs.names) == 0 {
+		return raw, nil
+	}
+
+	switch trimmed := bytes.TrimSpace(raw); {
+	case len(trimmed) > 0 && trimmed[0] == '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		for i, item := range items {
+			filtered, err := fs.filter(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = filtered
+		}
+		return json.Marshal(items)
+
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &members); err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		first := true
+		for _, name := range fs.names {
+			value, ok := members[name]
+			if !ok {
+				continue
+			}
+			filtered, err := fs.children[name].filter(value)
+			if err != nil {
+				return nil, err
+			}
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			key, _ := json.Marshal(name)
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(filtered)
+		}
+		buf.WriteByte('}')
+		return buf.Bytes(), nil
+
+	default:
+		return raw, nil
+	}
+}
+
+// fieldsetWriter carries the requested fieldset to WriteJSON and
+// WriteJSONPaginated.
+type fieldsetWriter struct {
+	http.ResponseWriter
+	fieldset *Fieldset
+}
+
+func (w *fieldsetWriter) Flush() {
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *fieldsetWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// WithFieldset returns a writer through which WriteJSON and
+// WriteJSONPaginated only write the fields selected by fs.
+func WithFieldset(w http.ResponseWriter, fs *Fieldset) http.ResponseWriter {
+	return &fieldsetWriter{ResponseWriter: w, fieldset: fs}
+}
+
+// fieldsetOf finds the fieldset attached to w or any writer it wraps.
+func fieldsetOf(w http.ResponseWriter) *Fieldset {
+	for {
+		switch v := w.(type) {
+		case *fieldsetWriter:
+			return v.fieldset
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = v.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// selectFields applies the fieldset attached to w, if any, to data. Data that
+// cannot be filtered is written in full.
+func selectFields(w http.ResponseWriter, data interface{}) interface{} {
+	fs := fieldsetOf(w)
+	if fs == nil || data == nil {
+		return data
+	}
+	filtered, err := fs.apply(data)
+	if err != nil {
+		return data
+	}
+	return filtered
+}
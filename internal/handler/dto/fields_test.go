@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFieldsetApply(t *testing.T) {
+	data := []map[string]interface{}{
+		{"id": "1", "state": "approved", "remaining_amount": 500, "_links": map[string]interface{}{"self": "/loans/1"}},
+		{"id": "2", "state": "proposed", "remaining_amount": 1000},
+	}
+
+	tests := []struct {
+		name     string
+		fields   string
+		expected string
+	}{
+		{"requested order", "state,id", `[{"state":"approved","id":"1"},{"state":"proposed","id":"2"}]`},
+		{"unknown ignored", "id,nope", `[{"id":"1"},{"id":"2"}]`},
+		{"nested", "id,_links.self", `[{"id":"1","_links":{"self":"/loans/1"}},{"id":"2"}]`},
+		{"duplicates", "id,id", `[{"id":"1"},{"id":"2"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, err := ParseFieldset(tt.fields)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := fs.apply(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseFieldsetInvalid(t *testing.T) {
+	for _, fields := range []string{"", ",", "Id", "id,loan..id", "id;state"} {
+		if _, err := ParseFieldset(fields); err == nil {
+			t.Errorf("expected error for %q", fields)
+		}
+	}
+}
+
+func TestWriteJSONWithFieldset(t *testing.T) {
+	fs, _ := ParseFieldset("id")
+	rec := httptest.NewRecorder()
+
+	WriteJSON(WithFieldset(rec, fs), 200, map[string]string{"id": "1", "state": "proposed"})
+
+	expected := `{"success":true,"data":{"id":"1"}}` + "\n"
+	if rec.Body.String() != expected {
+		t.Errorf("expected %s, got %s", expected, rec.Body.String())
+	}
+}
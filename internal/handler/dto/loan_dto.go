@@ -4,62 +4,187 @@ import (
 	"time"
 
 	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/validation"
 )
 
 // Request DTOs
 
-type CreateLoanRequest struct {
-	BorrowerID      string  `json:"borrower_id" validate:"required"`
-	PrincipalAmount int64   `json:"principal_amount" validate:"required,gt=0"`
-	Rate            float64 `json:"rate" validate:"required,gte=0"`
-	ROI             float64 `json:"roi" validate:"required,gte=0"`
-}
+// The loan and investment requests share their rules with the gRPC API.
+type (
+	CreateLoanRequest    = validation.Loan
+	Location             = validation.Location
+	AddInvestmentRequest = validation.Investment
+)
 
-type AddInvestmentRequest struct {
-	InvestorID string `json:"investor_id" validate:"required"`
-	Amount     int64  `json:"amount" validate:"required,gt=0"`
+func toLocation(p *domain.GeoPoint) *Location {
+	if p == nil {
+		return nil
+	}
+	return &Location{Latitude: &p.Latitude, Longitude: &p.Longitude}
 }
 
 // Response DTOs
 
 type LoanResponse struct {
-	ID                 string    `json:"id"`
-	BorrowerID         string    `json:"borrower_id"`
-	PrincipalAmount    int64     `json:"principal_amount"`
-	Rate               float64   `json:"rate"`
-	ROI                float64   `json:"roi"`
-	State              string    `json:"state"`
-	AgreementLetterURL *string   `json:"agreement_letter_url,omitempty"`
-	TotalInvested      int64     `json:"total_invested"`
-	RemainingAmount    int64     `json:"remaining_amount"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
-}
-
-func ToLoanResponse(loan *domain.Loan) *LoanResponse {
+	ID                 string             `json:"id"`
+	BorrowerID         string             `json:"borrower_id"`
+	BorrowerName       string             `json:"borrower_name,omitempty"`
+	Notes              string             `json:"notes,omitempty"`
+	PrincipalAmount    int64              `json:"principal_amount"`
+	Rate               domain.Decimal     `json:"rate"`
+	ROI                domain.Decimal     `json:"roi"`
+	InterestAmount     *int64             `json:"interest_amount,omitempty"`
+	InvestorReturn     *int64             `json:"investor_return,omitempty"`
+	ProductID          *string            `json:"product_id,omitempty"`
+	TenorMonths        int                `json:"tenor_months,omitempty"`
+	BorrowerLocation   *Location          `json:"borrower_location,omitempty"`
+	State              string             `json:"state"`
+	AgreementLetterURL *string            `json:"agreement_letter_url,omitempty"`
+	AgreementSignature *SignatureResponse `json:"agreement_signature,omitempty"`
+	TotalInvested      int64              `json:"total_invested"`
+	RemainingAmount    int64              `json:"remaining_amount"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+	Links              map[string]Link    `json:"_links"`
+	Actions            []Action           `json:"actions"`
+}
+
+type Link struct {
+	Href string `json:"href"`
+}
+
+// Action describes a request the caller may make next. MaxAmount is set for
+// invest and is the most that can still be invested.
+type Action struct {
+	Name        string   `json:"name"`
+	Method      string   `json:"method"`
+	Href        string   `json:"href"`
+	ContentType string   `json:"content_type"`
+	Fields      []string `json:"fields"`
+	MaxAmount   *int64   `json:"max_amount,omitempty"`
+}
+
+// ToLoanResponse maps a loan for a caller with the given role; the role only
+// affects which actions are advertised.
+func ToLoanResponse(loan *domain.Loan, role domain.Role) *LoanResponse {
+	var productID *string
+	if loan.ProductID != nil {
+		id := loan.ProductID.String()
+		productID = &id
+	}
+
+	var interest, investorReturn *int64
+	if v, err := loan.Interest(); err == nil {
+		interest = &v
+	}
+	if v, err := loan.InvestorReturn(loan.PrincipalAmount); err == nil {
+		investorReturn = &v
+	}
+
 	return &LoanResponse{
 		ID:                 loan.ID.String(),
 		BorrowerID:         loan.BorrowerID,
+		BorrowerName:       loan.BorrowerName,
+		Notes:              loan.Notes,
 		PrincipalAmount:    loan.PrincipalAmount,
 		Rate:               loan.Rate,
 		ROI:                loan.ROI,
+		InterestAmount:     interest,
+		InvestorReturn:     investorReturn,
+		ProductID:          productID,
+		TenorMonths:        loan.TenorMonths,
+		BorrowerLocation:   toLocation(loan.BorrowerLocation),
 		State:              string(loan.State),
-		AgreementLetterURL: loan.AgreementLetterURL,
+		AgreementLetterURL: AgreementLetterURL(loan),
+		AgreementSignature: ToSignatureResponse(loan.AgreementSignature),
 		TotalInvested:      loan.TotalInvested,
 		RemainingAmount:    loan.RemainingAmount(),
 		CreatedAt:          loan.CreatedAt,
 		UpdatedAt:          loan.UpdatedAt,
+		Links:              loanLinks(loan, role),
+		Actions:            loanActions(loan, role),
 	}
 }
 
-func ToLoanResponses(loans []*domain.Loan) []*LoanResponse {
+func ToLoanResponses(loans []*domain.Loan, role domain.Role) []*LoanResponse {
 	responses := make([]*LoanResponse, len(loans))
 	for i, loan := range loans {
-		responses[i] = ToLoanResponse(loan)
+		responses[i] = ToLoanResponse(loan, role)
 	}
 	return responses
 }
 
+// AgreementLetterURL returns the API path that redirects to a freshly signed
+// download URL for the loan's agreement letter, or nil before disbursement.
+func AgreementLetterURL(loan *domain.Loan) *string {
+	if loan.AgreementLetterKey == nil {
+		return nil
+	}
+	path := "/api/v1/loans/" + loan.ID.String() + "/agreement-letter"
+	return &path
+}
+
+func loanLinks(loan *domain.Loan, role domain.Role) map[string]Link {
+	self := "/api/v1/loans/" + loan.ID.String()
+	links := map[string]Link{
+		"self":        {Href: self},
+		"investments": {Href: self + "/investments"},
+	}
+	if loan.ProductID != nil {
+		links["product"] = Link{Href: "/api/v1/loan-products/" + loan.ProductID.String()}
+	}
+	if loan.State != domain.LoanStateProposed {
+		links["approval"] = Link{Href: self + "/approval"}
+		if role.IsStaff() {
+			links["documents"] = Link{Href: self + "/documents"}
+		}
+	}
+	if loan.State == domain.LoanStateDisbursed {
+		links["disbursement"] = Link{Href: self + "/disbursement"}
+	}
+	if url := AgreementLetterURL(loan); url != nil {
+		links["agreement_letter"] = Link{Href: *url}
+	}
+	return links
+}
+
+func loanActions(loan *domain.Loan, role domain.Role) []Action {
+	self := "/api/v1/loans/" + loan.ID.String()
+
+	actions := []Action{}
+	for _, name := range loan.AvailableActions(role) {
+		switch name {
+		case domain.LoanActionApprove:
+			actions = append(actions, Action{
+				Name:        string(name),
+				Method:      "POST",
+				Href:        self + "/approve",
+				ContentType: "multipart/form-data",
+				Fields:      []string{"field_validator_id", "picture_proof"},
+			})
+		case domain.LoanActionInvest:
+			remaining := loan.RemainingAmount()
+			actions = append(actions, Action{
+				Name:        string(name),
+				Method:      "POST",
+				Href:        self + "/investments",
+				ContentType: "application/json",
+				Fields:      []string{"investor_id", "amount"},
+				MaxAmount:   &remaining,
+			})
+		case domain.LoanActionDisburse:
+			actions = append(actions, Action{
+				Name:        string(name),
+				Method:      "POST",
+				Href:        self + "/disburse",
+				ContentType: "multipart/form-data",
+				Fields:      []string{"field_officer_id", "signed_agreement"},
+			})
+		}
+	}
+	return actions
+}
+
 type InvestmentResponse struct {
 	ID         string    `json:"id"`
 	LoanID     string    `json:"loan_id"`
@@ -86,38 +211,93 @@ func ToInvestmentResponses(investments []*domain.Investment) []*InvestmentRespon
 	return responses
 }
 
+// FileResponse describes a stored file. URL stops working at ExpiresAt.
+type FileResponse struct {
+	URL         string    `json:"url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ContentType string    `json:"content_type,omitempty"`
+	Size        int64     `json:"size,omitempty"`
+}
+
 type ApprovalResponse struct {
-	ID               string    `json:"id"`
-	LoanID           string    `json:"loan_id"`
-	FieldValidatorID string    `json:"field_validator_id"`
-	PictureProofURL  string    `json:"picture_proof_url"`
-	ApprovedAt       time.Time `json:"approved_at"`
+	ID               string           `json:"id"`
+	LoanID           string           `json:"loan_id"`
+	FieldValidatorID string           `json:"field_validator_id"`
+	PictureProof     FileResponse     `json:"picture_proof"`
+	Photo            PhotoResponse    `json:"photo"`
+	Preview          *PreviewResponse `json:"picture_proof_preview,omitempty"`
+	ApprovedAt       time.Time        `json:"approved_at"`
 }
 
-func ToApprovalResponse(approval *domain.Approval) *ApprovalResponse {
+// PhotoResponse is what the picture proof records about how it was taken;
+// fields it does not record are omitted.
+type PhotoResponse struct {
+	CapturedAt  *time.Time `json:"captured_at,omitempty"`
+	Location    *Location  `json:"location,omitempty"`
+	DeviceMake  string     `json:"device_make,omitempty"`
+	DeviceModel string     `json:"device_model,omitempty"`
+}
+
+// PreviewResponse describes a small rendering of a file: a JPEG thumbnail of
+// an image or a PNG summary card of a PDF, whose page count is included.
+type PreviewResponse struct {
+	FileResponse
+	Width     int `json:"width"`
+	Height    int `json:"height"`
+	PageCount int `json:"page_count,omitempty"`
+}
+
+// ToPreviewResponse describes preview, whose file is linked by file.
+func ToPreviewResponse(preview *domain.Preview, file FileResponse) *PreviewResponse {
+	return &PreviewResponse{
+		FileResponse: file,
+		Width:        preview.Width,
+		Height:       preview.Height,
+		PageCount:    preview.PageCount,
+	}
+}
+
+// ToApprovalResponse maps an approval; preview is nil if the picture has
+// none.
+func ToApprovalResponse(approval *domain.Approval, pictureProof FileResponse, preview *PreviewResponse) *ApprovalResponse {
 	return &ApprovalResponse{
 		ID:               approval.ID.String(),
 		LoanID:           approval.LoanID.String(),
 		FieldValidatorID: approval.FieldValidatorID,
-		PictureProofURL:  approval.PictureProofURL,
+		PictureProof:     pictureProof,
+		Photo:            toPhotoResponse(approval.Photo),
+		Preview:          preview,
 		ApprovedAt:       approval.ApprovedAt,
 	}
 }
 
+func toPhotoResponse(photo domain.PhotoMetadata) PhotoResponse {
+	return PhotoResponse{
+		CapturedAt:  photo.CapturedAt,
+		Location:    toLocation(photo.Location),
+		DeviceMake:  photo.DeviceMake,
+		DeviceModel: photo.DeviceModel,
+	}
+}
+
 type DisbursementResponse struct {
-	ID                 string    `json:"id"`
-	LoanID             string    `json:"loan_id"`
-	FieldOfficerID     string    `json:"field_officer_id"`
-	SignedAgreementURL string    `json:"signed_agreement_url"`
-	DisbursedAt        time.Time `json:"disbursed_at"`
+	ID              string           `json:"id"`
+	LoanID          string           `json:"loan_id"`
+	FieldOfficerID  string           `json:"field_officer_id"`
+	SignedAgreement FileResponse     `json:"signed_agreement"`
+	Preview         *PreviewResponse `json:"signed_agreement_preview,omitempty"`
+	DisbursedAt     time.Time        `json:"disbursed_at"`
 }
 
-func ToDisburseResponse(disbursement *domain.Disbursement) *DisbursementResponse {
+// ToDisburseResponse maps a disbursement; preview is nil if the agreement
+// has none.
+func ToDisburseResponse(disbursement *domain.Disbursement, signedAgreement FileResponse, preview *PreviewResponse) *DisbursementResponse {
 	return &DisbursementResponse{
-		ID:                 disbursement.ID.String(),
-		LoanID:             disbursement.LoanID.String(),
-		FieldOfficerID:     disbursement.FieldOfficerID,
-		SignedAgreementURL: disbursement.SignedAgreementURL,
-		DisbursedAt:        disbursement.DisbursedAt,
+		ID:              disbursement.ID.String(),
+		LoanID:          disbursement.LoanID.String(),
+		FieldOfficerID:  disbursement.FieldOfficerID,
+		SignedAgreement: signedAgreement,
+		Preview:         preview,
+		DisbursedAt:     disbursement.DisbursedAt,
 	}
 }
@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/service"
+)
+
+// LoanProductRequest creates or replaces a loan product. Active defaults to
+// true when omitted.
+type LoanProductRequest struct {
+	Code              string         `json:"code" validate:"required,max=64"`
+	Name              string         `json:"name" validate:"required,max=255"`
+	MinPrincipal      int64          `json:"min_principal" validate:"required,gt=0"`
+	MaxPrincipal      int64          `json:"max_principal" validate:"required,gt=0"`
+	TenorMonths       []int          `json:"tenor_months" validate:"required,min=1,dive,gt=0"`
+	MinRate           domain.Decimal `json:"min_rate" validate:"gte=0"`
+	MaxRate           domain.Decimal `json:"max_rate" validate:"required,gt=0"`
+	MinROI            domain.Decimal `json:"min_roi" validate:"gte=0"`
+	MaxROI            domain.Decimal `json:"max_roi" validate:"required,gt=0"`
+	RequiredDocuments []string       `json:"required_documents" validate:"dive,required,max=64"`
+	Active            *bool          `json:"active"`
+}
+
+func (r LoanProductRequest) ToInput() service.LoanProductInput {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return service.LoanProductInput{
+		Code:              r.Code,
+		Name:              r.Name,
+		MinPrincipal:      r.MinPrincipal,
+		MaxPrincipal:      r.MaxPrincipal,
+		TenorMonths:       r.TenorMonths,
+		MinRate:           r.MinRate,
+		MaxRate:           r.MaxRate,
+		MinROI:            r.MinROI,
+		MaxROI:            r.MaxROI,
+		RequiredDocuments: r.RequiredDocuments,
+		Active:            active,
+	}
+}
+
+type LoanProductResponse struct {
+	ID                string         `json:"id"`
+	Code              string         `json:"code"`
+	Name              string         `json:"name"`
+	MinPrincipal      int64          `json:"min_principal"`
+	MaxPrincipal      int64          `json:"max_principal"`
+	TenorMonths       []int          `json:"tenor_months"`
+	MinRate           domain.Decimal `json:"min_rate"`
+	MaxRate           domain.Decimal `json:"max_rate"`
+	MinROI            domain.Decimal `json:"min_roi"`
+	MaxROI            domain.Decimal `json:"max_roi"`
+	RequiredDocuments []string       `json:"required_documents"`
+	Active            bool           `json:"active"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+func ToLoanProductResponse(product *domain.LoanProduct) *LoanProductResponse {
+	return &LoanProductResponse{
+		ID:                product.ID.String(),
+		Code:              product.Code,
+		Name:              product.Name,
+		MinPrincipal:      product.MinPrincipal,
+		MaxPrincipal:      product.MaxPrincipal,
+		TenorMonths:       product.TenorMonths,
+		MinRate:           product.MinRate,
+		MaxRate:           product.MaxRate,
+		MinROI:            product.MinROI,
+		MaxROI:            product.MaxROI,
+		RequiredDocuments: product.RequiredDocuments,
+		Active:            product.Active,
+		CreatedAt:         product.CreatedAt,
+		UpdatedAt:         product.UpdatedAt,
+	}
+}
+
+func ToLoanProductResponses(products []*domain.LoanProduct) []*LoanProductResponse {
+	responses := make([]*LoanProductResponse, len(products))
+	for i, product := range products {
+		responses[i] = ToLoanProductResponse(product)
+	}
+	return responses
+}
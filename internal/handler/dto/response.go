@@ -8,17 +8,11 @@ import (
 type Response struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
-	Error   *ErrorInfo  `json:"error,omitempty"`
-}
-
-type ErrorInfo struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
 }
 
 type PaginatedResponse struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data"`
+	Success bool            `json:"success"`
+	Data    interface{}     `json:"data"`
 	Meta    *PaginationMeta `json:"meta"`
 }
 
@@ -28,25 +22,29 @@ type PaginationMeta struct {
 	Offset int   `json:"offset"`
 }
 
+// WriteJSON writes data in the standard envelope. If the request selected a
+// sparse fieldset (see WithFieldset), only those fields of data are written.
 func WriteJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
 	response := Response{
 		Success: status >= 200 && status < 300,
-		Data:    data,
+		Data:    selectFields(w, data),
 	}
 
 	json.NewEncoder(w).Encode(response)
 }
 
+// WriteJSONPaginated is WriteJSON with pagination metadata; the fieldset
+// applies to each item of data.
 func WriteJSONPaginated(w http.ResponseWriter, status int, data interface{}, total int64, limit, offset int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
 	response := PaginatedResponse{
 		Success: true,
-		Data:    data,
+		Data:    selectFields(w, data),
 		Meta: &PaginationMeta{
 			Total:  total,
 			Limit:  limit,
@@ -56,18 +54,3 @@ func WriteJSONPaginated(w http.ResponseWriter, status int, data interface{}, tot
 
 	json.NewEncoder(w).Encode(response)
 }
-
-func WriteError(w http.ResponseWriter, status int, code, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-
-	response := Response{
-		Success: false,
-		Error: &ErrorInfo{
-			Code:    code,
-			Message: message,
-		},
-	}
-
-	json.NewEncoder(w).Encode(response)
-}
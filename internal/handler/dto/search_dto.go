@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/service"
+)
+
+// SearchResultResponse is one search hit. Highlights maps each matched field
+// to an HTML-escaped snippet with the matching text wrapped in <mark>.
+type SearchResultResponse struct {
+	Loan       *LoanResponse     `json:"loan"`
+	Rank       float64           `json:"rank"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+func ToSearchResultResponses(results []*service.SearchResult, role domain.Role) []*SearchResultResponse {
+	responses := make([]*SearchResultResponse, len(results))
+	for i, result := range results {
+		responses[i] = &SearchResultResponse{
+			Loan:       ToLoanResponse(result.Loan, role),
+			Rank:       result.Rank,
+			Highlights: result.Highlights,
+		}
+	}
+	return responses
+}
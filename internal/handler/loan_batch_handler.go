@@ -0,0 +1,362 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/handler/dto"
+	"github.com/agunghallmanmaliki/amartha/internal/service"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+)
+
+// csvLoanColumns are the columns a loan import file must have, in any order.
+// borrower_name and notes are optional.
+var csvLoanColumns = []string{"borrower_id", "principal_amount", "rate", "roi", "product_id", "tenor_months"}
+
+// errBatchTooLarge is returned while reading a batch that has more rows than
+// allowed.
+var errBatchTooLarge = errors.New("batch has too many rows")
+
+// batchRow is one parsed input row awaiting validation.
+type batchRow struct {
+	row    int
+	req    dto.CreateLoanRequest
+	errors []httperror.FieldError
+}
+
+// CreateLoanBatch creates loans from a JSON batch. The body is bounded by
+// maxFileSize and read a loan at a time, so that an oversized batch is
+// rejected before it is decoded in full.
+func (h *LoanHandler) CreateLoanBatch(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxFileSize)
+	req, err := decodeLoanBatch(r.Body, h.maxBatchRows)
+	if err != nil {
+		if !h.writeBatchTooLarge(w, err) {
+			httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_JSON", "Invalid JSON request body"))
+		}
+		return
+	}
+
+	mode, ok := parseBatchMode(req.Mode)
+	if !ok {
+		httperror.WriteError(w, httperror.Validation(httperror.FieldError{
+			Field: "mode", Rule: "oneof", Param: "atomic partial", Message: "mode must be one of: atomic partial",
+		}))
+		return
+	}
+
+	rows := make([]*batchRow, len(req.Loans))
+	for i, loan := range req.Loans {
+		rows[i] = &batchRow{row: i + 1, req: loan}
+	}
+
+	h.createLoanBatch(w, r, mode, rows)
+}
+
+// ImportLoansCSV creates loans from a multipart "file" upload whose header
+// row names the csvLoanColumns. Like CreateLoanBatch, the upload is bounded
+// by maxFileSize.
+func (h *LoanHandler) ImportLoansCSV(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxFileSize+formOverhead)
+	if err := r.ParseMultipartForm(h.maxFileSize); err != nil {
+		if !h.writeBatchTooLarge(w, err) {
+			httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_FORM", "Failed to parse multipart form"))
+		}
+		return
+	}
+
+	mode, ok := parseBatchMode(r.FormValue("mode"))
+	if !ok {
+		httperror.WriteError(w, httperror.Validation(httperror.FieldError{
+			Field: "mode", Rule: "oneof", Param: "atomic partial", Message: "mode must be one of: atomic partial",
+		}))
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		httperror.WriteError(w, httperror.Validation(httperror.Required("file")))
+		return
+	}
+	defer file.Close()
+
+	rows, err := readLoanCSV(file, h.maxBatchRows)
+	if err != nil {
+		if !h.writeBatchTooLarge(w, err) {
+			httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_CSV", err.Error()))
+		}
+		return
+	}
+
+	h.createLoanBatch(w, r, mode, rows)
+}
+
+func (h *LoanHandler) createLoanBatch(w http.ResponseWriter, r *http.Request, mode service.BatchMode, rows []*batchRow) {
+	if len(rows) == 0 {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "EMPTY_BATCH", "Batch contains no loans"))
+		return
+	}
+	if len(rows) > h.maxBatchRows {
+		h.writeBatchTooLarge(w, errBatchTooLarge)
+		return
+	}
+
+	report := &dto.BatchReport{
+		Mode:    string(mode),
+		Total:   len(rows),
+		Results: make([]*dto.BatchRowResult, len(rows)),
+	}
+
+	var valid []int
+	for i, row := range rows {
+		if len(row.errors) == 0 {
+			if err := h.validator.Struct(row.req); err != nil {
+				row.errors = httperror.FromValidator(err).Errors
+			}
+		}
+
+		report.Results[i] = &dto.BatchRowResult{Row: row.row, Status: dto.BatchRowNotCreated}
+		if len(row.errors) > 0 {
+			report.Results[i].Status = dto.BatchRowFailed
+			report.Results[i].Code = "VALIDATION_ERROR"
+			report.Results[i].Errors = row.errors
+			report.Failed++
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	// An atomic batch is all-or-nothing, so don't start a transaction that
+	// is already known to fail.
+	if mode == service.BatchModeAtomic && report.Failed > 0 {
+		dto.WriteJSON(w, http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	inputs := make([]service.CreateLoanInput, len(valid))
+	for j, i := range valid {
+		inputs[j] = rows[i].req.ToInput()
+	}
+
+	results, err := h.loanService.CreateLoans(r.Context(), inputs, mode)
+	for j, result := range results {
+		row := report.Results[valid[j]]
+		switch {
+		case result.Loan != nil:
+			row.Status = dto.BatchRowCreated
+			row.Loan = dto.ToLoanResponse(result.Loan, actorRole(r))
+			report.Created++
+		case result.Err != nil:
+			problem := serviceError(result.Err)
+			row.Status = dto.BatchRowFailed
+			row.Code = problem.Code
+			row.Detail = problem.Detail
+			row.Errors = problem.Errors
+			report.Failed++
+		}
+	}
+
+	status := http.StatusCreated
+	switch {
+	case err != nil:
+		status = serviceError(err).Status
+		if status < http.StatusInternalServerError {
+			status = http.StatusUnprocessableEntity
+		}
+	case report.Failed > 0:
+		status = http.StatusMultiStatus
+	}
+
+	dto.WriteJSON(w, status, report)
+}
+
+// writeBatchTooLarge writes a 413 if err means the batch has too many rows or
+// too many bytes, and reports whether it did.
+func (h *LoanHandler) writeBatchTooLarge(w http.ResponseWriter, err error) bool {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, errBatchTooLarge):
+		httperror.WriteError(w, httperror.New(http.StatusRequestEntityTooLarge, "BATCH_TOO_LARGE",
+			fmt.Sprintf("Batch exceeds the maximum of %d loans", h.maxBatchRows)))
+	case errors.As(err, &tooLarge):
+		httperror.WriteError(w, httperror.New(http.StatusRequestEntityTooLarge, "BATCH_TOO_LARGE",
+			fmt.Sprintf("Batch exceeds the maximum size of %d bytes", h.maxFileSize)))
+	default:
+		return false
+	}
+	return true
+}
+
+// decodeLoanBatch decodes a CreateLoanBatchRequest one loan at a time,
+// returning errBatchTooLarge as soon as it has more than maxRows loans.
+// Unknown members are skipped, as json.Decoder.Decode would.
+func decodeLoanBatch(r io.Reader, maxRows int) (*dto.CreateLoanBatchRequest, error) {
+	decoder := json.NewDecoder(r)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, err
+	}
+
+	var req dto.CreateLoanBatchRequest
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch token {
+		case "mode":
+			err = decoder.Decode(&req.Mode)
+		case "loans":
+			req.Loans, err = decodeLoans(decoder, maxRows)
+		default:
+			var skipped json.RawMessage
+			err = decoder.Decode(&skipped)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := expectDelim(decoder, '}'); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func decodeLoans(decoder *json.Decoder, maxRows int) ([]dto.CreateLoanRequest, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, nil
+	}
+	if token != json.Delim('[') {
+		return nil, fmt.Errorf("expected loans to be an array, got %v", token)
+	}
+
+	var loans []dto.CreateLoanRequest
+	for decoder.More() {
+		if len(loans) == maxRows {
+			return nil, errBatchTooLarge
+		}
+		var loan dto.CreateLoanRequest
+		if err := decoder.Decode(&loan); err != nil {
+			return nil, err
+		}
+		loans = append(loans, loan)
+	}
+	return loans, expectDelim(decoder, ']')
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %v, got %v", delim, token)
+	}
+	return nil
+}
+
+func parseBatchMode(mode string) (service.BatchMode, bool) {
+	switch service.BatchMode(mode) {
+	case "", service.BatchModeAtomic:
+		return service.BatchModeAtomic, true
+	case service.BatchModePartial:
+		return service.BatchModePartial, true
+	default:
+		return "", false
+	}
+}
+
+// readLoanCSV parses an import file. Cells that are not numbers where numbers
+// are expected are reported as row errors rather than failing the upload.
+func readLoanCSV(r io.Reader, maxRows int) ([]*batchRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("CSV file is empty")
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvLoanColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header is missing column %q", name)
+		}
+	}
+
+	var rows []*batchRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if len(rows) == maxRows {
+			return nil, errBatchTooLarge
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := &batchRow{row: line}
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row.req.BorrowerID = cell("borrower_id")
+		row.req.BorrowerName = cell("borrower_name")
+		row.req.Notes = cell("notes")
+		row.req.PrincipalAmount = parseCSVInt(row, "principal_amount", cell("principal_amount"))
+		row.req.Rate = parseCSVDecimal(row, "rate", cell("rate"))
+		row.req.ROI = parseCSVDecimal(row, "roi", cell("roi"))
+		row.req.ProductID = cell("product_id")
+		row.req.TenorMonths = int(parseCSVInt(row, "tenor_months", cell("tenor_months")))
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseCSVInt(row *batchRow, field, value string) int64 {
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		row.errors = append(row.errors, httperror.FieldError{Field: field, Rule: "number", Message: field + " must be an integer"})
+	}
+	return n
+}
+
+func parseCSVDecimal(row *batchRow, field, value string) domain.Decimal {
+	if value == "" {
+		return domain.Decimal{}
+	}
+	d, err := domain.ParseDecimal(value)
+	if err != nil {
+		row.errors = append(row.errors, httperror.FieldError{
+			Field: field, Rule: "decimal", Message: field + " must be a decimal number with at most 4 fractional digits",
+		})
+	}
+	return d
+}
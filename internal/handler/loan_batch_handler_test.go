@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/handler/dto"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/internal/repository/repotest"
+	"github.com/agunghallmanmaliki/amartha/internal/service"
+	"github.com/agunghallmanmaliki/amartha/internal/upload"
+)
+
+// newBatchHandler returns a handler over in-memory repositories holding one
+// active product that accepts 12-month loans of 100000 to 5000000.
+func newBatchHandler(t *testing.T, maxFileSize int64, maxBatchRows int) (*LoanHandler, *repotest.Repositories, *domain.LoanProduct) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repos := repotest.New()
+
+	product := domain.NewLoanProduct("MICRO-12", "Micro 12 months")
+	product.MinPrincipal, product.MaxPrincipal = 100000, 5000000
+	product.TenorMonths = []int{12}
+	product.MinRate, product.MaxRate = domain.MustParseDecimal("0.1"), domain.MustParseDecimal("0.2")
+	product.MinROI, product.MaxROI = domain.MustParseDecimal("0.05"), domain.MustParseDecimal("0.15")
+	if err := repos.Products.Create(context.Background(), product); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := service.NewLoanService(repos.Loans, repos.Products, repos.Approvals, repos.Investments,
+		repos.Disbursements, repos.Documents, repos.Previews, repos.TxManager, nil,
+		service.NewMockEmailService(logger), domain.PhotoPolicy{}, nil, logger)
+	h := NewLoanHandler(svc, nil, nil, maxFileSize, maxBatchRows, 0, upload.Policy{}, upload.Policy{})
+	return h, repos, product
+}
+
+func loanJSON(borrowerID string, principal int64, productID string) string {
+	return fmt.Sprintf(`{"borrower_id": %q, "principal_amount": %d, "rate": 0.15, "roi": 0.12, "product_id": %q, "tenor_months": 12}`,
+		borrowerID, principal, productID)
+}
+
+func decodeBatchReport(t *testing.T, rec *httptest.ResponseRecorder) *dto.BatchReport {
+	t.Helper()
+	var response struct {
+		Data dto.BatchReport `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	return &response.Data
+}
+
+func rowStatuses(report *dto.BatchReport) []string {
+	statuses := make([]string, len(report.Results))
+	for i, result := range report.Results {
+		statuses[i] = fmt.Sprintf("%d:%s", result.Row, result.Status)
+	}
+	return statuses
+}
+
+func TestCreateLoanBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		principals  []int64
+		wantStatus  int
+		wantCreated int
+		wantRows    []string
+	}{
+		{"atomic success", "atomic", []int64{1000000, 2000000}, http.StatusCreated, 2,
+			[]string{"1:created", "2:created"}},
+		{"atomic with an invalid row", "", []int64{1000000, 0}, http.StatusUnprocessableEntity, 0,
+			[]string{"1:not_created", "2:failed"}},
+		{"atomic with a row out of the product's range", "atomic", []int64{1000000, 9000000}, http.StatusUnprocessableEntity, 0,
+			[]string{"1:not_created", "2:failed"}},
+		{"partial with failing rows", "partial", []int64{1000000, 0, 9000000}, http.StatusMultiStatus, 1,
+			[]string{"1:created", "2:failed", "3:failed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, product := newBatchHandler(t, 1<<20, 10)
+
+			loans := make([]string, len(tt.principals))
+			for i, principal := range tt.principals {
+				loans[i] = loanJSON(fmt.Sprintf("borrower-%d", i+1), principal, product.ID.String())
+			}
+			body := fmt.Sprintf(`{"mode": %q, "loans": [%s]}`, tt.mode, strings.Join(loans, ","))
+
+			rec := httptest.NewRecorder()
+			h.CreateLoanBatch(rec, httptest.NewRequest(http.MethodPost, "/api/v1/loans:batch", strings.NewReader(body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			report := decodeBatchReport(t, rec)
+			if report.Total != len(tt.principals) || report.Created != tt.wantCreated {
+				t.Errorf("expected %d total and %d created, got %d and %d", len(tt.principals), tt.wantCreated, report.Total, report.Created)
+			}
+			if got := rowStatuses(report); strings.Join(got, ",") != strings.Join(tt.wantRows, ",") {
+				t.Errorf("expected rows %v, got %v", tt.wantRows, got)
+			}
+			for _, result := range report.Results {
+				if result.Status == dto.BatchRowFailed && (result.Code == "" || len(result.Errors) == 0) {
+					t.Errorf("expected failed row %d to report a code and field errors, got %+v", result.Row, result)
+				}
+				if (result.Status == dto.BatchRowCreated) != (result.Loan != nil) {
+					t.Errorf("expected only created row %d to carry a loan", result.Row)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateLoanBatchRejectsOversizedBatches(t *testing.T) {
+	h, repos, product := newBatchHandler(t, 1024, 2)
+	loan := loanJSON("borrower-1", 1000000, product.ID.String())
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"too many rows", `{"loans": [` + strings.Repeat(loan+",", 2) + loan + `]}`, http.StatusRequestEntityTooLarge, "BATCH_TOO_LARGE"},
+		{"too many bytes", `{"loans": [` + loan + `], "padding": "` + strings.Repeat("x", 2048) + `"}`, http.StatusRequestEntityTooLarge, "BATCH_TOO_LARGE"},
+		{"empty", `{"loans": []}`, http.StatusBadRequest, "EMPTY_BATCH"},
+		{"malformed", `{"loans": {}}`, http.StatusBadRequest, "INVALID_JSON"},
+		{"bad mode", `{"mode": "eventual", "loans": [` + loan + `]}`, http.StatusBadRequest, "VALIDATION_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.CreateLoanBatch(rec, httptest.NewRequest(http.MethodPost, "/api/v1/loans:batch", strings.NewReader(tt.body)))
+
+			var problem struct {
+				Code string `json:"code"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &problem)
+			if rec.Code != tt.wantStatus || problem.Code != tt.wantCode {
+				t.Errorf("expected %d %s, got %d %s", tt.wantStatus, tt.wantCode, rec.Code, problem.Code)
+			}
+		})
+	}
+
+	if loans, _, _ := repos.Loans.List(context.Background(), repository.LoanFilter{}); len(loans) != 0 {
+		t.Errorf("expected no loans to be created, got %d", len(loans))
+	}
+}
+
+func csvUpload(t *testing.T, mode, content string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("mode", mode)
+	part, err := form.CreateFormFile("file", "loans.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(content))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/loans:import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+func TestImportLoansCSV(t *testing.T) {
+	h, _, product := newBatchHandler(t, 1<<20, 2)
+	id := product.ID.String()
+
+	tests := []struct {
+		name       string
+		content    string
+		wantStatus int
+		wantCode   string
+		wantRows   []string
+	}{
+		{
+			name:       "columns in any order",
+			content:    "tenor_months,product_id,roi,rate,principal_amount,borrower_id,notes\n12," + id + ",0.12,0.15,1000000,borrower-1,sawah\n",
+			wantStatus: http.StatusCreated,
+			wantRows:   []string{"2:created"},
+		},
+		{
+			name:       "unparseable cells",
+			content:    "borrower_id,principal_amount,rate,roi,product_id,tenor_months\nborrower-1,1000000,0.15,0.12," + id + ",12\nborrower-2,lots,0.15,0.12," + id + ",12\n",
+			wantStatus: http.StatusMultiStatus,
+			wantRows:   []string{"2:created", "3:failed"},
+		},
+		{
+			name:       "missing column",
+			content:    "borrower_id,principal_amount,rate,roi,product_id\nborrower-1,1000000,0.15,0.12," + id + "\n",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_CSV",
+		},
+		{
+			name:       "ragged row",
+			content:    "borrower_id,principal_amount,rate,roi,product_id,tenor_months\nborrower-1,1000000\n",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_CSV",
+		},
+		{
+			name:       "empty file",
+			content:    "",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_CSV",
+		},
+		{
+			name:       "too many rows",
+			content:    "borrower_id,principal_amount,rate,roi,product_id,tenor_months\n" + strings.Repeat("borrower-1,1000000,0.15,0.12,"+id+",12\n", 3),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   "BATCH_TOO_LARGE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ImportLoansCSV(rec, csvUpload(t, "partial", tt.content))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if tt.wantCode != "" {
+				var problem struct {
+					Code string `json:"code"`
+				}
+				json.Unmarshal(rec.Body.Bytes(), &problem)
+				if problem.Code != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, problem.Code)
+				}
+				return
+			}
+			if got := rowStatuses(decodeBatchReport(t, rec)); strings.Join(got, ",") != strings.Join(tt.wantRows, ",") {
+				t.Errorf("expected rows %v, got %v", tt.wantRows, got)
+			}
+		})
+	}
+}
+
+func TestImportLoansCSVRejectsOversizedUploads(t *testing.T) {
+	h, _, _ := newBatchHandler(t, 1024, 10)
+
+	rec := httptest.NewRecorder()
+	h.ImportLoansCSV(rec, csvUpload(t, "atomic", strings.Repeat("x", 2*formOverhead)))
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d: %s", rec.Code, rec.Body)
+	}
+}
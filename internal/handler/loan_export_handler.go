@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/export"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+	"github.com/google/uuid"
+)
+
+// ExportLoans streams loans as CSV or XLSX. It accepts the same filters as
+// ListLoans, but returns every match unless limit is given.
+func (h *LoanHandler) ExportLoans(w http.ResponseWriter, r *http.Request) {
+	filter := parseLoanFilter(r, 0)
+
+	writeExport(w, r, "loans", "Loans", export.LoanColumns, func(fn func(*domain.Loan) error) error {
+		return h.loanService.ExportLoans(r.Context(), filter, fn)
+	})
+}
+
+// ExportInvestments streams investments as CSV or XLSX, optionally filtered by
+// loan_id and investor_id.
+func (h *LoanHandler) ExportInvestments(w http.ResponseWriter, r *http.Request) {
+	var filter repository.InvestmentFilter
+
+	if loanIDStr := r.URL.Query().Get("loan_id"); loanIDStr != "" {
+		loanID, err := uuid.Parse(loanIDStr)
+		if err != nil {
+			httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format"))
+			return
+		}
+		filter.LoanID = &loanID
+	}
+	if investorID := r.URL.Query().Get("investor_id"); investorID != "" {
+		filter.InvestorID = &investorID
+	}
+
+	writeExport(w, r, "investments", "Investments", export.InvestmentColumns, func(fn func(*domain.Investment) error) error {
+		return h.loanService.ExportInvestments(r.Context(), filter, fn)
+	})
+}
+
+// writeExport negotiates format and columns, then streams rows from stream.
+// Once the first byte is sent a failure can no longer be reported as a
+// problem, so the connection is aborted to keep clients from mistaking a
+// truncated file for a complete one.
+func writeExport[T any](w http.ResponseWriter, r *http.Request, name, sheet string, all []export.Column[T], stream func(func(T) error) error) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_FORMAT", "format must be one of: csv xlsx"))
+		return
+	}
+
+	var names []string
+	if columns := r.URL.Query().Get("columns"); columns != "" {
+		names = strings.Split(columns, ",")
+	}
+	columns, err := export.SelectColumns(all, names)
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_COLUMNS", err.Error()))
+		return
+	}
+
+	// Large exports outlive the server's default write timeout.
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102T150405Z"), format)
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	writer, err := export.NewRowWriter(format, w, sheet)
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	table, err := export.NewTable(writer, columns)
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+
+	if err := stream(table.Write); err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	if err := writer.Close(); err != nil {
+		panic(http.ErrAbortHandler)
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/export"
+)
+
+func TestExportLoans(t *testing.T) {
+	h, repos, _ := newBatchHandler(t, 1<<20, 10)
+	for _, borrowerID := range []string{"borrower-1", "borrower-2"} {
+		loan := domain.NewLoan(borrowerID, 1000000, domain.MustParseDecimal("0.15"), domain.MustParseDecimal("0.12"))
+		if err := repos.Loans.Create(context.Background(), loan); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name            string
+		query           string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+		wantCode        string
+	}{
+		{"csv with columns", "?columns=borrower_id,principal_amount", http.StatusOK, "text/csv; charset=utf-8",
+			"borrower_id,principal_amount\nborrower-1,1000000\nborrower-2,1000000\n", ""},
+		{"xlsx", "?format=xlsx&columns=borrower_id", http.StatusOK, export.ContentType(export.FormatXLSX), "PK", ""},
+		{"unknown format", "?format=ods", http.StatusBadRequest, "", "", "INVALID_FORMAT"},
+		{"unknown column", "?columns=borrower_id,ssn", http.StatusBadRequest, "", "", "INVALID_COLUMNS"},
+		{"duplicate column", "?columns=borrower_id,borrower_id", http.StatusBadRequest, "", "", "INVALID_COLUMNS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ExportLoans(rec, httptest.NewRequest(http.MethodGet, "/api/v1/loans:export"+tt.query, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if tt.wantCode != "" {
+				var problem struct {
+					Code string `json:"code"`
+				}
+				json.Unmarshal(rec.Body.Bytes(), &problem)
+				if problem.Code != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, problem.Code)
+				}
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("expected content type %s, got %s", tt.wantContentType, got)
+			}
+			if !strings.HasPrefix(rec.Header().Get("Content-Disposition"), `attachment; filename="loans-`) {
+				t.Errorf("expected an attachment, got %q", rec.Header().Get("Content-Disposition"))
+			}
+			if !strings.HasPrefix(rec.Body.String(), tt.wantBody) {
+				t.Errorf("expected body to start with %q, got %q", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWriteExportAbortsOnStreamFailure(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/loans:export", nil)
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Fatalf("expected panic with http.ErrAbortHandler, got %v", recovered)
+		}
+		// The 200 has already been sent, so the client can only learn of the
+		// failure from the connection dropping.
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != export.ContentType(export.FormatCSV) {
+			t.Errorf("expected the export to have been started, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+		}
+	}()
+
+	writeExport(rec, req, "loans", "Loans", export.LoanColumns[1:2], func(fn func(*domain.Loan) error) error {
+		loan := domain.NewLoan("borrower-1", 1000000, domain.MustParseDecimal("0.15"), domain.MustParseDecimal("0.12"))
+		if err := fn(loan); err != nil {
+			return err
+		}
+		return errors.New("connection reset by peer")
+	})
+	t.Fatal("expected writeExport to abort")
+}
@@ -5,58 +5,88 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
-	"strings"
+	"time"
 
 	"github.com/agunghallmanmaliki/amartha/internal/domain"
 	"github.com/agunghallmanmaliki/amartha/internal/handler/dto"
+	"github.com/agunghallmanmaliki/amartha/internal/photo"
 	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"github.com/agunghallmanmaliki/amartha/internal/scan"
 	"github.com/agunghallmanmaliki/amartha/internal/service"
 	"github.com/agunghallmanmaliki/amartha/internal/storage"
+	"github.com/agunghallmanmaliki/amartha/internal/upload"
+	"github.com/agunghallmanmaliki/amartha/internal/validation"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 type LoanHandler struct {
-	loanService *service.LoanService
-	storage     storage.Storage
-	validator   *validator.Validate
-	maxFileSize int64
+	loanService  *service.LoanService
+	storage      storage.Storage
+	uploads      *scan.Gate
+	validator    *validator.Validate
+	maxFileSize  int64
+	maxBatchRows int
+	signedURLTTL time.Duration
+
+	pictureProof    upload.Policy
+	signedAgreement upload.Policy
 }
 
-func NewLoanHandler(loanService *service.LoanService, storage storage.Storage, maxFileSize int64) *LoanHandler {
+// NewLoanHandler builds the REST handlers. Uploads are saved through the
+// uploads gate, which scans them before they are committed. maxFileSize is
+// how much of a multipart form is buffered in memory; pictureProof and
+// signedAgreement bound the documents accepted on approval and disbursement.
+func NewLoanHandler(
+	loanService *service.LoanService,
+	storage storage.Storage,
+	uploads *scan.Gate,
+	maxFileSize int64,
+	maxBatchRows int,
+	signedURLTTL time.Duration,
+	pictureProof upload.Policy,
+	signedAgreement upload.Policy,
+) *LoanHandler {
 	return &LoanHandler{
-		loanService: loanService,
-		storage:     storage,
-		validator:   validator.New(),
-		maxFileSize: maxFileSize,
+		loanService:     loanService,
+		storage:         storage,
+		uploads:         uploads,
+		validator:       validation.New(),
+		maxFileSize:     maxFileSize,
+		maxBatchRows:    maxBatchRows,
+		signedURLTTL:    signedURLTTL,
+		pictureProof:    pictureProof,
+		signedAgreement: signedAgreement,
 	}
 }
 
 func (h *LoanHandler) CreateLoan(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateLoanRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON request body")
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_JSON", "Invalid JSON request body"))
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "VALIDATION_ERROR", formatValidationError(err))
+		httperror.WriteError(w, httperror.FromValidator(err))
 		return
 	}
 
-	loan, err := h.loanService.CreateLoan(r.Context(), req.BorrowerID, req.PrincipalAmount, req.Rate, req.ROI)
+	loan, err := h.loanService.CreateLoan(r.Context(), req.ToInput())
 	if err != nil {
 		h.handleServiceError(w, err)
 		return
 	}
 
-	dto.WriteJSON(w, http.StatusCreated, dto.ToLoanResponse(loan))
+	dto.WriteJSON(w, http.StatusCreated, dto.ToLoanResponse(loan, actorRole(r)))
 }
 
 func (h *LoanHandler) GetLoan(w http.ResponseWriter, r *http.Request) {
 	loanID, err := h.extractLoanID(r)
 	if err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format")
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format"))
 		return
 	}
 
@@ -66,12 +96,26 @@ func (h *LoanHandler) GetLoan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dto.WriteJSON(w, http.StatusOK, dto.ToLoanResponse(loan))
+	dto.WriteJSON(w, http.StatusOK, dto.ToLoanResponse(loan, actorRole(r)))
 }
 
 func (h *LoanHandler) ListLoans(w http.ResponseWriter, r *http.Request) {
+	filter := parseLoanFilter(r, 10)
+
+	loans, total, err := h.loanService.ListLoans(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	dto.WriteJSONPaginated(w, http.StatusOK, dto.ToLoanResponses(loans, actorRole(r)), total, filter.Limit, filter.Offset)
+}
+
+// parseLoanFilter reads the limit, offset and state query parameters shared by
+// the list and export endpoints.
+func parseLoanFilter(r *http.Request, defaultLimit int) repository.LoanFilter {
 	filter := repository.LoanFilter{
-		Limit:  10,
+		Limit:  defaultLimit,
 		Offset: 0,
 	}
 
@@ -92,72 +136,55 @@ func (h *LoanHandler) ListLoans(w http.ResponseWriter, r *http.Request) {
 		filter.State = &state
 	}
 
-	loans, total, err := h.loanService.ListLoans(r.Context(), filter)
-	if err != nil {
-		h.handleServiceError(w, err)
-		return
-	}
-
-	dto.WriteJSONPaginated(w, http.StatusOK, dto.ToLoanResponses(loans), total, filter.Limit, filter.Offset)
+	return filter
 }
 
 func (h *LoanHandler) ApproveLoan(w http.ResponseWriter, r *http.Request) {
 	loanID, err := h.extractLoanID(r)
 	if err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format")
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format"))
 		return
 	}
 
-	if err := r.ParseMultipartForm(h.maxFileSize); err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "INVALID_FORM", "Failed to parse multipart form")
+	if !h.parseUploadForm(w, r, h.pictureProof) {
 		return
 	}
 
 	fieldValidatorID := r.FormValue("field_validator_id")
 	if fieldValidatorID == "" {
-		dto.WriteError(w, http.StatusBadRequest, "VALIDATION_ERROR", "field_validator_id is required")
-		return
-	}
-
-	file, header, err := r.FormFile("picture_proof")
-	if err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "VALIDATION_ERROR", "picture_proof file is required")
+		httperror.WriteError(w, httperror.Validation(httperror.Required("field_validator_id")))
 		return
 	}
-	defer file.Close()
 
-	filename, err := h.storage.Save(r.Context(), header.Filename, file)
-	if err != nil {
-		dto.WriteError(w, http.StatusInternalServerError, "STORAGE_ERROR", "Failed to save file")
+	pictureProof, file, ok := h.saveUpload(w, r, h.pictureProof)
+	if !ok {
 		return
 	}
 
-	pictureProofURL := h.storage.GetURL(filename)
-
-	loan, err := h.loanService.ApproveLoan(r.Context(), loanID, fieldValidatorID, pictureProofURL)
+	loan, err := h.loanService.ApproveLoan(r.Context(), loanID, fieldValidatorID, pictureProof, photo.Metadata(file.Head()))
 	if err != nil {
 		h.handleServiceError(w, err)
 		return
 	}
 
-	dto.WriteJSON(w, http.StatusOK, dto.ToLoanResponse(loan))
+	dto.WriteJSON(w, http.StatusOK, dto.ToLoanResponse(loan, actorRole(r)))
 }
 
 func (h *LoanHandler) AddInvestment(w http.ResponseWriter, r *http.Request) {
 	loanID, err := h.extractLoanID(r)
 	if err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format")
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format"))
 		return
 	}
 
 	var req dto.AddInvestmentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON request body")
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_JSON", "Invalid JSON request body"))
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "VALIDATION_ERROR", formatValidationError(err))
+		httperror.WriteError(w, httperror.FromValidator(err))
 		return
 	}
 
@@ -171,7 +198,7 @@ func (h *LoanHandler) AddInvestment(w http.ResponseWriter, r *http.Request) {
 		Loan       *dto.LoanResponse       `json:"loan"`
 		Investment *dto.InvestmentResponse `json:"investment"`
 	}{
-		Loan:       dto.ToLoanResponse(loan),
+		Loan:       dto.ToLoanResponse(loan, actorRole(r)),
 		Investment: dto.ToInvestmentResponse(investment),
 	}
 
@@ -181,7 +208,7 @@ func (h *LoanHandler) AddInvestment(w http.ResponseWriter, r *http.Request) {
 func (h *LoanHandler) ListInvestments(w http.ResponseWriter, r *http.Request) {
 	loanID, err := h.extractLoanID(r)
 	if err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format")
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format"))
 		return
 	}
 
@@ -197,91 +224,300 @@ func (h *LoanHandler) ListInvestments(w http.ResponseWriter, r *http.Request) {
 func (h *LoanHandler) DisburseLoan(w http.ResponseWriter, r *http.Request) {
 	loanID, err := h.extractLoanID(r)
 	if err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format")
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format"))
 		return
 	}
 
-	if err := r.ParseMultipartForm(h.maxFileSize); err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "INVALID_FORM", "Failed to parse multipart form")
+	if !h.parseUploadForm(w, r, h.signedAgreement) {
 		return
 	}
 
 	fieldOfficerID := r.FormValue("field_officer_id")
 	if fieldOfficerID == "" {
-		dto.WriteError(w, http.StatusBadRequest, "VALIDATION_ERROR", "field_officer_id is required")
+		httperror.WriteError(w, httperror.Validation(httperror.Required("field_officer_id")))
+		return
+	}
+
+	signedAgreement, _, ok := h.saveUpload(w, r, h.signedAgreement)
+	if !ok {
 		return
 	}
 
-	file, header, err := r.FormFile("signed_agreement")
+	loan, err := h.loanService.DisburseLoan(r.Context(), loanID, fieldOfficerID, signedAgreement)
 	if err != nil {
-		dto.WriteError(w, http.StatusBadRequest, "VALIDATION_ERROR", "signed_agreement file is required")
+		h.handleServiceError(w, err)
 		return
 	}
+
+	dto.WriteJSON(w, http.StatusOK, dto.ToLoanResponse(loan, actorRole(r)))
+}
+
+// formOverhead is the room left in a multipart body for form fields and
+// part headers beyond the file itself.
+const formOverhead = 64 * 1024
+
+// parseUploadForm parses a multipart form whose file must satisfy policy,
+// rejecting bodies that cannot fit within its size limit. On failure it
+// writes the problem and returns false.
+func (h *LoanHandler) parseUploadForm(w http.ResponseWriter, r *http.Request, policy upload.Policy) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, policy.MaxSize+formOverhead)
+	if err := r.ParseMultipartForm(h.maxFileSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			httperror.WriteError(w, serviceError(policy.TooLarge()))
+			return false
+		}
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_FORM", "Failed to parse multipart form"))
+		return false
+	}
+	return true
+}
+
+// saveUpload checks the policy's form file and saves it, returning the saved
+// file for callers that read its metadata. On failure it writes the problem
+// and returns false.
+func (h *LoanHandler) saveUpload(w http.ResponseWriter, r *http.Request, policy upload.Policy) (domain.StoredFile, *upload.File, bool) {
+	file, header, err := r.FormFile(policy.Field)
+	if err != nil {
+		httperror.WriteError(w, httperror.Validation(httperror.Required(policy.Field)))
+		return domain.StoredFile{}, nil, false
+	}
 	defer file.Close()
 
-	filename, err := h.storage.Save(r.Context(), header.Filename, file)
+	if header.Size > policy.MaxSize {
+		httperror.WriteError(w, serviceError(policy.TooLarge()))
+		return domain.StoredFile{}, nil, false
+	}
+
+	f, err := policy.Open(header.Filename, file)
 	if err != nil {
-		dto.WriteError(w, http.StatusInternalServerError, "STORAGE_ERROR", "Failed to save file")
+		httperror.WriteError(w, serviceError(err))
+		return domain.StoredFile{}, nil, false
+	}
+
+	key, err := h.uploads.Save(r.Context(), policy.Field, f.Name(), f)
+	if err != nil {
+		if f.Err() != nil {
+			httperror.WriteError(w, serviceError(f.Err()))
+			return domain.StoredFile{}, nil, false
+		}
+		if errors.Is(err, domain.ErrFileInfected) || errors.Is(err, domain.ErrScanUnavailable) {
+			httperror.WriteError(w, serviceError(err))
+			return domain.StoredFile{}, nil, false
+		}
+		httperror.WriteError(w, httperror.New(http.StatusInternalServerError, "STORAGE_ERROR", "Failed to save file"))
+		return domain.StoredFile{}, nil, false
+	}
+	return f.Stored(key), f, true
+}
+
+func (h *LoanHandler) GetApproval(w http.ResponseWriter, r *http.Request) {
+	loanID, err := h.extractLoanID(r)
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format"))
 		return
 	}
 
-	signedAgreementURL := h.storage.GetURL(filename)
+	approval, err := h.loanService.GetApproval(r.Context(), loanID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
 
-	loan, err := h.loanService.DisburseLoan(r.Context(), loanID, fieldOfficerID, signedAgreementURL)
+	pictureProof, err := h.signURL(r, approval.PictureProof)
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusInternalServerError, "STORAGE_ERROR", "Failed to sign download URL"))
+		return
+	}
+	preview, ok := h.preview(w, r, approval.PictureProof)
+	if !ok {
+		return
+	}
+
+	dto.WriteJSON(w, http.StatusOK, dto.ToApprovalResponse(approval, pictureProof, preview))
+}
+
+func (h *LoanHandler) GetDisbursement(w http.ResponseWriter, r *http.Request) {
+	loanID, err := h.extractLoanID(r)
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format"))
+		return
+	}
+
+	disbursement, err := h.loanService.GetDisbursement(r.Context(), loanID)
 	if err != nil {
 		h.handleServiceError(w, err)
 		return
 	}
 
-	dto.WriteJSON(w, http.StatusOK, dto.ToLoanResponse(loan))
+	signedAgreement, err := h.signURL(r, disbursement.SignedAgreement)
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusInternalServerError, "STORAGE_ERROR", "Failed to sign download URL"))
+		return
+	}
+	preview, ok := h.preview(w, r, disbursement.SignedAgreement)
+	if !ok {
+		return
+	}
+
+	dto.WriteJSON(w, http.StatusOK, dto.ToDisburseResponse(disbursement, signedAgreement, preview))
 }
 
-func (h *LoanHandler) extractLoanID(r *http.Request) (uuid.UUID, error) {
-	// Extract from path: /api/v1/loans/{id}/...
-	path := r.URL.Path
-	parts := strings.Split(path, "/")
-
-	// Find the ID after "loans"
-	for i, part := range parts {
-		if part == "loans" && i+1 < len(parts) {
-			return uuid.Parse(parts[i+1])
-		}
+// GetAgreementLetter redirects to a freshly signed download URL, so the
+// agreement_letter_url on a loan never goes stale.
+func (h *LoanHandler) GetAgreementLetter(w http.ResponseWriter, r *http.Request) {
+	loanID, err := h.extractLoanID(r)
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan ID format"))
+		return
+	}
+
+	loan, err := h.loanService.GetLoan(r.Context(), loanID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+	if loan.AgreementLetterKey == nil {
+		httperror.WriteError(w, httperror.NotFound("Loan has no agreement letter yet"))
+		return
+	}
+
+	agreement, err := h.signURL(r, domain.StoredFile{Key: *loan.AgreementLetterKey})
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusInternalServerError, "STORAGE_ERROR", "Failed to sign download URL"))
+		return
 	}
 
-	return uuid.Nil, errors.New("loan ID not found in path")
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, agreement.URL, http.StatusFound)
+}
+
+// preview describes the preview of file with a signed download URL, or
+// returns nil if it has none. On failure it writes the problem and returns
+// false.
+func (h *LoanHandler) preview(w http.ResponseWriter, r *http.Request, file domain.StoredFile) (*dto.PreviewResponse, bool) {
+	preview, err := h.loanService.GetPreview(r.Context(), file.Key)
+	if errors.Is(err, domain.ErrPreviewNotFound) {
+		return nil, true
+	}
+	if err != nil {
+		h.handleServiceError(w, err)
+		return nil, false
+	}
+
+	previewFile, err := h.signURL(r, preview.File)
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusInternalServerError, "STORAGE_ERROR", "Failed to sign download URL"))
+		return nil, false
+	}
+	return dto.ToPreviewResponse(preview, previewFile), true
+}
+
+// signURL describes a stored file with a download URL valid for
+// signedURLTTL.
+func (h *LoanHandler) signURL(r *http.Request, file domain.StoredFile) (dto.FileResponse, error) {
+	expiresAt := time.Now().Add(h.signedURLTTL).UTC().Truncate(time.Second)
+	url, err := h.storage.SignedURL(r.Context(), file.Key, h.signedURLTTL)
+	if err != nil {
+		return dto.FileResponse{}, err
+	}
+	return dto.FileResponse{URL: url, ExpiresAt: expiresAt, ContentType: file.ContentType, Size: file.Size}, nil
+}
+
+func (h *LoanHandler) extractLoanID(r *http.Request) (uuid.UUID, error) {
+	return uuid.Parse(r.PathValue("id"))
+}
+
+// actorRole returns the caller's role forwarded by the API gateway.
+func actorRole(r *http.Request) domain.Role {
+	if info := requestctx.FromContext(r.Context()); info != nil {
+		return domain.Role(info.ActorRole)
+	}
+	return ""
 }
 
 func (h *LoanHandler) handleServiceError(w http.ResponseWriter, err error) {
+	httperror.WriteError(w, serviceError(err))
+}
+
+// serviceError maps domain errors returned by LoanService to problems.
+func serviceError(err error) *httperror.HTTPError {
 	switch {
 	case errors.Is(err, domain.ErrLoanNotFound):
-		dto.WriteError(w, http.StatusNotFound, "NOT_FOUND", "Loan not found")
+		return httperror.New(http.StatusNotFound, "NOT_FOUND", "Loan not found")
 	case errors.Is(err, domain.ErrInvalidStateTransition):
-		dto.WriteError(w, http.StatusUnprocessableEntity, "INVALID_STATE_TRANSITION", "Invalid state transition")
+		return httperror.New(http.StatusUnprocessableEntity, "INVALID_STATE_TRANSITION", "Invalid state transition")
 	case errors.Is(err, domain.ErrInvestmentExceedsLimit):
-		dto.WriteError(w, http.StatusUnprocessableEntity, "INVESTMENT_EXCEEDS_LIMIT", "Investment amount exceeds remaining principal")
+		return httperror.New(http.StatusUnprocessableEntity, "INVESTMENT_EXCEEDS_LIMIT", "Investment amount exceeds remaining principal")
 	case errors.Is(err, domain.ErrLoanNotApproved):
-		dto.WriteError(w, http.StatusUnprocessableEntity, "LOAN_NOT_APPROVED", "Loan must be in approved state to accept investments")
+		return httperror.New(http.StatusUnprocessableEntity, "LOAN_NOT_APPROVED", "Loan must be in approved state to accept investments")
 	case errors.Is(err, domain.ErrLoanNotInvested):
-		dto.WriteError(w, http.StatusUnprocessableEntity, "LOAN_NOT_INVESTED", "Loan must be in invested state to disburse")
+		return httperror.New(http.StatusUnprocessableEntity, "LOAN_NOT_INVESTED", "Loan must be in invested state to disburse")
 	case errors.Is(err, domain.ErrLoanAlreadyApproved):
-		dto.WriteError(w, http.StatusUnprocessableEntity, "LOAN_ALREADY_APPROVED", "Loan is already approved")
+		return httperror.New(http.StatusUnprocessableEntity, "LOAN_ALREADY_APPROVED", "Loan is already approved")
 	case errors.Is(err, domain.ErrLoanAlreadyDisbursed):
-		dto.WriteError(w, http.StatusUnprocessableEntity, "LOAN_ALREADY_DISBURSED", "Loan is already disbursed")
+		return httperror.New(http.StatusUnprocessableEntity, "LOAN_ALREADY_DISBURSED", "Loan is already disbursed")
 	case errors.Is(err, domain.ErrInvalidAmount):
-		dto.WriteError(w, http.StatusBadRequest, "INVALID_AMOUNT", "Amount must be greater than zero")
+		return httperror.New(http.StatusBadRequest, "INVALID_AMOUNT", "Amount must be greater than zero")
+	case errors.Is(err, domain.ErrApprovalNotFound):
+		return httperror.New(http.StatusNotFound, "NOT_FOUND", "Loan has not been approved")
+	case errors.Is(err, domain.ErrDisbursementNotFound):
+		return httperror.New(http.StatusNotFound, "NOT_FOUND", "Loan has not been disbursed")
+	case errors.Is(err, domain.ErrAgreementLetterNotFound):
+		return httperror.New(http.StatusNotFound, "NOT_FOUND", "Loan has no agreement letter yet")
+	case errors.Is(err, domain.ErrFileTooLarge):
+		return withViolations(httperror.New(http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", "File exceeds the maximum size"), err)
+	case errors.Is(err, domain.ErrUnsupportedFileType):
+		return withViolations(httperror.New(http.StatusUnsupportedMediaType, "UNSUPPORTED_FILE_TYPE", "File type is not allowed"), err)
+	case errors.Is(err, domain.ErrFileExtensionMismatch):
+		return withViolations(httperror.New(http.StatusUnsupportedMediaType, "FILE_EXTENSION_MISMATCH", "File extension does not match its content"), err)
+	case errors.Is(err, domain.ErrPictureProofRejected):
+		return withViolations(httperror.New(http.StatusUnprocessableEntity, "PICTURE_PROOF_REJECTED", "Picture proof does not meet the photo policy"), err)
+	case errors.Is(err, domain.ErrFileInfected):
+		return withViolations(httperror.New(http.StatusUnprocessableEntity, "FILE_INFECTED", "File contains malware"), err)
+	case errors.Is(err, domain.ErrScanUnavailable):
+		return httperror.New(http.StatusServiceUnavailable, "SCAN_UNAVAILABLE", "File could not be scanned for malware; try again later")
+	case errors.Is(err, domain.ErrDocumentNotFound):
+		return httperror.New(http.StatusNotFound, "NOT_FOUND", "Document not found")
+	case errors.Is(err, domain.ErrDocumentSuperseded):
+		return httperror.New(http.StatusConflict, "DOCUMENT_SUPERSEDED", "Document has been superseded; only the current version can be replaced")
+	case errors.Is(err, domain.ErrRoleNotPermitted):
+		return httperror.Forbidden("The caller's role may not perform this operation")
+	case errors.Is(err, domain.ErrDocumentTampered):
+		return httperror.New(http.StatusInternalServerError, "DOCUMENT_TAMPERED", "Stored document does not match its checksum")
+	case errors.Is(err, domain.ErrLoanProductNotFound):
+		return httperror.New(http.StatusNotFound, "NOT_FOUND", "Loan product not found")
+	case errors.Is(err, domain.ErrLoanProductCodeTaken):
+		return httperror.New(http.StatusConflict, "LOAN_PRODUCT_CODE_TAKEN", "Loan product code is already in use")
+	case errors.Is(err, domain.ErrLoanProductInUse):
+		return httperror.New(http.StatusConflict, "LOAN_PRODUCT_IN_USE", "Loan product is referenced by loans; deactivate it instead")
+	case errors.Is(err, domain.ErrInvalidLoanProduct):
+		return httperror.Validation(violations(err)...)
+	case errors.Is(err, domain.ErrLoanProductUnavailable):
+		return withViolations(httperror.New(http.StatusUnprocessableEntity, "LOAN_PRODUCT_UNAVAILABLE", "Loan product does not exist or is not active"), err)
+	case errors.Is(err, domain.ErrLoanTermsOutOfRange):
+		return withViolations(httperror.New(http.StatusUnprocessableEntity, "LOAN_TERMS_OUT_OF_RANGE", "Loan terms are outside the product's limits"), err)
 	default:
-		dto.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "An internal error occurred")
+		return httperror.New(http.StatusInternalServerError, "INTERNAL_ERROR", "An internal error occurred")
 	}
 }
 
-func formatValidationError(err error) string {
-	var validationErrs validator.ValidationErrors
-	if errors.As(err, &validationErrs) {
-		var messages []string
-		for _, e := range validationErrs {
-			messages = append(messages, e.Field()+" is invalid")
-		}
-		return strings.Join(messages, ", ")
+// violations converts the rules reported by a *domain.ViolationError into
+// problem field errors.
+func violations(err error) []httperror.FieldError {
+	var verr *domain.ViolationError
+	if !errors.As(err, &verr) {
+		return nil
 	}
-	return "Validation failed"
+
+	fieldErrors := make([]httperror.FieldError, len(verr.Violations))
+	for i, v := range verr.Violations {
+		fieldErrors[i] = httperror.FieldError{Field: v.Field, Rule: v.Rule, Param: v.Param, Message: v.Message}
+	}
+	return fieldErrors
+}
+
+func withViolations(problem *httperror.HTTPError, err error) *httperror.HTTPError {
+	problem.Errors = violations(err)
+	return problem
 }
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/agunghallmanmaliki/amartha/internal/handler/dto"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+	"github.com/google/uuid"
+)
+
+func (h *LoanHandler) CreateLoanProduct(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeLoanProduct(w, r)
+	if !ok {
+		return
+	}
+
+	product, err := h.loanService.CreateLoanProduct(r.Context(), req.ToInput())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	dto.WriteJSON(w, http.StatusCreated, dto.ToLoanProductResponse(product))
+}
+
+// ListLoanProducts lists the catalogue ordered by code; ?active=true leaves
+// out deactivated products.
+func (h *LoanHandler) ListLoanProducts(w http.ResponseWriter, r *http.Request) {
+	var filter repository.LoanProductFilter
+	if activeStr := r.URL.Query().Get("active"); activeStr != "" {
+		active, err := strconv.ParseBool(activeStr)
+		if err != nil {
+			httperror.WriteError(w, httperror.BadRequest("active must be true or false"))
+			return
+		}
+		filter.ActiveOnly = active
+	}
+
+	products, err := h.loanService.ListLoanProducts(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	dto.WriteJSON(w, http.StatusOK, dto.ToLoanProductResponses(products))
+}
+
+func (h *LoanHandler) GetLoanProduct(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan product ID format"))
+		return
+	}
+
+	product, err := h.loanService.GetLoanProduct(r.Context(), productID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	dto.WriteJSON(w, http.StatusOK, dto.ToLoanProductResponse(product))
+}
+
+func (h *LoanHandler) UpdateLoanProduct(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan product ID format"))
+		return
+	}
+
+	req, ok := h.decodeLoanProduct(w, r)
+	if !ok {
+		return
+	}
+
+	product, err := h.loanService.UpdateLoanProduct(r.Context(), productID, req.ToInput())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	dto.WriteJSON(w, http.StatusOK, dto.ToLoanProductResponse(product))
+}
+
+func (h *LoanHandler) DeleteLoanProduct(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_ID", "Invalid loan product ID format"))
+		return
+	}
+
+	if err := h.loanService.DeleteLoanProduct(r.Context(), productID); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *LoanHandler) decodeLoanProduct(w http.ResponseWriter, r *http.Request) (dto.LoanProductRequest, bool) {
+	var req dto.LoanProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_JSON", "Invalid JSON request body"))
+		return req, false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		httperror.WriteError(w, httperror.FromValidator(err))
+		return req, false
+	}
+
+	return req, true
+}
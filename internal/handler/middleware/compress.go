@@ -0,0 +1,230 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// compressMinSize is the smallest body worth compressing. Shorter responses
+// are sent as is unless the handler flushes before reaching it.
+const compressMinSize = 1024
+
+// compressibleTypes are the media type prefixes that are compressed. Uploaded
+// images and PDFs are already compressed and are left alone.
+var compressibleTypes = []string{
+	"text/",
+	"application/json",
+	"application/problem+json",
+	"application/xml",
+}
+
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var encoderPools = map[string]*sync.Pool{
+	"br": {New: func() any {
+		return brotli.NewWriterLevel(nil, brotli.DefaultCompression)
+	}},
+	"gzip": {New: func() any {
+		return gzip.NewWriter(nil)
+	}},
+}
+
+// Compress encodes responses with br or gzip as negotiated by Accept-Encoding.
+// Streaming responses stay streaming: a flush from the handler flushes the
+// encoder too.
+func Compress() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding}
+			next.ServeHTTP(cw, r)
+			cw.close()
+		})
+	}
+}
+
+// negotiateEncoding picks br or gzip, whichever has the higher q-value,
+// preferring br on a tie. It returns "" if neither is acceptable.
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	q := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		weight := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				weight = f
+			}
+		}
+		q[name] = weight
+	}
+
+	best, bestQ := "", 0.0
+	for _, name := range []string{"br", "gzip"} {
+		weight, ok := q[name]
+		if !ok {
+			weight, ok = q["*"]
+		}
+		if ok && weight > bestQ {
+			best, bestQ = name, weight
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the start of the body until it knows whether the
+// response is worth compressing, then commits the headers.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+
+	status   int
+	buf      []byte
+	decided  bool
+	compress bool
+	enc      encoder
+}
+
+func (c *compressWriter) WriteHeader(code int) {
+	if c.decided || c.status != 0 {
+		return
+	}
+	if code >= 100 && code < 200 {
+		c.ResponseWriter.WriteHeader(code)
+		return
+	}
+	c.status = code
+}
+
+func (c *compressWriter) Write(p []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+
+	if !c.decided {
+		if !c.compressible() {
+			c.decide(false)
+		} else {
+			c.buf = append(c.buf, p...)
+			if len(c.buf) >= compressMinSize {
+				if err := c.decide(true); err != nil {
+					return 0, err
+				}
+			}
+			return len(p), nil
+		}
+	}
+
+	if c.compress {
+		return c.enc.Write(p)
+	}
+	return c.ResponseWriter.Write(p)
+}
+
+func (c *compressWriter) Flush() {
+	if !c.decided {
+		if c.status == 0 {
+			c.status = http.StatusOK
+		}
+		c.decide(c.compressible())
+	}
+	if c.compress {
+		c.enc.Flush()
+	}
+	http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+func (c *compressWriter) compressible() bool {
+	h := c.Header()
+	if c.status < 200 || c.status == http.StatusNoContent || c.status == http.StatusNotModified ||
+		c.status == http.StatusPartialContent {
+		return false
+	}
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" {
+		return false
+	}
+	if n, err := strconv.Atoi(h.Get("Content-Length")); err == nil && n < compressMinSize {
+		return false
+	}
+
+	contentType := h.Get("Content-Type")
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// decide commits the headers, with or without compression, and writes out
+// anything buffered so far.
+func (c *compressWriter) decide(compress bool) error {
+	c.decided = true
+	c.compress = compress
+
+	if compress {
+		h := c.Header()
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+		h.Set("Content-Encoding", c.encoding)
+
+		c.enc = encoderPools[c.encoding].Get().(encoder)
+		c.enc.Reset(c.ResponseWriter)
+	}
+
+	c.ResponseWriter.WriteHeader(c.status)
+
+	buf := c.buf
+	c.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if compress {
+		_, err := c.enc.Write(buf)
+		return err
+	}
+	_, err := c.ResponseWriter.Write(buf)
+	return err
+}
+
+// close finishes the response. Bodies that never reached compressMinSize are
+// written uncompressed.
+func (c *compressWriter) close() {
+	if !c.decided {
+		if c.status == 0 {
+			return
+		}
+		c.decide(false)
+	}
+	if c.compress {
+		c.enc.Close()
+		encoderPools[c.encoding].Put(c.enc)
+		c.enc = nil
+	}
+}
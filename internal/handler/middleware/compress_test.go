@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br", "br"},
+		{"br;q=0.5, gzip", "gzip"},
+		{"br;q=0, gzip;q=0", ""},
+		{"*", "br"},
+		{"br;q=0, *;q=0.1", "gzip"},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.expected {
+			t.Errorf("expected %q for %q, got %q", tt.expected, tt.header, got)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat(`{"id":"550e8400-e29b-41d4-a716-446655440000"}`, 100)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		encoded     bool
+	}{
+		{"large json", "application/json", large, true},
+		{"small json", "application/json", `{"ok":true}`, false},
+		{"image", "image/jpeg", large, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Compress()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(http.StatusCreated)
+				io.WriteString(w, tt.body)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusCreated {
+				t.Errorf("expected status %d, got %d", http.StatusCreated, rec.Code)
+			}
+			if vary := rec.Header().Get("Vary"); vary != "Accept-Encoding" {
+				t.Errorf("expected Vary Accept-Encoding, got %q", vary)
+			}
+
+			body := io.Reader(rec.Body)
+			if tt.encoded {
+				if ce := rec.Header().Get("Content-Encoding"); ce != "gzip" {
+					t.Fatalf("expected gzip encoding, got %q", ce)
+				}
+				zr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("invalid gzip body: %v", err)
+				}
+				body = zr
+			} else if ce := rec.Header().Get("Content-Encoding"); ce != "" {
+				t.Errorf("expected no encoding, got %q", ce)
+			}
+
+			got, _ := io.ReadAll(body)
+			if string(got) != tt.body {
+				t.Errorf("body mismatch: got %d bytes, expected %d", len(got), len(tt.body))
+			}
+		})
+	}
+}
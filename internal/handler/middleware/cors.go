@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+)
+
+// CORSConfig configures cross-origin access. An origin may be "*" (any
+// origin) or contain a single leading wildcard label, e.g.
+// "https://*.amartha.com".
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+type CORS struct {
+	cfg            CORSConfig
+	allowedMethods string
+	allowedHeaders string
+	exposedHeaders string
+	maxAge         string
+}
+
+// NewCORS rejects a config that allows any origin with credentials, since
+// that would let every site make credentialed requests as the user.
+func NewCORS(cfg CORSConfig) (*CORS, error) {
+	if cfg.AllowCredentials && allowsAny(cfg.AllowedOrigins) {
+		return nil, errors.New("CORS cannot allow credentials from any origin; list the allowed origins instead")
+	}
+	return &CORS{
+		cfg:            cfg,
+		allowedMethods: strings.Join(cfg.AllowedMethods, ", "),
+		allowedHeaders: strings.Join(cfg.AllowedHeaders, ", "),
+		exposedHeaders: strings.Join(cfg.ExposedHeaders, ", "),
+		maxAge:         strconv.Itoa(int(cfg.MaxAge.Seconds())),
+	}, nil
+}
+
+// Handler answers preflight requests itself and adds CORS headers to actual
+// requests from allowed origins. Requests without an Origin header pass
+// through untouched.
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Origin")
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if !c.originAllowed(origin) {
+			if preflight {
+				httperror.WriteError(w, httperror.New(http.StatusForbidden, "CORS_ORIGIN_NOT_ALLOWED", "Origin "+origin+" is not allowed"))
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		c.setAllowOrigin(h, origin)
+
+		if preflight {
+			h.Set("Access-Control-Allow-Methods", c.allowedMethods)
+			h.Set("Access-Control-Allow-Headers", c.allowedHeaders)
+			if c.cfg.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", c.maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if c.exposedHeaders != "" {
+			h.Set("Access-Control-Expose-Headers", c.exposedHeaders)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setAllowOrigin sends "*" when any origin is allowed, which NewCORS only
+// permits without credentials, and otherwise echoes the origin.
+func (c *CORS) setAllowOrigin(h http.Header, origin string) {
+	if allowsAny(c.cfg.AllowedOrigins) {
+		h.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	h.Set("Access-Control-Allow-Origin", origin)
+	if c.cfg.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func allowsAny(origins []string) bool {
+	for _, allowed := range origins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CORS) originAllowed(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range c.cfg.AllowedOrigins {
+		allowed = strings.ToLower(allowed)
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		if scheme, host, ok := strings.Cut(allowed, "://*."); ok {
+			prefix := scheme + "://"
+			if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, "."+host) &&
+				len(origin) > len(prefix)+len(host)+1 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+	cors, err := NewCORS(CORSConfig{
+		AllowedOrigins: []string{"https://backoffice.amartha.com", "https://*.staging.amartha.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type", "X-Request-ID"},
+		ExposedHeaders: []string{"X-Request-ID"},
+		MaxAge:         10 * time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		preflight   bool
+		status      int
+		allowOrigin string
+	}{
+		{"no origin", http.MethodGet, "", false, http.StatusOK, ""},
+		{"allowed origin", http.MethodGet, "https://backoffice.amartha.com", false, http.StatusOK, "https://backoffice.amartha.com"},
+		{"wildcard subdomain", http.MethodGet, "https://ops.staging.amartha.com", false, http.StatusOK, "https://ops.staging.amartha.com"},
+		{"wildcard needs a label", http.MethodGet, "https://.staging.amartha.com", false, http.StatusOK, ""},
+		{"other origin", http.MethodGet, "https://evil.example", false, http.StatusOK, ""},
+		{"preflight", http.MethodOptions, "https://backoffice.amartha.com", true, http.StatusNoContent, "https://backoffice.amartha.com"},
+		{"preflight other origin", http.MethodOptions, "https://evil.example", true, http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/loans", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			cors.Handler(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, rec.Code)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("expected Allow-Origin %q, got %q", tt.allowOrigin, got)
+			}
+			if tt.preflight && tt.status == http.StatusNoContent {
+				if got := rec.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+					t.Errorf("expected Allow-Methods %q, got %q", "GET, POST", got)
+				}
+				if got := rec.Header().Get("Access-Control-Max-Age"); got != "600" {
+					t.Errorf("expected Max-Age 600, got %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	cors, err := NewCORS(CORSConfig{AllowedOrigins: []string{"*"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://app.example")
+	rec := httptest.NewRecorder()
+	cors.Handler(next).ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("expected Allow-Origin *, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("expected no Allow-Credentials, got %q", got)
+	}
+}
+
+func TestCORSCredentials(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	if _, err := NewCORS(CORSConfig{AllowedOrigins: []string{"https://app.example", "*"}, AllowCredentials: true}); err == nil {
+		t.Fatal("expected any origin with credentials to be rejected")
+	}
+
+	cors, err := NewCORS(CORSConfig{AllowedOrigins: []string{"https://app.example"}, AllowCredentials: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://app.example")
+	rec := httptest.NewRecorder()
+	cors.Handler(next).ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example" {
+		t.Errorf("expected the origin to be echoed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("expected Allow-Credentials true, got %q", got)
+	}
+}
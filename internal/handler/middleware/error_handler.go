@@ -6,7 +6,7 @@ import (
 	"runtime/debug"
 	"time"
 
-	"github.com/agunghallmanmaliki/amartha/internal/handler/dto"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
 )
 
 type responseWriter struct {
@@ -19,6 +19,15 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+func (rw *responseWriter) Flush() {
+	http.NewResponseController(rw.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 func Logger(logger *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -28,7 +37,7 @@ func Logger(logger *slog.Logger) func(http.Handler) http.Handler {
 
 			next.ServeHTTP(rw, r)
 
-			logger.Info("request completed",
+			logger.InfoContext(r.Context(), "request completed",
 				"method", r.Method,
 				"path", r.URL.Path,
 				"status", rw.status,
@@ -43,11 +52,16 @@ func Recovery(logger *slog.Logger) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
-					logger.Error("panic recovered",
+					// Deliberate aborts (e.g. a failed streaming export) must
+					// reach net/http so the connection is cut, not answered.
+					if err == http.ErrAbortHandler {
+						panic(err)
+					}
+					logger.ErrorContext(r.Context(), "panic recovered",
 						"error", err,
 						"stack", string(debug.Stack()),
 					)
-					dto.WriteError(w, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "An unexpected error occurred")
+					httperror.WriteError(w, httperror.InternalServerError("An unexpected error occurred"))
 				}
 			}()
 			next.ServeHTTP(w, r)
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/agunghallmanmaliki/amartha/internal/handler/dto"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+)
+
+// SparseFields honours ?fields=a,b,c by attaching the parsed fieldset to the
+// response writer, where dto.WriteJSON and dto.WriteJSONPaginated apply it.
+func SparseFields() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !r.URL.Query().Has("fields") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			fs, err := dto.ParseFieldset(r.URL.Query().Get("fields"))
+			if err != nil {
+				httperror.WriteError(w, httperror.New(http.StatusBadRequest, "INVALID_FIELDS", "Invalid fields parameter: "+err.Error()))
+				return
+			}
+
+			next.ServeHTTP(dto.WithFieldset(w, fs), r)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/ratelimit"
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+)
+
+func newTestRateLimiter(t *testing.T, limit int) http.Handler {
+	t.Helper()
+
+	trusted, err := requestctx.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policies := map[string]ratelimit.Policy{
+		ratelimit.DefaultPolicy: {Name: ratelimit.DefaultPolicy, Limit: limit, Period: time.Minute},
+	}
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(100), policies, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return RequestID()(Actor(trusted)(limiter.Limit(ok)))
+}
+
+func TestRateLimiterHeaders(t *testing.T) {
+	handler := newTestRateLimiter(t, 2)
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/loans", nil)
+		req.RemoteAddr = "203.0.113.9:41000"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		rec := serve()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status 200, got %d", i+1, rec.Code)
+		}
+		if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d: expected RateLimit-Limit 2, got %q", i+1, got)
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != wantRemaining {
+			t.Errorf("request %d: expected RateLimit-Remaining %s, got %q", i+1, wantRemaining, got)
+		}
+		if got := rec.Header().Get("RateLimit-Policy"); got != "2;w=60" {
+			t.Errorf("request %d: expected RateLimit-Policy 2;w=60, got %q", i+1, got)
+		}
+		if rec.Header().Get("Retry-After") != "" {
+			t.Errorf("request %d: expected no Retry-After on an allowed request", i+1)
+		}
+	}
+
+	rec := serve()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != httperror.ContentType {
+		t.Errorf("expected content type %q, got %q", httperror.ContentType, got)
+	}
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 || retryAfter > 30 {
+		t.Errorf("expected Retry-After between 1 and 30 seconds, got %q", rec.Header().Get("Retry-After"))
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("expected RateLimit-Remaining 0, got %q", got)
+	}
+	reset, err := strconv.Atoi(rec.Header().Get("RateLimit-Reset"))
+	if err != nil || reset < 1 || reset > 60 {
+		t.Errorf("expected RateLimit-Reset between 1 and 60 seconds, got %q", rec.Header().Get("RateLimit-Reset"))
+	}
+
+	var problem struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	if problem.Code != "RATE_LIMITED" {
+		t.Errorf("expected code RATE_LIMITED, got %q", problem.Code)
+	}
+}
+
+func TestRateLimiterClientKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		first      func(*http.Request)
+		second     func(*http.Request)
+		wantStatus int
+	}{
+		{
+			name: "rotating API keys share the client IP bucket",
+			first: func(r *http.Request) {
+				r.RemoteAddr = "203.0.113.9:41000"
+				r.Header.Set("X-API-Key", "key-1")
+			},
+			second: func(r *http.Request) {
+				r.RemoteAddr = "203.0.113.9:41001"
+				r.Header.Set("X-API-Key", "key-2")
+			},
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "different client IPs",
+			first:      func(r *http.Request) { r.RemoteAddr = "203.0.113.9:41000" },
+			second:     func(r *http.Request) { r.RemoteAddr = "203.0.113.10:41000" },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "forwarded client IP behind a trusted proxy",
+			first: func(r *http.Request) {
+				r.RemoteAddr = "10.0.0.5:41000"
+				r.Header.Set(requestctx.HeaderForwardedFor, "203.0.113.9")
+			},
+			second: func(r *http.Request) {
+				r.RemoteAddr = "10.0.0.6:41000"
+				r.Header.Set(requestctx.HeaderForwardedFor, "203.0.113.9")
+			},
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name: "verified actors behind one proxy",
+			first: func(r *http.Request) {
+				r.RemoteAddr = "10.0.0.5:41000"
+				r.Header.Set(requestctx.HeaderActorID, "officer-1")
+			},
+			second: func(r *http.Request) {
+				r.RemoteAddr = "10.0.0.5:41000"
+				r.Header.Set(requestctx.HeaderActorID, "officer-2")
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestRateLimiter(t, 1)
+
+			first := httptest.NewRequest(http.MethodGet, "/api/v1/loans", nil)
+			tt.first(first)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, first)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected first request to pass, got %d", rec.Code)
+			}
+
+			second := httptest.NewRequest(http.MethodGet, "/api/v1/loans", nil)
+			tt.second(second)
+			rec = httptest.NewRecorder()
+			handler.ServeHTTP(rec, second)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
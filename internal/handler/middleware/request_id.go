@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/netip"
+
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+	"github.com/google/uuid"
+)
+
+// RequestID accepts the caller's X-Request-ID (or generates one), echoes it on
+// the response and stores it in the request context.
+func RequestID() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(requestctx.HeaderRequestID)
+			if !requestctx.ValidRequestID(requestID) {
+				requestID = uuid.New().String()
+			}
+
+			w.Header().Set(requestctx.HeaderRequestID, requestID)
+
+			info := &requestctx.Info{RequestID: requestID}
+			next.ServeHTTP(w, r.WithContext(requestctx.NewContext(r.Context(), info)))
+		})
+	}
+}
+
+// Actor records the caller forwarded by a trusted proxy, and the client's
+// address. The actor headers can claim any identity or role, so a request
+// carrying them from any other peer is refused rather than served as
+// anonymous, which would hide a misrouted gateway.
+func Actor(trusted requestctx.TrustedProxies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			info := requestctx.FromContext(r.Context())
+			if info == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			peer := remoteAddr(r)
+			if !trusted.Trusts(peer) {
+				if len(r.Header.Values(requestctx.HeaderActorID)) > 0 || len(r.Header.Values(requestctx.HeaderActorRole)) > 0 {
+					httperror.WriteError(w, httperror.Forbidden("Actor headers are only accepted from trusted proxies"))
+					return
+				}
+				if peer.IsValid() {
+					info.ClientIP = peer.String()
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			info.ActorID = r.Header.Get(requestctx.HeaderActorID)
+			info.ActorRole = r.Header.Get(requestctx.HeaderActorRole)
+			info.ClientIP = trusted.ClientIP(peer, r.Header.Values(requestctx.HeaderForwardedFor)).String()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// remoteAddr returns the peer's address, or the zero Addr if the server's
+// listener does not give one.
+func remoteAddr(r *http.Request) netip.Addr {
+	if addrPort, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+		return addrPort.Addr().Unmap()
+	}
+	addr, _ := netip.ParseAddr(r.RemoteAddr)
+	return addr.Unmap()
+}
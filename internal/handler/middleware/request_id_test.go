@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"github.com/google/uuid"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{"caller id kept", "abc-123", true},
+		{"missing id generated", "", false},
+		{"id with spaces replaced", "abc 123", false},
+		{"overlong id replaced", strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = requestctx.RequestID(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(requestctx.HeaderRequestID, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			echoed := rec.Header().Get(requestctx.HeaderRequestID)
+			if echoed != seen {
+				t.Errorf("expected response header %q to match context %q", echoed, seen)
+			}
+			if tt.keep && seen != tt.incoming {
+				t.Errorf("expected %q to be kept, got %q", tt.incoming, seen)
+			}
+			if !tt.keep {
+				if _, err := uuid.Parse(seen); err != nil {
+					t.Errorf("expected a generated UUID, got %q", seen)
+				}
+			}
+		})
+	}
+}
+
+func TestActor(t *testing.T) {
+	trusted, err := requestctx.ParseTrustedProxies([]string{"10.0.0.0/8", "::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		headers      map[string]string
+		wantStatus   int
+		wantActor    string
+		wantRole     string
+		wantClientIP string
+	}{
+		{
+			name:       "trusted proxy",
+			remoteAddr: "10.0.0.5:41000",
+			headers: map[string]string{
+				requestctx.HeaderActorID: "officer-1", requestctx.HeaderActorRole: "field_officer",
+				requestctx.HeaderForwardedFor: "198.51.100.7, 203.0.113.9, 10.0.0.9",
+			},
+			wantStatus: http.StatusOK, wantActor: "officer-1", wantRole: "field_officer", wantClientIP: "203.0.113.9",
+		},
+		{
+			name:         "trusted proxy over IPv6 loopback",
+			remoteAddr:   "[::1]:41000",
+			headers:      map[string]string{requestctx.HeaderActorRole: "admin"},
+			wantStatus:   http.StatusOK,
+			wantRole:     "admin",
+			wantClientIP: "::1",
+		},
+		{
+			name:         "untrusted peer without actor headers",
+			remoteAddr:   "203.0.113.9:41000",
+			headers:      map[string]string{requestctx.HeaderForwardedFor: "10.0.0.1"},
+			wantStatus:   http.StatusOK,
+			wantClientIP: "203.0.113.9",
+		},
+		{
+			name:       "spoofed role",
+			remoteAddr: "203.0.113.9:41000",
+			headers:    map[string]string{requestctx.HeaderActorRole: "admin"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "spoofed empty actor id",
+			remoteAddr: "203.0.113.9:41000",
+			headers:    map[string]string{requestctx.HeaderActorID: ""},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info *requestctx.Info
+			handler := RequestID()(Actor(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				info = requestctx.FromContext(r.Context())
+			})))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if info != nil {
+					t.Error("expected the request to be refused before the handler")
+				}
+				return
+			}
+			if info.ActorID != tt.wantActor || info.ActorRole != tt.wantRole || info.ClientIP != tt.wantClientIP {
+				t.Errorf("expected actor %q role %q client %q, got %+v", tt.wantActor, tt.wantRole, tt.wantClientIP, info)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+)
+
+// RequireRole rejects callers whose role, as forwarded by a trusted proxy in
+// X-Actor-Role, is not one of roles. It relies on Actor, which refuses the
+// header from any other peer.
+func RequireRole(roles ...domain.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var role domain.Role
+			if info := requestctx.FromContext(r.Context()); info != nil {
+				role = domain.Role(info.ActorRole)
+			}
+
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			httperror.WriteError(w, httperror.Forbidden("The caller's role may not perform this operation"))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+)
+
+func TestRequireRole(t *testing.T) {
+	trusted, err := requestctx.ParseTrustedProxies([]string{"10.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := RequestID()(Actor(trusted)(RequireRole(domain.RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		role       string
+		wantStatus int
+	}{
+		{"admin through the gateway", "10.0.0.1:41000", "admin", http.StatusNoContent},
+		{"investor through the gateway", "10.0.0.1:41000", "investor", http.StatusForbidden},
+		{"no role", "10.0.0.1:41000", "", http.StatusForbidden},
+		{"admin claimed by the client", "203.0.113.9:41000", "admin", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/loan-products", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.role != "" {
+				req.Header.Set(requestctx.HeaderActorRole, tt.role)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
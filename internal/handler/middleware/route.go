@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Route records the matched route pattern on the request info and the server
+// span, so logs and traces group by endpoint rather than by loan ID. It must
+// run inside the mux, after a pattern has been matched.
+func Route() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Pattern != "" {
+				if info := requestctx.FromContext(r.Context()); info != nil {
+					info.Route = r.Pattern
+				}
+
+				span := trace.SpanFromContext(r.Context())
+				span.SetName(r.Pattern)
+				span.SetAttributes(attribute.String("http.route", r.Pattern))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	mw := SecurityHeaders(SecurityHeadersConfig{
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		ContentSecurityPolicy: "default-src 'none'",
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "no-referrer",
+	})
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name  string
+		setup func(r *http.Request)
+		hsts  string
+	}{
+		{"plain http", func(r *http.Request) {}, ""},
+		{"tls", func(r *http.Request) { r.TLS = &tls.ConnectionState{} }, "max-age=31536000; includeSubDomains"},
+		{"forwarded https", func(r *http.Request) { r.Header.Set("X-Forwarded-Proto", "https") }, "max-age=31536000; includeSubDomains"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/loans", nil)
+			tt.setup(req)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			h := rec.Header()
+			if got := h.Get("Strict-Transport-Security"); got != tt.hsts {
+				t.Errorf("expected HSTS %q, got %q", tt.hsts, got)
+			}
+			if got := h.Get("X-Content-Type-Options"); got != "nosniff" {
+				t.Errorf("expected nosniff, got %q", got)
+			}
+			if got := h.Get("X-Frame-Options"); got != "DENY" {
+				t.Errorf("expected X-Frame-Options DENY, got %q", got)
+			}
+			if got := h.Get("Content-Security-Policy"); got != "default-src 'none'" {
+				t.Errorf("expected CSP, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+)
+
+// Middleware wraps a matched route's handler.
+type Middleware func(http.Handler) http.Handler
+
+// RouteGroup registers Go 1.22 method+pattern routes under a shared path
+// prefix. Middleware added to a group runs after the route has matched, so
+// r.Pattern and path values are available to it, and applies to the group's
+// routes and to any group nested below it.
+type RouteGroup struct {
+	mux        *http.ServeMux
+	prefix     string
+	middleware []Middleware
+}
+
+func NewRouteGroup(mux *http.ServeMux, middleware ...Middleware) *RouteGroup {
+	return &RouteGroup{mux: mux, middleware: middleware}
+}
+
+// Group returns a child group rooted at prefix (e.g. "/api/v1").
+func (g *RouteGroup) Group(prefix string, middleware ...Middleware) *RouteGroup {
+	return &RouteGroup{
+		mux:        g.mux,
+		prefix:     g.prefix + prefix,
+		middleware: append(append([]Middleware(nil), g.middleware...), middleware...),
+	}
+}
+
+// Use adds middleware to routes registered on the group afterwards.
+func (g *RouteGroup) Use(middleware ...Middleware) {
+	g.middleware = append(g.middleware, middleware...)
+}
+
+// Handle registers h for method and path relative to the group prefix. The
+// full pattern, e.g. "GET /api/v1/loans/{id}", is what r.Pattern reports.
+func (g *RouteGroup) Handle(method, path string, h http.Handler) {
+	for i := len(g.middleware) - 1; i >= 0; i-- {
+		h = g.middleware[i](h)
+	}
+	g.mux.Handle(method+" "+g.prefix+path, h)
+}
+
+func (g *RouteGroup) HandleFunc(method, path string, h http.HandlerFunc) {
+	g.Handle(method, path, h)
+}
+
+// problemFallback answers requests that match no route with problem+json
+// 404 and 405 responses; for 405 the mux's Allow header is kept. Anything else
+// the mux produces without a route (e.g. redirects to a cleaned path) is
+// passed through unchanged.
+func problemFallback(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, pattern := mux.Handler(r)
+		if pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &fallbackRecorder{header: make(http.Header), status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+
+		switch rec.status {
+		case http.StatusNotFound:
+			httperror.WriteError(w, httperror.NotFound("No route matches "+r.URL.Path))
+		case http.StatusMethodNotAllowed:
+			w.Header().Set("Allow", rec.header.Get("Allow"))
+			httperror.WriteError(w, httperror.MethodNotAllowed("Method "+r.Method+" is not allowed on "+r.URL.Path))
+		default:
+			for k, v := range rec.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(rec.status)
+			w.Write(rec.body.Bytes())
+		}
+	})
+}
+
+// fallbackRecorder captures the mux's built-in not-found, method-not-allowed
+// and redirect responses so they can be rewritten.
+type fallbackRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *fallbackRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *fallbackRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *fallbackRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
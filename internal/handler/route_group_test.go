@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+)
+
+func TestRouteGroup(t *testing.T) {
+	mux := http.NewServeMux()
+
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	root := NewRouteGroup(mux, tag("root"))
+	v1 := root.Group("/api/v1", tag("v1"))
+	v1.HandleFunc(http.MethodGet, "/loans/{id}", func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, r.Pattern+" "+r.PathValue("id"))
+	})
+
+	problemFallback(mux).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/loans/42", nil))
+
+	expected := []string{"root", "v1", "GET /api/v1/loans/{id} 42"}
+	if len(order) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, order)
+			break
+		}
+	}
+}
+
+func TestProblemFallback(t *testing.T) {
+	mux := http.NewServeMux()
+	root := NewRouteGroup(mux)
+	root.HandleFunc(http.MethodPost, "/loans", func(w http.ResponseWriter, r *http.Request) {})
+	root.HandleFunc(http.MethodGet, "/loans", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		code   string
+		allow  string
+	}{
+		{"unknown path", http.MethodGet, "/unknown", http.StatusNotFound, "NOT_FOUND", ""},
+		{"wrong method", http.MethodDelete, "/loans", http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "GET, HEAD, POST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			problemFallback(mux).ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != httperror.ContentType {
+				t.Errorf("expected content type %s, got %s", httperror.ContentType, ct)
+			}
+			if allow := rec.Header().Get("Allow"); allow != tt.allow {
+				t.Errorf("expected Allow %q, got %q", tt.allow, allow)
+			}
+
+			var problem httperror.HTTPError
+			if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+				t.Fatalf("failed to decode problem: %v", err)
+			}
+			if problem.Code != tt.code {
+				t.Errorf("expected code %s, got %s", tt.code, problem.Code)
+			}
+		})
+	}
+}
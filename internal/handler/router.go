@@ -3,108 +3,116 @@ package handler
 import (
 	"log/slog"
 	"net/http"
-	"strings"
 
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
 	"github.com/agunghallmanmaliki/amartha/internal/handler/middleware"
+	"github.com/agunghallmanmaliki/amartha/internal/health"
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
 )
 
 type Router struct {
-	mux     *http.ServeMux
-	handler *LoanHandler
-	logger  *slog.Logger
+	mux             *http.ServeMux
+	handler         *LoanHandler
+	health          *health.Checker
+	rateLimiter     *middleware.RateLimiter
+	cors            *middleware.CORS
+	securityHeaders middleware.SecurityHeadersConfig
+	uploads         http.Handler
+	trustedProxies  requestctx.TrustedProxies
+	logger          *slog.Logger
 }
 
-func NewRouter(handler *LoanHandler, logger *slog.Logger) *Router {
+// NewRouter builds the API router. rateLimiter and cors may be nil to disable
+// rate limiting and cross-origin access. uploads serves signed download URLs
+// under /uploads/ and is nil when storage serves them itself, as S3 does.
+// Only trustedProxies may forward the caller's identity.
+func NewRouter(
+	handler *LoanHandler,
+	health *health.Checker,
+	rateLimiter *middleware.RateLimiter,
+	cors *middleware.CORS,
+	securityHeaders middleware.SecurityHeadersConfig,
+	uploads http.Handler,
+	trustedProxies requestctx.TrustedProxies,
+	logger *slog.Logger,
+) *Router {
 	return &Router{
-		mux:     http.NewServeMux(),
-		handler: handler,
-		logger:  logger,
+		mux:             http.NewServeMux(),
+		handler:         handler,
+		health:          health,
+		rateLimiter:     rateLimiter,
+		cors:            cors,
+		securityHeaders: securityHeaders,
+		uploads:         uploads,
+		trustedProxies:  trustedProxies,
+		logger:          logger,
 	}
 }
 
 func (r *Router) Setup() http.Handler {
-	// Register routes
-	r.mux.HandleFunc("/api/v1/loans", r.loansHandler)
-	r.mux.HandleFunc("/api/v1/loans/", r.loanDetailHandler)
+	root := NewRouteGroup(r.mux, middleware.Route())
 
-	// Health check
-	r.mux.HandleFunc("/health", func(w http.ResponseWriter, req *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
-
-	// Serve static files for uploads
-	r.mux.Handle("/uploads/", http.StripPrefix("/uploads/", http.FileServer(http.Dir("./uploads"))))
-
-	// Apply middleware
-	var handler http.Handler = r.mux
-	handler = middleware.Logger(r.logger)(handler)
-	handler = middleware.Recovery(r.logger)(handler)
-
-	return handler
-}
-
-func (r *Router) loansHandler(w http.ResponseWriter, req *http.Request) {
-	switch req.Method {
-	case http.MethodPost:
-		r.handler.CreateLoan(w, req)
-	case http.MethodGet:
-		r.handler.ListLoans(w, req)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// API v1
+	v1 := root.Group("/api/v1", middleware.SparseFields())
+	if r.rateLimiter != nil {
+		v1.Use(r.rateLimiter.Limit)
 	}
-}
+	v1.HandleFunc(http.MethodPost, "/loans", r.handler.CreateLoan)
+	v1.HandleFunc(http.MethodGet, "/loans", r.handler.ListLoans)
+	v1.HandleFunc(http.MethodPost, "/loans:batch", r.handler.CreateLoanBatch)
+	v1.HandleFunc(http.MethodPost, "/loans:import", r.handler.ImportLoansCSV)
+	v1.HandleFunc(http.MethodGet, "/loans:export", r.handler.ExportLoans)
+	v1.HandleFunc(http.MethodGet, "/loans/{id}", r.handler.GetLoan)
+	v1.HandleFunc(http.MethodPost, "/loans/{id}/approve", r.handler.ApproveLoan)
+	v1.HandleFunc(http.MethodPost, "/loans/{id}/investments", r.handler.AddInvestment)
+	v1.HandleFunc(http.MethodGet, "/loans/{id}/investments", r.handler.ListInvestments)
+	v1.HandleFunc(http.MethodPost, "/loans/{id}/disburse", r.handler.DisburseLoan)
+	v1.HandleFunc(http.MethodGet, "/loans/{id}/approval", r.handler.GetApproval)
+	v1.HandleFunc(http.MethodGet, "/loans/{id}/disbursement", r.handler.GetDisbursement)
+	v1.HandleFunc(http.MethodGet, "/loans/{id}/agreement-letter", r.handler.GetAgreementLetter)
+	v1.HandleFunc(http.MethodPost, "/loans/{id}/agreement-letter:verify", r.handler.VerifyAgreementLetter)
+	v1.HandleFunc(http.MethodGet, "/agreement-letters/signing-certificate", r.handler.GetSigningCertificate)
+	v1.HandleFunc(http.MethodGet, "/investments:export", r.handler.ExportInvestments)
+	v1.HandleFunc(http.MethodGet, "/search", r.handler.Search)
 
-func (r *Router) loanDetailHandler(w http.ResponseWriter, req *http.Request) {
-	path := strings.TrimPrefix(req.URL.Path, "/api/v1/loans/")
-	parts := strings.Split(path, "/")
+	// Loan documents are limited to staff; the service further limits new
+	// versions of each document to the roles that may upload it
+	staff := v1.Group("", middleware.RequireRole(domain.StaffRoles...))
+	staff.HandleFunc(http.MethodGet, "/loans/{id}/documents", r.handler.ListDocuments)
+	staff.HandleFunc(http.MethodGet, "/documents/{id}", r.handler.GetDocument)
+	staff.HandleFunc(http.MethodGet, "/documents/{id}/content", r.handler.GetDocumentContent)
+	staff.HandleFunc(http.MethodPost, "/documents/{id}/versions", r.handler.SupersedeDocument)
 
-	if len(parts) == 0 || parts[0] == "" {
-		http.Error(w, "Not found", http.StatusNotFound)
-		return
-	}
+	// Loan product catalogue; changes are limited to admins
+	v1.HandleFunc(http.MethodGet, "/loan-products", r.handler.ListLoanProducts)
+	v1.HandleFunc(http.MethodGet, "/loan-products/{id}", r.handler.GetLoanProduct)
+	admin := v1.Group("", middleware.RequireRole(domain.RoleAdmin))
+	admin.HandleFunc(http.MethodPost, "/loan-products", r.handler.CreateLoanProduct)
+	admin.HandleFunc(http.MethodPut, "/loan-products/{id}", r.handler.UpdateLoanProduct)
+	admin.HandleFunc(http.MethodDelete, "/loan-products/{id}", r.handler.DeleteLoanProduct)
 
-	// /api/v1/loans/{id}
-	if len(parts) == 1 {
-		switch req.Method {
-		case http.MethodGet:
-			r.handler.GetLoan(w, req)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-		return
+	// Health checks
+	root.HandleFunc(http.MethodGet, "/livez", r.health.Live)
+	root.HandleFunc(http.MethodGet, "/readyz", r.health.Ready)
+	root.HandleFunc(http.MethodGet, "/health", r.health.Ready)
+
+	// Serve static files for uploads
+	if r.uploads != nil {
+		root.Handle(http.MethodGet, "/uploads/", http.StripPrefix("/uploads/", r.uploads))
 	}
 
-	// /api/v1/loans/{id}/{action}
-	if len(parts) == 2 {
-		action := parts[1]
-		switch action {
-		case "approve":
-			if req.Method == http.MethodPost {
-				r.handler.ApproveLoan(w, req)
-			} else {
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			}
-		case "investments":
-			switch req.Method {
-			case http.MethodPost:
-				r.handler.AddInvestment(w, req)
-			case http.MethodGet:
-				r.handler.ListInvestments(w, req)
-			default:
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			}
-		case "disburse":
-			if req.Method == http.MethodPost {
-				r.handler.DisburseLoan(w, req)
-			} else {
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			}
-		default:
-			http.Error(w, "Not found", http.StatusNotFound)
-		}
-		return
+	// Apply middleware
+	handler := problemFallback(r.mux)
+	if r.cors != nil {
+		handler = r.cors.Handler(handler)
 	}
+	handler = middleware.SecurityHeaders(r.securityHeaders)(handler)
+	handler = middleware.Logger(r.logger)(handler)
+	handler = middleware.Recovery(r.logger)(handler)
+	handler = middleware.Compress()(handler)
+	handler = middleware.Actor(r.trustedProxies)(handler)
+	handler = middleware.RequestID()(handler)
+	handler = middleware.Tracing()(handler)
 
-	http.Error(w, "Not found", http.StatusNotFound)
+	return handler
 }
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/agunghallmanmaliki/amartha/internal/handler/dto"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+)
+
+const (
+	minSearchLength = 2
+	maxSearchLength = 200
+)
+
+// Search looks loans up by partial borrower id, name, notes or the id of the
+// staff who approved or disbursed them, best match first.
+func (h *LoanHandler) Search(w http.ResponseWriter, r *http.Request) {
+	text := strings.TrimSpace(r.URL.Query().Get("q"))
+	switch n := utf8.RuneCountInString(text); {
+	case n == 0:
+		httperror.WriteError(w, httperror.Validation(httperror.Required("q")))
+		return
+	case n < minSearchLength || n > maxSearchLength:
+		httperror.WriteError(w, httperror.Validation(httperror.FieldError{
+			Field:   "q",
+			Rule:    "len",
+			Param:   strconv.Itoa(minSearchLength) + "-" + strconv.Itoa(maxSearchLength),
+			Message: "q must be between " + strconv.Itoa(minSearchLength) + " and " + strconv.Itoa(maxSearchLength) + " characters long",
+		}))
+		return
+	}
+
+	filter := parseLoanFilter(r, 10)
+	query := repository.SearchQuery{Text: text, Limit: filter.Limit, Offset: filter.Offset}
+
+	results, total, err := h.loanService.SearchLoans(r.Context(), query)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	dto.WriteJSONPaginated(w, http.StatusOK, dto.ToSearchResultResponses(results, actorRole(r)), total, query.Limit, query.Offset)
+}
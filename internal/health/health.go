@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// CheckFunc reports whether a dependency is usable. A nil error means healthy.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// CheckResult is what callers see of a check. Probes are unauthenticated, so
+// why a check failed is only logged.
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Checker serves liveness and readiness probes. Readiness runs every
+// registered dependency check and fails once shutdown has begun.
+type Checker struct {
+	checks  []check
+	timeout time.Duration
+	ready   atomic.Bool
+	logger  *slog.Logger
+}
+
+func NewChecker(timeout time.Duration, logger *slog.Logger) *Checker {
+	c := &Checker{timeout: timeout, logger: logger}
+	c.ready.Store(true)
+	return c
+}
+
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// SetReady flips readiness; main sets it to false when draining for shutdown.
+func (c *Checker) SetReady(ready bool) {
+	c.ready.Store(ready)
+}
+
+func (c *Checker) Live(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, http.StatusOK, &Report{Status: StatusOK})
+}
+
+func (c *Checker) Ready(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeReport(w, status, report)
+}
+
+// Run executes all checks concurrently, each bounded by the checker timeout.
+func (c *Checker) Run(ctx context.Context) *Report {
+	report := &Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(c.checks)+1),
+	}
+
+	if !c.ready.Load() {
+		report.Status = StatusFail
+		report.Checks["shutdown"] = CheckResult{Status: StatusFail}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, chk := range c.checks {
+		wg.Add(1)
+		go func(chk check) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := chk.fn(checkCtx)
+			result := CheckResult{
+				Status:    StatusOK,
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result.Status = StatusFail
+				c.logger.WarnContext(ctx, "readiness check failed", "check", chk.name, "error", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[chk.name] = result
+			if err != nil {
+				report.Status = StatusFail
+			}
+		}(chk)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func writeReport(w http.ResponseWriter, status int, report *Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,93 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func probe(t *testing.T, handler http.HandlerFunc, path string) (int, Report, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	body := rec.Body.String()
+	var report Report
+	if err := json.Unmarshal([]byte(body), &report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	return rec.Code, report, body
+}
+
+func TestLiveAndReady(t *testing.T) {
+	var logs bytes.Buffer
+	checker := NewChecker(time.Second, slog.New(slog.NewTextHandler(&logs, nil)))
+	checker.Register("database", func(context.Context) error { return nil })
+	checker.Register("migrations", func(context.Context) error {
+		return errors.New("schema version 3 is behind expected 4")
+	})
+
+	status, report, _ := probe(t, checker.Live, "/livez")
+	if status != http.StatusOK || report.Status != StatusOK {
+		t.Errorf("expected live 200 ok with a failing dependency, got %d %s", status, report.Status)
+	}
+	if len(report.Checks) != 0 {
+		t.Errorf("expected liveness to run no checks, got %v", report.Checks)
+	}
+
+	status, report, body := probe(t, checker.Ready, "/readyz")
+	if status != http.StatusServiceUnavailable || report.Status != StatusFail {
+		t.Errorf("expected ready 503 fail, got %d %s", status, report.Status)
+	}
+	if report.Checks["database"].Status != StatusOK || report.Checks["migrations"].Status != StatusFail {
+		t.Errorf("expected database ok and migrations fail, got %v", report.Checks)
+	}
+	if strings.Contains(body, "schema version") {
+		t.Errorf("expected error details to be withheld, got %s", body)
+	}
+	if !strings.Contains(logs.String(), "schema version 3 is behind expected 4") {
+		t.Errorf("expected error details to be logged, got %q", logs.String())
+	}
+}
+
+func TestReadyDraining(t *testing.T) {
+	checker := NewChecker(time.Second, slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+	checker.Register("database", func(context.Context) error { return nil })
+
+	if status, _, _ := probe(t, checker.Ready, "/readyz"); status != http.StatusOK {
+		t.Fatalf("expected ready 200 before draining, got %d", status)
+	}
+
+	checker.SetReady(false)
+	status, report, _ := probe(t, checker.Ready, "/readyz")
+	if status != http.StatusServiceUnavailable || report.Checks["shutdown"].Status != StatusFail {
+		t.Errorf("expected 503 with a failing shutdown check, got %d %v", status, report.Checks)
+	}
+	if status, _, _ := probe(t, checker.Live, "/livez"); status != http.StatusOK {
+		t.Errorf("expected live 200 while draining, got %d", status)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	checker := NewChecker(20*time.Millisecond, slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+	checker.Register("storage", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	start := time.Now()
+	report := checker.Run(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the check to be cut off at the timeout, took %v", elapsed)
+	}
+	if report.Status != StatusFail || report.Checks["storage"].Status != StatusFail {
+		t.Errorf("expected a timed out check to fail, got %+v", report)
+	}
+}
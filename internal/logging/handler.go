@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextHandler decorates records with the request id, route, actor and
+// trace identifiers carried by the context passed to the *Context logging
+// methods (InfoContext, ErrorContext, ...).
+type ContextHandler struct {
+	slog.Handler
+}
+
+func NewContextHandler(h slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: h}
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if info := requestctx.FromContext(ctx); info != nil {
+		if info.RequestID != "" {
+			r.AddAttrs(slog.String("request_id", info.RequestID))
+		}
+		if info.Route != "" {
+			r.AddAttrs(slog.String("route", info.Route))
+		}
+		if info.ActorID != "" {
+			r.AddAttrs(slog.String("actor_id", info.ActorID))
+		}
+		if info.ActorRole != "" {
+			r.AddAttrs(slog.String("actor_role", info.ActorRole))
+		}
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func logRecord(t *testing.T, ctx context.Context, logger func(*slog.Logger) *slog.Logger) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	base := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+	logger(base).InfoContext(ctx, "hello")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to parse log record %q: %v", buf.String(), err)
+	}
+	return record
+}
+
+func TestContextHandler(t *testing.T) {
+	info := &requestctx.Info{RequestID: "req-1", Route: "GET /loans/{id}", ActorID: "officer-1", ActorRole: "admin"}
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa},
+	})
+	ctx := trace.ContextWithSpanContext(requestctx.NewContext(context.Background(), info), spanContext)
+
+	record := logRecord(t, ctx, func(l *slog.Logger) *slog.Logger { return l })
+	want := map[string]string{
+		"request_id": "req-1",
+		"route":      "GET /loans/{id}",
+		"actor_id":   "officer-1",
+		"actor_role": "admin",
+		"trace_id":   spanContext.TraceID().String(),
+		"span_id":    spanContext.SpanID().String(),
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("expected %s=%q, got %v", key, value, record[key])
+		}
+	}
+}
+
+func TestContextHandlerWithoutContext(t *testing.T) {
+	record := logRecord(t, context.Background(), func(l *slog.Logger) *slog.Logger { return l })
+	for _, key := range []string{"request_id", "route", "actor_id", "actor_role", "trace_id", "span_id"} {
+		if _, ok := record[key]; ok {
+			t.Errorf("expected no %s without request context, got %v", key, record[key])
+		}
+	}
+}
+
+func TestContextHandlerKeepsDecorating(t *testing.T) {
+	ctx := requestctx.NewContext(context.Background(), &requestctx.Info{RequestID: "req-2"})
+
+	record := logRecord(t, ctx, func(l *slog.Logger) *slog.Logger {
+		return l.With("component", "test").WithGroup("details")
+	})
+	if record["component"] != "test" {
+		t.Errorf("expected attribute added by With, got %v", record["component"])
+	}
+	details, _ := record["details"].(map[string]any)
+	if details["request_id"] != "req-2" {
+		t.Errorf("expected request_id after WithGroup, got %v", record)
+	}
+}
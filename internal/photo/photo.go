@@ -0,0 +1,52 @@
+// Package photo reads what a picture records about how it was taken.
+package photo
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/rwcarlsen/goexif/exif"
+)
+
+// Metadata reads the capture time, GPS position and camera from the EXIF
+// data at the start of a JPEG. Anything missing or unreadable is left empty,
+// as is everything for other formats. Capture times without a zone are read
+// in the server's local time.
+func Metadata(head []byte) domain.PhotoMetadata {
+	var metadata domain.PhotoMetadata
+
+	x, err := exif.Decode(bytes.NewReader(head))
+	if err != nil && (x == nil || exif.IsCriticalError(err)) {
+		return metadata
+	}
+
+	if capturedAt, err := x.DateTime(); err == nil {
+		metadata.CapturedAt = &capturedAt
+	}
+	if lat, lon, err := x.LatLong(); err == nil && validPosition(lat, lon) {
+		metadata.Location = &domain.GeoPoint{Latitude: lat, Longitude: lon}
+	}
+	metadata.DeviceMake = stringTag(x, exif.Make)
+	metadata.DeviceModel = stringTag(x, exif.Model)
+
+	return metadata
+}
+
+func stringTag(x *exif.Exif, name exif.FieldName) string {
+	tag, err := x.Get(name)
+	if err != nil {
+		return ""
+	}
+	s, err := tag.StringVal()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(s, "\x00"))
+}
+
+// validPosition rejects NaN and out of range coordinates, which cameras
+// write when they have no fix.
+func validPosition(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180 && !(lat == 0 && lon == 0)
+}
@@ -0,0 +1,149 @@
+package photo
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// ifdEntry is a TIFF directory entry; data longer than four bytes is placed
+// after the directory.
+type ifdEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+const (
+	typeASCII    = 2
+	typeLong     = 4
+	typeRational = 5
+)
+
+func asciiEntry(tag uint16, s string) ifdEntry {
+	return ifdEntry{tag, typeASCII, uint32(len(s) + 1), append([]byte(s), 0)}
+}
+
+func pointerEntry(tag uint16, offset uint32) ifdEntry {
+	return ifdEntry{tag, typeLong, 1, binary.LittleEndian.AppendUint32(nil, offset)}
+}
+
+// degreesEntry writes |deg| as degrees, minutes and hundredths of seconds.
+func degreesEntry(tag uint16, deg float64) ifdEntry {
+	deg = math.Abs(deg)
+	d := math.Floor(deg)
+	m := math.Floor((deg - d) * 60)
+	s := math.Round(((deg-d)*60 - m) * 60 * 100)
+	var data []byte
+	for _, r := range [][2]uint32{{uint32(d), 1}, {uint32(m), 1}, {uint32(s), 100}} {
+		data = binary.LittleEndian.AppendUint32(data, r[0])
+		data = binary.LittleEndian.AppendUint32(data, r[1])
+	}
+	return ifdEntry{tag, typeRational, 3, data}
+}
+
+func ifdSize(entries []ifdEntry) uint32 {
+	size := uint32(2 + 12*len(entries) + 4)
+	for _, e := range entries {
+		if len(e.data) > 4 {
+			size += uint32(len(e.data) + len(e.data)%2)
+		}
+	}
+	return size
+}
+
+func appendIFD(b []byte, offset uint32, entries []ifdEntry) []byte {
+	data := offset + uint32(2+12*len(entries)+4)
+	var values []byte
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(entries)))
+	for _, e := range entries {
+		b = binary.LittleEndian.AppendUint16(b, e.tag)
+		b = binary.LittleEndian.AppendUint16(b, e.typ)
+		b = binary.LittleEndian.AppendUint32(b, e.count)
+		if len(e.data) <= 4 {
+			b = append(b, e.data...)
+			b = append(b, make([]byte, 4-len(e.data))...)
+			continue
+		}
+		b = binary.LittleEndian.AppendUint32(b, data+uint32(len(values)))
+		values = append(values, e.data...)
+		if len(e.data)%2 == 1 {
+			values = append(values, 0)
+		}
+	}
+	b = binary.LittleEndian.AppendUint32(b, 0)
+	return append(b, values...)
+}
+
+// exifJPEG returns a minimal JPEG whose EXIF records the given capture time,
+// position and camera.
+func exifJPEG(capturedAt string, lat, lon float64, deviceMake, deviceModel string) []byte {
+	ifd0 := []ifdEntry{asciiEntry(0x010f, deviceMake), asciiEntry(0x0110, deviceModel), pointerEntry(0x8769, 0), pointerEntry(0x8825, 0)}
+	exifIFD := []ifdEntry{asciiEntry(0x9003, capturedAt)}
+	latRef, lonRef := "N", "E"
+	if lat < 0 {
+		latRef = "S"
+	}
+	if lon < 0 {
+		lonRef = "W"
+	}
+	gpsIFD := []ifdEntry{asciiEntry(0x0001, latRef), degreesEntry(0x0002, lat), asciiEntry(0x0003, lonRef), degreesEntry(0x0004, lon)}
+
+	exifOffset := 8 + ifdSize(ifd0)
+	gpsOffset := exifOffset + ifdSize(exifIFD)
+	ifd0[2] = pointerEntry(0x8769, exifOffset)
+	ifd0[3] = pointerEntry(0x8825, gpsOffset)
+
+	tiff := []byte("II*\x00")
+	tiff = binary.LittleEndian.AppendUint32(tiff, 8)
+	tiff = appendIFD(tiff, 8, ifd0)
+	tiff = appendIFD(tiff, exifOffset, exifIFD)
+	tiff = appendIFD(tiff, gpsOffset, gpsIFD)
+
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+	jpeg := []byte{0xff, 0xd8, 0xff, 0xe1}
+	jpeg = binary.BigEndian.AppendUint16(jpeg, uint16(len(app1)+2))
+	jpeg = append(jpeg, app1...)
+	return append(jpeg, 0xff, 0xd9)
+}
+
+func TestMetadata(t *testing.T) {
+	metadata := Metadata(exifJPEG("2026:03:10 09:30:00", -6.175392, 106.827153, "Samsung", "SM-A155F"))
+
+	want := time.Date(2026, 3, 10, 9, 30, 0, 0, time.Local)
+	if metadata.CapturedAt == nil || !metadata.CapturedAt.Equal(want) {
+		t.Errorf("expected capture time %v, got %v", want, metadata.CapturedAt)
+	}
+	if metadata.Location == nil {
+		t.Fatal("expected a location")
+	}
+	if math.Abs(metadata.Location.Latitude-(-6.175392)) > 1e-5 || math.Abs(metadata.Location.Longitude-106.827153) > 1e-5 {
+		t.Errorf("expected location -6.175392,106.827153, got %+v", *metadata.Location)
+	}
+	if metadata.DeviceMake != "Samsung" || metadata.DeviceModel != "SM-A155F" {
+		t.Errorf("expected device Samsung SM-A155F, got %q %q", metadata.DeviceMake, metadata.DeviceModel)
+	}
+}
+
+func TestMetadataWithoutEXIF(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")},
+		{"jpeg without exif", []byte{0xff, 0xd8, 0xff, 0xe0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0, 1, 1, 0, 0, 1, 0, 1, 0, 0, 0xff, 0xd9}},
+		{"truncated exif", exifJPEG("2026:03:10 09:30:00", 1, 1, "a", "b")[:40]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := Metadata(tt.data)
+			if metadata.CapturedAt != nil || metadata.Location != nil || metadata.DeviceMake != "" || metadata.DeviceModel != "" {
+				t.Errorf("expected no metadata, got %+v", metadata)
+			}
+		})
+	}
+}
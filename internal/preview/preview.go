@@ -0,0 +1,249 @@
+// Package preview renders small previews of uploaded files so reviewers do
+// not have to download the originals: thumbnails of images and a summary
+// card for PDFs.
+package preview
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"rsc.io/pdf"
+)
+
+const (
+	// MaxDimension bounds the width and height of a preview.
+	MaxDimension = 320
+
+	// MaxSourceSize is the largest file a preview is rendered from.
+	MaxSourceSize = 64 * 1024 * 1024
+
+	// maxPixels bounds the decoded size of an image, so a small file that
+	// claims huge dimensions is not decoded.
+	maxPixels = 50_000_000
+
+	thumbnailQuality = 80
+)
+
+// ErrUnsupported is returned for content types no preview is rendered for.
+var ErrUnsupported = errors.New("preview: unsupported content type")
+
+// Rendering is an encoded preview. PageCount is set for PDFs.
+type Rendering struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+	PageCount   int
+}
+
+// Supports reports whether previews are rendered for contentType.
+func Supports(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "application/pdf":
+		return true
+	}
+	return false
+}
+
+// Render renders a preview of data, a file of the given content type.
+func Render(contentType string, data []byte) (*Rendering, error) {
+	switch contentType {
+	case "image/jpeg", "image/png":
+		return thumbnail(data)
+	case "application/pdf":
+		return pdfCard(data)
+	default:
+		return nil, ErrUnsupported
+	}
+}
+
+// thumbnail scales an image down to fit MaxDimension, never up, and encodes
+// it as a JPEG on a white background.
+func thumbnail(data []byte) (*Rendering, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("image is %dx%d, more than %d pixels", config.Width, config.Height, maxPixels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	width, height := fit(config.Width, config.Height)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return &Rendering{Data: buf.Bytes(), ContentType: "image/jpeg", Extension: ".jpg", Width: width, Height: height}, nil
+}
+
+func fit(width, height int) (int, int) {
+	if width <= MaxDimension && height <= MaxDimension {
+		return width, height
+	}
+	if width >= height {
+		return MaxDimension, max(1, height*MaxDimension/width)
+	}
+	return max(1, width*MaxDimension/height), MaxDimension
+}
+
+// pdfInfo is what a PDF's trailer says about it.
+type pdfInfo struct {
+	version   string
+	pages     int
+	title     string
+	author    string
+	producer  string
+	createdAt string
+}
+
+// pdfCard renders a PNG card summarising the PDF: its title, author, page
+// count, version, creation date and producer.
+func pdfCard(data []byte) (*Rendering, error) {
+	info, err := readPDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	title := info.title
+	if title == "" {
+		title = "Untitled document"
+	}
+	pages := strconv.Itoa(info.pages) + " pages"
+	if info.pages == 1 {
+		pages = "1 page"
+	}
+	lines := []string{title, ""}
+	if info.author != "" {
+		lines = append(lines, "Author: "+info.author)
+	}
+	lines = append(lines, pages, "PDF "+info.version)
+	if info.createdAt != "" {
+		lines = append(lines, "Created: "+info.createdAt)
+	}
+	if info.producer != "" {
+		lines = append(lines, "Producer: "+info.producer)
+	}
+
+	width, height := MaxDimension, MaxDimension*99/70 // A4 proportions
+	card := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(card, card.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(card, image.Rect(0, 0, width, 32), image.NewUniform(color.RGBA{0xc6, 0x28, 0x28, 0xff}), image.Point{}, draw.Src)
+	border := image.NewUniform(color.Gray{0xbb})
+	for _, r := range []image.Rectangle{
+		image.Rect(0, 0, width, 1), image.Rect(0, height-1, width, height),
+		image.Rect(0, 0, 1, height), image.Rect(width-1, 0, width, height),
+	} {
+		draw.Draw(card, r, border, image.Point{}, draw.Src)
+	}
+
+	face := basicfont.Face7x13
+	const margin = 12
+	maxChars := (width - 2*margin) / 7
+	drawer := &font.Drawer{Dst: card, Src: image.White, Face: face, Dot: fixed.P(margin, 21)}
+	drawer.DrawString("PDF")
+
+	drawer.Src = image.Black
+	y := 32 + 24
+	for _, line := range lines {
+		for _, part := range wrap(line, maxChars) {
+			if y > height-margin {
+				break
+			}
+			drawer.Dot = fixed.P(margin, y)
+			drawer.DrawString(part)
+			y += 16
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, card); err != nil {
+		return nil, fmt.Errorf("failed to encode preview: %w", err)
+	}
+	return &Rendering{Data: buf.Bytes(), ContentType: "image/png", Extension: ".png", Width: width, Height: height, PageCount: info.pages}, nil
+}
+
+// readPDF reads the PDF's page count and document information. The parser
+// panics on some malformed files; that is reported as an error.
+func readPDF(data []byte) (info pdfInfo, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to read pdf: %v", r)
+		}
+	}()
+
+	if !bytes.HasPrefix(data, []byte("%PDF-")) || len(data) < 8 {
+		return info, errors.New("failed to read pdf: missing header")
+	}
+	info.version = string(data[5:8])
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return info, fmt.Errorf("failed to read pdf: %w", err)
+	}
+	info.pages = reader.NumPage()
+
+	doc := reader.Trailer().Key("Info")
+	info.title = clean(doc.Key("Title").Text())
+	info.author = clean(doc.Key("Author").Text())
+	info.producer = clean(doc.Key("Producer").Text())
+	if created, ok := pdfDate(doc.Key("CreationDate").Text()); ok {
+		info.createdAt = created.Format("2006-01-02")
+	}
+	return info, nil
+}
+
+// pdfDate parses the date part of a PDF date string, D:YYYYMMDDHHmmSS...
+func pdfDate(s string) (time.Time, bool) {
+	s = strings.TrimPrefix(s, "D:")
+	if len(s) < 8 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("20060102", s[:8])
+	return t, err == nil
+}
+
+// clean keeps the printable ASCII the card's font can draw.
+func clean(s string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, s))
+}
+
+// wrap splits s into lines of at most n characters, at spaces where it can.
+func wrap(s string, n int) []string {
+	var lines []string
+	for len(s) > n {
+		cut := strings.LastIndex(s[:n+1], " ")
+		if cut <= 0 {
+			cut = n
+		}
+		lines = append(lines, strings.TrimSpace(s[:cut]))
+		s = strings.TrimSpace(s[cut:])
+	}
+	return append(lines, s)
+}
@@ -0,0 +1,177 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodedImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, height/2, color.NRGBA{R: 0xff, A: 0xff})
+	}
+	var buf bytes.Buffer
+	var err error
+	if format == "png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatalf("failed to encode %s: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+// testPDF builds a PDF with the given number of empty pages and a title.
+func testPDF(pages int, title string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+	}
+	kids := ""
+	for i := 0; i < pages; i++ {
+		kids += fmt.Sprintf("%d 0 R ", 4+i)
+	}
+	objects = append(objects,
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids, pages),
+		fmt.Sprintf("<< /Title (%s) /Author (Field Office) /CreationDate (D:20260310093000+07'00') >>", title),
+	)
+	for i := 0; i < pages; i++ {
+		objects = append(objects, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] >>")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// hugePNG is a PNG header claiming far more pixels than it holds.
+func hugePNG() []byte {
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], 100000)
+	binary.BigEndian.PutUint32(ihdr[4:], 100000)
+	ihdr[8], ihdr[9] = 8, 2 // 8-bit RGB
+
+	data := []byte("\x89PNG\r\n\x1a\n")
+	data = binary.BigEndian.AppendUint32(data, uint32(len(ihdr)))
+	chunk := append([]byte("IHDR"), ihdr...)
+	data = append(data, chunk...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(chunk))
+}
+
+func TestRenderThumbnail(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+		wantWidth   int
+		wantHeight  int
+	}{
+		{"landscape jpeg", "image/jpeg", encodedImage(t, "jpeg", 1600, 800), 320, 160},
+		{"portrait png", "image/png", encodedImage(t, "png", 600, 1200), 160, 320},
+		{"small png is not enlarged", "image/png", encodedImage(t, "png", 100, 50), 100, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendering, err := Render(tt.contentType, tt.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rendering.ContentType != "image/jpeg" || rendering.Extension != ".jpg" {
+				t.Errorf("expected a jpeg thumbnail, got %s %s", rendering.ContentType, rendering.Extension)
+			}
+			if rendering.Width != tt.wantWidth || rendering.Height != tt.wantHeight {
+				t.Errorf("expected %dx%d, got %dx%d", tt.wantWidth, tt.wantHeight, rendering.Width, rendering.Height)
+			}
+			config, err := jpeg.DecodeConfig(bytes.NewReader(rendering.Data))
+			if err != nil {
+				t.Fatalf("expected a decodable jpeg: %v", err)
+			}
+			if config.Width != tt.wantWidth || config.Height != tt.wantHeight {
+				t.Errorf("expected encoded %dx%d, got %dx%d", tt.wantWidth, tt.wantHeight, config.Width, config.Height)
+			}
+		})
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	rendering, err := Render("application/pdf", testPDF(3, "Loan agreement"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rendering.ContentType != "image/png" || rendering.PageCount != 3 {
+		t.Errorf("expected a png card for 3 pages, got %s for %d", rendering.ContentType, rendering.PageCount)
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(rendering.Data))
+	if err != nil {
+		t.Fatalf("expected a decodable png: %v", err)
+	}
+	if config.Width != MaxDimension || config.Width != rendering.Width || config.Height != rendering.Height {
+		t.Errorf("expected %dx%d, got %dx%d", rendering.Width, rendering.Height, config.Width, config.Height)
+	}
+
+	info, err := readPDF(testPDF(1, "Loan agreement"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := pdfInfo{version: "1.4", pages: 1, title: "Loan agreement", author: "Field Office", createdAt: "2026-03-10"}
+	if info != want {
+		t.Errorf("expected %+v, got %+v", want, info)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+		want        error
+	}{
+		{"unsupported type", "image/heic", []byte("heic"), ErrUnsupported},
+		{"corrupt image", "image/jpeg", []byte{0xff, 0xd8, 0xff, 0x00}, nil},
+		{"too many pixels", "image/png", hugePNG(), nil},
+		{"not a pdf", "application/pdf", []byte("hello"), nil},
+		{"corrupt pdf", "application/pdf", []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nstartxref\n9999\n%%EOF\n"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Render(tt.contentType, tt.data)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	got := wrap("Perjanjian pinjaman modal usaha mikro", 12)
+	want := []string{"Perjanjian", "pinjaman", "modal usaha", "mikro"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
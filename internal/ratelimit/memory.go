@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const memorySweepInterval = 10 * time.Minute
+
+// MemoryStore keeps buckets in process memory. Limits are per instance. At
+// most maxBuckets buckets are kept; when full, the least recently taken bucket
+// is evicted.
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxBuckets int
+	buckets    map[string]*list.Element
+	recent     *list.List // of *memoryBucket, most recently taken first
+	lastSweep  time.Time
+}
+
+type memoryBucket struct {
+	Bucket
+	key    string
+	period time.Duration
+}
+
+func NewMemoryStore(maxBuckets int) *MemoryStore {
+	return &MemoryStore{
+		maxBuckets: maxBuckets,
+		buckets:    make(map[string]*list.Element),
+		recent:     list.New(),
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	elem, ok := s.buckets[key]
+	if !ok {
+		if s.maxBuckets > 0 && s.recent.Len() >= s.maxBuckets {
+			s.remove(s.recent.Back())
+		}
+		elem = s.recent.PushFront(&memoryBucket{key: key})
+		s.buckets[key] = elem
+	}
+	s.recent.MoveToFront(elem)
+
+	b := elem.Value.(*memoryBucket)
+	bucket, result := policy.Take(b.Bucket, now)
+	b.Bucket = bucket
+	b.period = policy.Period
+	return result, nil
+}
+
+// Len returns the number of buckets held.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recent.Len()
+}
+
+// sweep drops buckets that have been idle long enough to be full again.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for elem := s.recent.Front(); elem != nil; {
+		next := elem.Next()
+		if b := elem.Value.(*memoryBucket); now.Sub(b.UpdatedAt) > b.period {
+			s.remove(elem)
+		}
+		elem = next
+	}
+}
+
+func (s *MemoryStore) remove(elem *list.Element) {
+	s.recent.Remove(elem)
+	delete(s.buckets, elem.Value.(*memoryBucket).key)
+}
@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPolicy is the policy name applied to routes without their own entry.
+const DefaultPolicy = "default"
+
+// Policy is a token bucket holding up to Limit tokens that refills Limit
+// tokens every Period.
+type Policy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+}
+
+// Bucket is the persisted state of one client's bucket for one policy.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next token, when denied
+}
+
+// Store takes one token from the bucket identified by key.
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error)
+}
+
+func (p Policy) ratePerSecond() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+// Take refills b for the time elapsed since its last update and consumes a
+// token if one is available. A zero Bucket is treated as full.
+func (p Policy) Take(b Bucket, now time.Time) (Bucket, Result) {
+	tokens := float64(p.Limit)
+	if !b.UpdatedAt.IsZero() {
+		elapsed := now.Sub(b.UpdatedAt).Seconds()
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		tokens = math.Min(float64(p.Limit), b.Tokens+elapsed*p.ratePerSecond())
+	}
+
+	result := Result{Limit: p.Limit}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - tokens) / p.ratePerSecond())
+	}
+
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = secondsToDuration((float64(p.Limit) - tokens) / p.ratePerSecond())
+
+	return Bucket{Tokens: tokens, UpdatedAt: now}, result
+}
+
+// LongestPeriod returns the longest Period among policies. A bucket idle for
+// that long is full again under any of them and can be dropped.
+func LongestPeriod(policies map[string]Policy) time.Duration {
+	var longest time.Duration
+	for _, p := range policies {
+		longest = max(longest, p.Period)
+	}
+	return longest
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
+
+// ParsePolicies parses "name=limit/period" entries separated by ";", e.g.
+// "default=300/1m;POST /api/v1/loans/{id}/investments=30/1m". Names are route
+// templates or DefaultPolicy.
+func ParsePolicies(spec string) (map[string]Policy, error) {
+	policies := make(map[string]Policy)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, rule, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("rate limit policy %q: missing '='", entry)
+		}
+		limitStr, periodStr, found := strings.Cut(rule, "/")
+		if !found {
+			return nil, fmt.Errorf("rate limit policy %q: expected limit/period", entry)
+		}
+
+		limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("rate limit policy %q: invalid limit", entry)
+		}
+		period, err := time.ParseDuration(strings.TrimSpace(periodStr))
+		if err != nil || period <= 0 {
+			return nil, fmt.Errorf("rate limit policy %q: invalid period", entry)
+		}
+
+		name = strings.TrimSpace(name)
+		policies[name] = Policy{Name: name, Limit: limit, Period: period}
+	}
+	return policies, nil
+}
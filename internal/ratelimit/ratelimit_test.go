@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPolicyTake(t *testing.T) {
+	policy := Policy{Name: "test", Limit: 2, Period: 2 * time.Second}
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	var bucket Bucket
+	var result Result
+
+	bucket, result = policy.Take(bucket, now)
+	if !result.Allowed || result.Remaining != 1 {
+		t.Errorf("expected first take to be allowed with 1 remaining, got %+v", result)
+	}
+
+	bucket, result = policy.Take(bucket, now)
+	if !result.Allowed || result.Remaining != 0 {
+		t.Errorf("expected second take to be allowed with 0 remaining, got %+v", result)
+	}
+
+	bucket, result = policy.Take(bucket, now)
+	if result.Allowed {
+		t.Error("expected third take to be denied")
+	}
+	if result.RetryAfter != time.Second {
+		t.Errorf("expected retry after 1s, got %s", result.RetryAfter)
+	}
+
+	// One token refills per second
+	_, result = policy.Take(bucket, now.Add(time.Second))
+	if !result.Allowed {
+		t.Error("expected take after refill to be allowed")
+	}
+}
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ParsePolicies("default=300/1m; POST /api/v1/loans/{id}/investments=30/1m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	policy, ok := policies["POST /api/v1/loans/{id}/investments"]
+	if !ok {
+		t.Fatal("expected investments policy to be parsed")
+	}
+	if policy.Limit != 30 || policy.Period != time.Minute {
+		t.Errorf("expected 30/1m, got %d/%s", policy.Limit, policy.Period)
+	}
+
+	for _, spec := range []string{"default", "default=abc/1m", "default=10/xyz", "default=10"} {
+		if _, err := ParsePolicies(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	store := NewMemoryStore(2)
+	policy := Policy{Name: "test", Limit: 1, Period: time.Minute}
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	store.Take(ctx, "a", policy, now)
+	store.Take(ctx, "b", policy, now)
+	// Touch "a" so "b" is the least recently taken
+	if result, _ := store.Take(ctx, "a", policy, now); result.Allowed {
+		t.Error("expected a's second take to be denied")
+	}
+	store.Take(ctx, "c", policy, now)
+
+	if store.Len() != 2 {
+		t.Errorf("expected 2 buckets, got %d", store.Len())
+	}
+	if result, _ := store.Take(ctx, "a", policy, now); result.Allowed {
+		t.Error("expected a's bucket to be kept")
+	}
+	if result, _ := store.Take(ctx, "b", policy, now); !result.Allowed {
+		t.Error("expected b's bucket to have been evicted")
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	store := NewMemoryStore(0)
+	policy := Policy{Name: "test", Limit: 1, Period: time.Minute}
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	store.Take(ctx, "a", policy, now)
+	store.Take(ctx, "b", policy, now.Add(memorySweepInterval))
+	if store.Len() != 1 {
+		t.Errorf("expected idle bucket to be swept, got %d buckets", store.Len())
+	}
+}
+
+func TestLongestPeriod(t *testing.T) {
+	policies := map[string]Policy{
+		"a": {Name: "a", Limit: 1, Period: time.Minute},
+		"b": {Name: "b", Limit: 1, Period: time.Hour},
+	}
+	if got := LongestPeriod(policies); got != time.Hour {
+		t.Errorf("expected 1h, got %s", got)
+	}
+}
@@ -13,30 +13,106 @@ type LoanRepository interface {
 	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Loan, error)
 	Update(ctx context.Context, loan *domain.Loan) error
 	List(ctx context.Context, filter LoanFilter) ([]*domain.Loan, int64, error)
+	Stream(ctx context.Context, filter LoanFilter, fn func(*domain.Loan) error) error
+	Search(ctx context.Context, query SearchQuery) ([]*SearchHit, int64, error)
 }
 
 type LoanFilter struct {
-	State    *domain.LoanState
-	Limit    int
-	Offset   int
+	State  *domain.LoanState
+	Limit  int
+	Offset int
+}
+
+// SearchQuery is a free-text query matched against loan borrower data and
+// the ids of the staff who approved or disbursed the loan.
+type SearchQuery struct {
+	Text   string
+	Limit  int
+	Offset int
+}
+
+// SearchHit is a matching loan with its relevance and the staff ids it was
+// matched on, if any.
+type SearchHit struct {
+	Loan             *domain.Loan
+	FieldValidatorID *string
+	FieldOfficerID   *string
+	Rank             float64
+}
+
+type LoanProductRepository interface {
+	Create(ctx context.Context, product *domain.LoanProduct) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.LoanProduct, error)
+	List(ctx context.Context, filter LoanProductFilter) ([]*domain.LoanProduct, error)
+	Update(ctx context.Context, product *domain.LoanProduct) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type LoanProductFilter struct {
+	ActiveOnly bool
 }
 
 type ApprovalRepository interface {
 	Create(ctx context.Context, approval *domain.Approval) error
 	GetByLoanID(ctx context.Context, loanID uuid.UUID) (*domain.Approval, error)
+	UpdatePictureProof(ctx context.Context, loanID uuid.UUID, file domain.StoredFile, photo domain.PhotoMetadata) error
 }
 
 type InvestmentRepository interface {
 	Create(ctx context.Context, investment *domain.Investment) error
 	ListByLoanID(ctx context.Context, loanID uuid.UUID) ([]*domain.Investment, error)
 	GetInvestorsByLoanID(ctx context.Context, loanID uuid.UUID) ([]string, error)
+	Stream(ctx context.Context, filter InvestmentFilter, fn func(*domain.Investment) error) error
+}
+
+type InvestmentFilter struct {
+	LoanID     *uuid.UUID
+	InvestorID *string
 }
 
 type DisbursementRepository interface {
 	Create(ctx context.Context, disbursement *domain.Disbursement) error
 	GetByLoanID(ctx context.Context, loanID uuid.UUID) (*domain.Disbursement, error)
+	UpdateSignedAgreement(ctx context.Context, loanID uuid.UUID, file domain.StoredFile) error
+}
+
+type DocumentRepository interface {
+	Create(ctx context.Context, document *domain.Document) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Document, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Document, error)
+	// GetBySHA256 returns any document with the given checksum, so that an
+	// identical upload can reuse its storage key.
+	GetBySHA256(ctx context.Context, sha256 string) (*domain.Document, error)
+	// ListByLoanID returns every version of the loan's documents, by purpose
+	// and then version.
+	ListByLoanID(ctx context.Context, loanID uuid.UUID) ([]*domain.Document, error)
+	Update(ctx context.Context, document *domain.Document) error
+}
+
+type PreviewRepository interface {
+	Create(ctx context.Context, preview *domain.Preview) error
+	GetBySourceKey(ctx context.Context, sourceKey string) (*domain.Preview, error)
+}
+
+type DataKeyRepository interface {
+	Create(ctx context.Context, key *domain.DataKey) error
+	GetByStorageKey(ctx context.Context, storageKey string) (*domain.DataKey, error)
+	Delete(ctx context.Context, storageKey string) error
+	// ListNotWrappedWith returns up to limit keys wrapped by a master key
+	// other than masterKeyID, ordered by storage key and starting after
+	// the given one.
+	ListNotWrappedWith(ctx context.Context, masterKeyID, after string, limit int) ([]*domain.DataKey, error)
+	// Rewrap stores key's new wrapping unless the key has been rewrapped
+	// since it was read wrapped by previousMasterKeyID.
+	Rewrap(ctx context.Context, key *domain.DataKey, previousMasterKeyID string) error
 }
 
 type TransactionManager interface {
 	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
+
+// StoredFileRepository reports which storage keys are referenced by loans,
+// approvals, disbursements, documents or the previews of documents.
+type StoredFileRepository interface {
+	ReferencedKeys(ctx context.Context, keys []string) (map[string]bool, error)
+}
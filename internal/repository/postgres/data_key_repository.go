@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type DataKeyRepository struct {
+	db *DB
+}
+
+func NewDataKeyRepository(db *DB) *DataKeyRepository {
+	return &DataKeyRepository{db: db}
+}
+
+func (r *DataKeyRepository) Create(ctx context.Context, key *domain.DataKey) error {
+	conn := r.db.GetConn(ctx)
+	query := `
+		INSERT INTO data_keys (storage_key, master_key_id, wrapped_key, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := conn.Exec(ctx, query, key.StorageKey, key.MasterKeyID, key.Wrapped, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create data key: %w", err)
+	}
+	return nil
+}
+
+func (r *DataKeyRepository) GetByStorageKey(ctx context.Context, storageKey string) (*domain.DataKey, error) {
+	conn := r.db.GetConn(ctx)
+	query := `
+		SELECT storage_key, master_key_id, wrapped_key, created_at, rotated_at
+		FROM data_keys
+		WHERE storage_key = $1
+	`
+	var key domain.DataKey
+	err := conn.QueryRow(ctx, query, storageKey).Scan(
+		&key.StorageKey,
+		&key.MasterKeyID,
+		&key.Wrapped,
+		&key.CreatedAt,
+		&key.RotatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrDataKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get data key: %w", err)
+	}
+	return &key, nil
+}
+
+func (r *DataKeyRepository) Delete(ctx context.Context, storageKey string) error {
+	conn := r.db.GetConn(ctx)
+	_, err := conn.Exec(ctx, `DELETE FROM data_keys WHERE storage_key = $1`, storageKey)
+	if err != nil {
+		return fmt.Errorf("failed to delete data key: %w", err)
+	}
+	return nil
+}
+
+func (r *DataKeyRepository) ListNotWrappedWith(ctx context.Context, masterKeyID, after string, limit int) ([]*domain.DataKey, error) {
+	conn := r.db.GetConn(ctx)
+	query := `
+		SELECT storage_key, master_key_id, wrapped_key, created_at, rotated_at
+		FROM data_keys
+		WHERE master_key_id <> $1 AND storage_key > $2
+		ORDER BY storage_key
+		LIMIT $3
+	`
+	rows, err := conn.Query(ctx, query, masterKeyID, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*domain.DataKey
+	for rows.Next() {
+		var key domain.DataKey
+		if err := rows.Scan(&key.StorageKey, &key.MasterKeyID, &key.Wrapped, &key.CreatedAt, &key.RotatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan data key: %w", err)
+		}
+		keys = append(keys, &key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list data keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *DataKeyRepository) Rewrap(ctx context.Context, key *domain.DataKey, previousMasterKeyID string) error {
+	conn := r.db.GetConn(ctx)
+	query := `
+		UPDATE data_keys
+		SET master_key_id = $2, wrapped_key = $3, rotated_at = $4
+		WHERE storage_key = $1 AND master_key_id = $5
+	`
+	tag, err := conn.Exec(ctx, query, key.StorageKey, key.MasterKeyID, key.Wrapped, key.RotatedAt, previousMasterKeyID)
+	if err != nil {
+		return fmt.Errorf("failed to rewrap data key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataKeyNotFound
+	}
+	return nil
+}
@@ -3,18 +3,27 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"log/slog"
 
+	"github.com/agunghallmanmaliki/amartha/internal/telemetry"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type DB struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	logger *slog.Logger
 }
 
-func NewDB(ctx context.Context, databaseURL string) (*DB, error) {
-	pool, err := pgxpool.New(ctx, databaseURL)
+func NewDB(ctx context.Context, databaseURL string, logger *slog.Logger) (*DB, error) {
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database URL: %w", err)
+	}
+	poolConfig.ConnConfig.Tracer = &queryTracer{logger: logger}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
@@ -23,20 +32,27 @@ func NewDB(ctx context.Context, databaseURL string) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &DB{pool: pool}, nil
+	return &DB{pool: pool, logger: logger}, nil
 }
 
 func (db *DB) Close() {
 	db.pool.Close()
 }
 
+func (db *DB) Ping(ctx context.Context) error {
+	return db.pool.Ping(ctx)
+}
+
 func (db *DB) Pool() *pgxpool.Pool {
 	return db.pool
 }
 
 type txKey struct{}
 
-func (db *DB) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+func (db *DB) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "TransactionManager.WithTransaction")
+	defer func() { telemetry.End(span, err) }()
+
 	tx, err := db.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -45,6 +61,7 @@ func (db *DB) WithTransaction(ctx context.Context, fn func(ctx context.Context)
 	txCtx := context.WithValue(ctx, txKey{}, tx)
 
 	if err := fn(txCtx); err != nil {
+		db.logger.DebugContext(ctx, "rolling back transaction", "error", err)
 		if rbErr := tx.Rollback(ctx); rbErr != nil {
 			return fmt.Errorf("tx err: %v, rollback err: %v", err, rbErr)
 		}
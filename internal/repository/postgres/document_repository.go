@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type DocumentRepository struct {
+	db *DB
+}
+
+func NewDocumentRepository(db *DB) *DocumentRepository {
+	return &DocumentRepository{db: db}
+}
+
+const documentColumns = `id, loan_id, purpose, version, storage_key, content_type, size, COALESCE(sha256, ''),
+		       uploaded_by, superseded_by, created_at`
+
+func (r *DocumentRepository) Create(ctx context.Context, document *domain.Document) error {
+	conn := r.db.GetConn(ctx)
+	query := `
+		INSERT INTO documents (id, loan_id, purpose, version, storage_key, content_type, size, sha256, uploaded_by, superseded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11)
+	`
+	_, err := conn.Exec(ctx, query,
+		document.ID,
+		document.LoanID,
+		document.Purpose,
+		document.Version,
+		document.File.Key,
+		document.File.ContentType,
+		document.File.Size,
+		document.File.SHA256,
+		document.UploadedBy,
+		document.SupersededBy,
+		document.CreatedAt,
+	)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return domain.ErrDocumentSuperseded
+		}
+		return fmt.Errorf("failed to create document: %w", err)
+	}
+	return nil
+}
+
+func (r *DocumentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Document, error) {
+	conn := r.db.GetConn(ctx)
+	query := `
+		SELECT ` + documentColumns + `
+		FROM documents
+		WHERE id = $1
+	`
+	return r.scanDocument(conn.QueryRow(ctx, query, id))
+}
+
+func (r *DocumentRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Document, error) {
+	conn := r.db.GetConn(ctx)
+	query := `
+		SELECT ` + documentColumns + `
+		FROM documents
+		WHERE id = $1
+		FOR UPDATE
+	`
+	return r.scanDocument(conn.QueryRow(ctx, query, id))
+}
+
+func (r *DocumentRepository) GetBySHA256(ctx context.Context, sha256 string) (*domain.Document, error) {
+	conn := r.db.GetConn(ctx)
+	query := `
+		SELECT ` + documentColumns + `
+		FROM documents
+		WHERE sha256 = $1
+		ORDER BY created_at
+		LIMIT 1
+	`
+	return r.scanDocument(conn.QueryRow(ctx, query, sha256))
+}
+
+func (r *DocumentRepository) ListByLoanID(ctx context.Context, loanID uuid.UUID) ([]*domain.Document, error) {
+	conn := r.db.GetConn(ctx)
+	query := `
+		SELECT ` + documentColumns + `
+		FROM documents
+		WHERE loan_id = $1
+		ORDER BY purpose, version
+	`
+	rows, err := conn.Query(ctx, query, loanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	documents := []*domain.Document{}
+	for rows.Next() {
+		document, err := r.scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+
+	return documents, rows.Err()
+}
+
+// Update records that the document was superseded; everything else about a
+// document is immutable.
+func (r *DocumentRepository) Update(ctx context.Context, document *domain.Document) error {
+	conn := r.db.GetConn(ctx)
+	tag, err := conn.Exec(ctx, "UPDATE documents SET superseded_by = $2 WHERE id = $1", document.ID, document.SupersededBy)
+	if err != nil {
+		return fmt.Errorf("failed to update document: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDocumentNotFound
+	}
+	return nil
+}
+
+func (r *DocumentRepository) scanDocument(row pgx.Row) (*domain.Document, error) {
+	var document domain.Document
+	err := row.Scan(
+		&document.ID,
+		&document.LoanID,
+		&document.Purpose,
+		&document.Version,
+		&document.File.Key,
+		&document.File.ContentType,
+		&document.File.Size,
+		&document.File.SHA256,
+		&document.UploadedBy,
+		&document.SupersededBy,
+		&document.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrDocumentNotFound
+		}
+		return nil, fmt.Errorf("failed to scan document: %w", err)
+	}
+	return &document, nil
+}
@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes mapped to domain errors.
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+type LoanProductRepository struct {
+	db *DB
+}
+
+func NewLoanProductRepository(db *DB) *LoanProductRepository {
+	return &LoanProductRepository{db: db}
+}
+
+const loanProductColumns = `id, code, name, min_principal, max_principal, tenor_months, min_rate, max_rate,
+		       min_roi, max_roi, required_documents, active, created_at, updated_at`
+
+func (r *LoanProductRepository) Create(ctx context.Context, product *domain.LoanProduct) error {
+	conn := r.db.GetConn(ctx)
+	query := `
+		INSERT INTO loan_products (` + loanProductColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`
+	_, err := conn.Exec(ctx, query,
+		product.ID,
+		product.Code,
+		product.Name,
+		product.MinPrincipal,
+		product.MaxPrincipal,
+		product.TenorMonths,
+		product.MinRate,
+		product.MaxRate,
+		product.MinROI,
+		product.MaxROI,
+		product.RequiredDocuments,
+		product.Active,
+		product.CreatedAt,
+		product.UpdatedAt,
+	)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return domain.ErrLoanProductCodeTaken
+		}
+		return fmt.Errorf("failed to create loan product: %w", err)
+	}
+	return nil
+}
+
+func (r *LoanProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.LoanProduct, error) {
+	conn := r.db.GetConn(ctx)
+	query := `
+		SELECT ` + loanProductColumns + `
+		FROM loan_products
+		WHERE id = $1
+	`
+	return r.scanLoanProduct(conn.QueryRow(ctx, query, id))
+}
+
+func (r *LoanProductRepository) List(ctx context.Context, filter repository.LoanProductFilter) ([]*domain.LoanProduct, error) {
+	conn := r.db.GetConn(ctx)
+	query := `
+		SELECT ` + loanProductColumns + `
+		FROM loan_products
+	`
+	if filter.ActiveOnly {
+		query += " WHERE active"
+	}
+	query += " ORDER BY code"
+
+	rows, err := conn.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list loan products: %w", err)
+	}
+	defer rows.Close()
+
+	products := []*domain.LoanProduct{}
+	for rows.Next() {
+		product, err := r.scanLoanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+func (r *LoanProductRepository) Update(ctx context.Context, product *domain.LoanProduct) error {
+	conn := r.db.GetConn(ctx)
+	query := `
+		UPDATE loan_products
+		SET code = $2, name = $3, min_principal = $4, max_principal = $5, tenor_months = $6,
+		    min_rate = $7, max_rate = $8, min_roi = $9, max_roi = $10, required_documents = $11,
+		    active = $12, updated_at = $13
+		WHERE id = $1
+	`
+	tag, err := conn.Exec(ctx, query,
+		product.ID,
+		product.Code,
+		product.Name,
+		product.MinPrincipal,
+		product.MaxPrincipal,
+		product.TenorMonths,
+		product.MinRate,
+		product.MaxRate,
+		product.MinROI,
+		product.MaxROI,
+		product.RequiredDocuments,
+		product.Active,
+		product.UpdatedAt,
+	)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return domain.ErrLoanProductCodeTaken
+		}
+		return fmt.Errorf("failed to update loan product: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrLoanProductNotFound
+	}
+	return nil
+}
+
+func (r *LoanProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	conn := r.db.GetConn(ctx)
+	tag, err := conn.Exec(ctx, "DELETE FROM loan_products WHERE id = $1", id)
+	if err != nil {
+		if isPgError(err, foreignKeyViolation) {
+			return domain.ErrLoanProductInUse
+		}
+		return fmt.Errorf("failed to delete loan product: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrLoanProductNotFound
+	}
+	return nil
+}
+
+func (r *LoanProductRepository) scanLoanProduct(row pgx.Row) (*domain.LoanProduct, error) {
+	var product domain.LoanProduct
+	err := row.Scan(
+		&product.ID,
+		&product.Code,
+		&product.Name,
+		&product.MinPrincipal,
+		&product.MaxPrincipal,
+		&product.TenorMonths,
+		&product.MinRate,
+		&product.MaxRate,
+		&product.MinROI,
+		&product.MaxROI,
+		&product.RequiredDocuments,
+		&product.Active,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrLoanProductNotFound
+		}
+		return nil, fmt.Errorf("failed to scan loan product: %w", err)
+	}
+	return &product, nil
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
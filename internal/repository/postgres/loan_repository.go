@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/agunghallmanmaliki/amartha/internal/domain"
 	"github.com/agunghallmanmaliki/amartha/internal/repository"
@@ -23,20 +24,33 @@ func NewLoanRepository(db *DB) *LoanRepository {
 func (r *LoanRepository) Create(ctx context.Context, loan *domain.Loan) error {
 	conn := r.db.GetConn(ctx)
 	query := `
-		INSERT INTO loans (id, borrower_id, principal_amount, rate, roi, state, agreement_letter_url, total_invested, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO loans (id, borrower_id, borrower_name, notes, principal_amount, rate, roi, product_id, tenor_months, state, agreement_letter_key, total_invested, created_at, updated_at,
+		                   borrower_latitude, borrower_longitude, agreement_sha256, agreement_signature, agreement_signer_fingerprint, agreement_signed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 	`
+	latitude, longitude := coordinates(loan.BorrowerLocation)
+	signature := newSignatureColumns(loan.AgreementSignature)
 	_, err := conn.Exec(ctx, query,
 		loan.ID,
 		loan.BorrowerID,
+		loan.BorrowerName,
+		loan.Notes,
 		loan.PrincipalAmount,
 		loan.Rate,
 		loan.ROI,
+		loan.ProductID,
+		loan.TenorMonths,
 		loan.State,
-		loan.AgreementLetterURL,
+		loan.AgreementLetterKey,
 		loan.TotalInvested,
 		loan.CreatedAt,
 		loan.UpdatedAt,
+		latitude,
+		longitude,
+		signature.sha256,
+		signature.signature,
+		signature.fingerprint,
+		signature.signedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create loan: %w", err)
@@ -47,7 +61,8 @@ func (r *LoanRepository) Create(ctx context.Context, loan *domain.Loan) error {
 func (r *LoanRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Loan, error) {
 	conn := r.db.GetConn(ctx)
 	query := `
-		SELECT id, borrower_id, principal_amount, rate, roi, state, agreement_letter_url, total_invested, created_at, updated_at
+		SELECT id, borrower_id, borrower_name, notes, principal_amount, rate, roi, product_id, tenor_months, state, agreement_letter_key, total_invested, created_at, updated_at,
+		       borrower_latitude, borrower_longitude, agreement_sha256, agreement_signature, agreement_signer_fingerprint, agreement_signed_at
 		FROM loans
 		WHERE id = $1
 	`
@@ -57,7 +72,8 @@ func (r *LoanRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Loa
 func (r *LoanRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Loan, error) {
 	conn := r.db.GetConn(ctx)
 	query := `
-		SELECT id, borrower_id, principal_amount, rate, roi, state, agreement_letter_url, total_invested, created_at, updated_at
+		SELECT id, borrower_id, borrower_name, notes, principal_amount, rate, roi, product_id, tenor_months, state, agreement_letter_key, total_invested, created_at, updated_at,
+		       borrower_latitude, borrower_longitude, agreement_sha256, agreement_signature, agreement_signer_fingerprint, agreement_signed_at
 		FROM loans
 		WHERE id = $1
 		FOR UPDATE
@@ -67,17 +83,29 @@ func (r *LoanRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*d
 
 func (r *LoanRepository) scanLoan(row pgx.Row) (*domain.Loan, error) {
 	var loan domain.Loan
+	var latitude, longitude *float64
+	var signature signatureColumns
 	err := row.Scan(
 		&loan.ID,
 		&loan.BorrowerID,
+		&loan.BorrowerName,
+		&loan.Notes,
 		&loan.PrincipalAmount,
 		&loan.Rate,
 		&loan.ROI,
+		&loan.ProductID,
+		&loan.TenorMonths,
 		&loan.State,
-		&loan.AgreementLetterURL,
+		&loan.AgreementLetterKey,
 		&loan.TotalInvested,
 		&loan.CreatedAt,
 		&loan.UpdatedAt,
+		&latitude,
+		&longitude,
+		&signature.sha256,
+		&signature.signature,
+		&signature.fingerprint,
+		&signature.signedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -85,6 +113,8 @@ func (r *LoanRepository) scanLoan(row pgx.Row) (*domain.Loan, error) {
 		}
 		return nil, fmt.Errorf("failed to scan loan: %w", err)
 	}
+	loan.BorrowerLocation = geoPoint(latitude, longitude)
+	loan.AgreementSignature = signature.agreementSignature()
 	return &loan, nil
 }
 
@@ -92,20 +122,34 @@ func (r *LoanRepository) Update(ctx context.Context, loan *domain.Loan) error {
 	conn := r.db.GetConn(ctx)
 	query := `
 		UPDATE loans
-		SET borrower_id = $2, principal_amount = $3, rate = $4, roi = $5, state = $6,
-		    agreement_letter_url = $7, total_invested = $8, updated_at = $9
+		SET borrower_id = $2, borrower_name = $3, notes = $4, principal_amount = $5, rate = $6, roi = $7,
+		    product_id = $8, tenor_months = $9, state = $10, agreement_letter_key = $11, total_invested = $12,
+		    updated_at = $13, borrower_latitude = $14, borrower_longitude = $15,
+		    agreement_sha256 = $16, agreement_signature = $17, agreement_signer_fingerprint = $18, agreement_signed_at = $19
 		WHERE id = $1
 	`
+	latitude, longitude := coordinates(loan.BorrowerLocation)
+	signature := newSignatureColumns(loan.AgreementSignature)
 	_, err := conn.Exec(ctx, query,
 		loan.ID,
 		loan.BorrowerID,
+		loan.BorrowerName,
+		loan.Notes,
 		loan.PrincipalAmount,
 		loan.Rate,
 		loan.ROI,
+		loan.ProductID,
+		loan.TenorMonths,
 		loan.State,
-		loan.AgreementLetterURL,
+		loan.AgreementLetterKey,
 		loan.TotalInvested,
 		loan.UpdatedAt,
+		latitude,
+		longitude,
+		signature.sha256,
+		signature.signature,
+		signature.fingerprint,
+		signature.signedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update loan: %w", err)
@@ -116,20 +160,8 @@ func (r *LoanRepository) Update(ctx context.Context, loan *domain.Loan) error {
 func (r *LoanRepository) List(ctx context.Context, filter repository.LoanFilter) ([]*domain.Loan, int64, error) {
 	conn := r.db.GetConn(ctx)
 
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
-
-	if filter.State != nil {
-		conditions = append(conditions, fmt.Sprintf("state = $%d", argIndex))
-		args = append(args, *filter.State)
-		argIndex++
-	}
-
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	whereClause, args := loanWhereClause(filter)
+	argIndex := len(args) + 1
 
 	// Count query
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM loans %s", whereClause)
@@ -141,7 +173,8 @@ func (r *LoanRepository) List(ctx context.Context, filter repository.LoanFilter)
 
 	// List query
 	listQuery := fmt.Sprintf(`
-		SELECT id, borrower_id, principal_amount, rate, roi, state, agreement_letter_url, total_invested, created_at, updated_at
+		SELECT id, borrower_id, borrower_name, notes, principal_amount, rate, roi, product_id, tenor_months, state, agreement_letter_key, total_invested, created_at, updated_at,
+		       borrower_latitude, borrower_longitude, agreement_sha256, agreement_signature, agreement_signer_fingerprint, agreement_signed_at
 		FROM loans
 		%s
 		ORDER BY created_at DESC
@@ -159,27 +192,95 @@ func (r *LoanRepository) List(ctx context.Context, filter repository.LoanFilter)
 	var loans []*domain.Loan
 	for rows.Next() {
 		var loan domain.Loan
+		var latitude, longitude *float64
+		var signature signatureColumns
 		err := rows.Scan(
 			&loan.ID,
 			&loan.BorrowerID,
+			&loan.BorrowerName,
+			&loan.Notes,
 			&loan.PrincipalAmount,
 			&loan.Rate,
 			&loan.ROI,
+			&loan.ProductID,
+			&loan.TenorMonths,
 			&loan.State,
-			&loan.AgreementLetterURL,
+			&loan.AgreementLetterKey,
 			&loan.TotalInvested,
 			&loan.CreatedAt,
 			&loan.UpdatedAt,
+			&latitude,
+			&longitude,
+			&signature.sha256,
+			&signature.signature,
+			&signature.fingerprint,
+			&signature.signedAt,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan loan: %w", err)
 		}
+		loan.BorrowerLocation = geoPoint(latitude, longitude)
+		loan.AgreementSignature = signature.agreementSignature()
 		loans = append(loans, &loan)
 	}
 
 	return loans, total, nil
 }
 
+// Stream calls fn for every loan matching filter, oldest first, without
+// buffering the result set. Limit and Offset apply only when Limit > 0.
+func (r *LoanRepository) Stream(ctx context.Context, filter repository.LoanFilter, fn func(*domain.Loan) error) error {
+	conn := r.db.GetConn(ctx)
+
+	whereClause, args := loanWhereClause(filter)
+
+	query := fmt.Sprintf(`
+		SELECT id, borrower_id, borrower_name, notes, principal_amount, rate, roi, product_id, tenor_months, state, agreement_letter_key, total_invested, created_at, updated_at,
+		       borrower_latitude, borrower_longitude, agreement_sha256, agreement_signature, agreement_signer_fingerprint, agreement_signed_at
+		FROM loans
+		%s
+		ORDER BY created_at ASC, id ASC
+	`, whereClause)
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to stream loans: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		loan, err := r.scanLoan(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(loan); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func loanWhereClause(filter repository.LoanFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.State != nil {
+		args = append(args, *filter.State)
+		conditions = append(conditions, fmt.Sprintf("state = $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
 // ApprovalRepository
 
 type ApprovalRepository struct {
@@ -193,15 +294,24 @@ func NewApprovalRepository(db *DB) *ApprovalRepository {
 func (r *ApprovalRepository) Create(ctx context.Context, approval *domain.Approval) error {
 	conn := r.db.GetConn(ctx)
 	query := `
-		INSERT INTO approvals (id, loan_id, field_validator_id, picture_proof_url, approved_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO approvals (id, loan_id, field_validator_id, picture_proof_key, picture_proof_content_type, picture_proof_size, approved_at,
+		                       photo_captured_at, photo_latitude, photo_longitude, photo_device_make, photo_device_model)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
+	latitude, longitude := coordinates(approval.Photo.Location)
 	_, err := conn.Exec(ctx, query,
 		approval.ID,
 		approval.LoanID,
 		approval.FieldValidatorID,
-		approval.PictureProofURL,
+		approval.PictureProof.Key,
+		approval.PictureProof.ContentType,
+		approval.PictureProof.Size,
 		approval.ApprovedAt,
+		approval.Photo.CapturedAt,
+		latitude,
+		longitude,
+		approval.Photo.DeviceMake,
+		approval.Photo.DeviceModel,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create approval: %w", err)
@@ -212,17 +322,26 @@ func (r *ApprovalRepository) Create(ctx context.Context, approval *domain.Approv
 func (r *ApprovalRepository) GetByLoanID(ctx context.Context, loanID uuid.UUID) (*domain.Approval, error) {
 	conn := r.db.GetConn(ctx)
 	query := `
-		SELECT id, loan_id, field_validator_id, picture_proof_url, approved_at
+		SELECT id, loan_id, field_validator_id, picture_proof_key, picture_proof_content_type, picture_proof_size, approved_at,
+		       photo_captured_at, photo_latitude, photo_longitude, photo_device_make, photo_device_model
 		FROM approvals
 		WHERE loan_id = $1
 	`
 	var approval domain.Approval
+	var latitude, longitude *float64
 	err := conn.QueryRow(ctx, query, loanID).Scan(
 		&approval.ID,
 		&approval.LoanID,
 		&approval.FieldValidatorID,
-		&approval.PictureProofURL,
+		&approval.PictureProof.Key,
+		&approval.PictureProof.ContentType,
+		&approval.PictureProof.Size,
 		&approval.ApprovedAt,
+		&approval.Photo.CapturedAt,
+		&latitude,
+		&longitude,
+		&approval.Photo.DeviceMake,
+		&approval.Photo.DeviceModel,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -230,9 +349,32 @@ func (r *ApprovalRepository) GetByLoanID(ctx context.Context, loanID uuid.UUID)
 		}
 		return nil, fmt.Errorf("failed to get approval: %w", err)
 	}
+	approval.Photo.Location = geoPoint(latitude, longitude)
 	return &approval, nil
 }
 
+// UpdatePictureProof points the loan's approval at a new version of its
+// picture proof and what that photo records.
+func (r *ApprovalRepository) UpdatePictureProof(ctx context.Context, loanID uuid.UUID, file domain.StoredFile, photo domain.PhotoMetadata) error {
+	conn := r.db.GetConn(ctx)
+	query := `
+		UPDATE approvals
+		SET picture_proof_key = $2, picture_proof_content_type = $3, picture_proof_size = $4,
+		    photo_captured_at = $5, photo_latitude = $6, photo_longitude = $7, photo_device_make = $8, photo_device_model = $9
+		WHERE loan_id = $1
+	`
+	latitude, longitude := coordinates(photo.Location)
+	tag, err := conn.Exec(ctx, query, loanID, file.Key, file.ContentType, file.Size,
+		photo.CapturedAt, latitude, longitude, photo.DeviceMake, photo.DeviceModel)
+	if err != nil {
+		return fmt.Errorf("failed to update approval: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrApprovalNotFound
+	}
+	return nil
+}
+
 // InvestmentRepository
 
 type InvestmentRepository struct {
@@ -295,6 +437,60 @@ func (r *InvestmentRepository) ListByLoanID(ctx context.Context, loanID uuid.UUI
 	return investments, nil
 }
 
+// Stream calls fn for every investment matching filter, oldest first.
+func (r *InvestmentRepository) Stream(ctx context.Context, filter repository.InvestmentFilter, fn func(*domain.Investment) error) error {
+	conn := r.db.GetConn(ctx)
+
+	var conditions []string
+	var args []interface{}
+
+	if filter.LoanID != nil {
+		args = append(args, *filter.LoanID)
+		conditions = append(conditions, fmt.Sprintf("loan_id = $%d", len(args)))
+	}
+	if filter.InvestorID != nil {
+		args = append(args, *filter.InvestorID)
+		conditions = append(conditions, fmt.Sprintf("investor_id = $%d", len(args)))
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, loan_id, investor_id, amount, created_at
+		FROM investments
+		%s
+		ORDER BY created_at ASC, id ASC
+	`, whereClause)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to stream investments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var inv domain.Investment
+		err := rows.Scan(
+			&inv.ID,
+			&inv.LoanID,
+			&inv.InvestorID,
+			&inv.Amount,
+			&inv.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan investment: %w", err)
+		}
+		if err := fn(&inv); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (r *InvestmentRepository) GetInvestorsByLoanID(ctx context.Context, loanID uuid.UUID) ([]string, error) {
 	conn := r.db.GetConn(ctx)
 	query := `
@@ -333,14 +529,16 @@ func NewDisbursementRepository(db *DB) *DisbursementRepository {
 func (r *DisbursementRepository) Create(ctx context.Context, disbursement *domain.Disbursement) error {
 	conn := r.db.GetConn(ctx)
 	query := `
-		INSERT INTO disbursements (id, loan_id, field_officer_id, signed_agreement_url, disbursed_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO disbursements (id, loan_id, field_officer_id, signed_agreement_key, signed_agreement_content_type, signed_agreement_size, disbursed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 	_, err := conn.Exec(ctx, query,
 		disbursement.ID,
 		disbursement.LoanID,
 		disbursement.FieldOfficerID,
-		disbursement.SignedAgreementURL,
+		disbursement.SignedAgreement.Key,
+		disbursement.SignedAgreement.ContentType,
+		disbursement.SignedAgreement.Size,
 		disbursement.DisbursedAt,
 	)
 	if err != nil {
@@ -352,7 +550,7 @@ func (r *DisbursementRepository) Create(ctx context.Context, disbursement *domai
 func (r *DisbursementRepository) GetByLoanID(ctx context.Context, loanID uuid.UUID) (*domain.Disbursement, error) {
 	conn := r.db.GetConn(ctx)
 	query := `
-		SELECT id, loan_id, field_officer_id, signed_agreement_url, disbursed_at
+		SELECT id, loan_id, field_officer_id, signed_agreement_key, signed_agreement_content_type, signed_agreement_size, disbursed_at
 		FROM disbursements
 		WHERE loan_id = $1
 	`
@@ -361,7 +559,9 @@ func (r *DisbursementRepository) GetByLoanID(ctx context.Context, loanID uuid.UU
 		&disbursement.ID,
 		&disbursement.LoanID,
 		&disbursement.FieldOfficerID,
-		&disbursement.SignedAgreementURL,
+		&disbursement.SignedAgreement.Key,
+		&disbursement.SignedAgreement.ContentType,
+		&disbursement.SignedAgreement.Size,
 		&disbursement.DisbursedAt,
 	)
 	if err != nil {
@@ -372,3 +572,74 @@ func (r *DisbursementRepository) GetByLoanID(ctx context.Context, loanID uuid.UU
 	}
 	return &disbursement, nil
 }
+
+// UpdateSignedAgreement points the loan's disbursement at a new version of
+// its signed agreement.
+func (r *DisbursementRepository) UpdateSignedAgreement(ctx context.Context, loanID uuid.UUID, file domain.StoredFile) error {
+	conn := r.db.GetConn(ctx)
+	query := `
+		UPDATE disbursements
+		SET signed_agreement_key = $2, signed_agreement_content_type = $3, signed_agreement_size = $4
+		WHERE loan_id = $1
+	`
+	tag, err := conn.Exec(ctx, query, loanID, file.Key, file.ContentType, file.Size)
+	if err != nil {
+		return fmt.Errorf("failed to update disbursement: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDisbursementNotFound
+	}
+	return nil
+}
+
+// coordinates splits p into nullable latitude and longitude columns.
+func coordinates(p *domain.GeoPoint) (latitude, longitude *float64) {
+	if p == nil {
+		return nil, nil
+	}
+	return &p.Latitude, &p.Longitude
+}
+
+func geoPoint(latitude, longitude *float64) *domain.GeoPoint {
+	if latitude == nil || longitude == nil {
+		return nil
+	}
+	return &domain.GeoPoint{Latitude: *latitude, Longitude: *longitude}
+}
+
+// signatureColumns holds a loan's nullable agreement signature columns.
+type signatureColumns struct {
+	sha256      *string
+	signature   []byte
+	fingerprint *string
+	signedAt    *time.Time
+}
+
+func newSignatureColumns(s *domain.AgreementSignature) signatureColumns {
+	if s == nil {
+		return signatureColumns{}
+	}
+	return signatureColumns{
+		sha256:      &s.SHA256,
+		signature:   s.Signature,
+		fingerprint: &s.SignerFingerprint,
+		signedAt:    &s.SignedAt,
+	}
+}
+
+func (c signatureColumns) agreementSignature() *domain.AgreementSignature {
+	if c.signature == nil {
+		return nil
+	}
+	s := &domain.AgreementSignature{Signature: c.signature}
+	if c.sha256 != nil {
+		s.SHA256 = *c.sha256
+	}
+	if c.fingerprint != nil {
+		s.SignerFingerprint = *c.fingerprint
+	}
+	if c.signedAt != nil {
+		s.SignedAt = *c.signedAt
+	}
+	return s
+}
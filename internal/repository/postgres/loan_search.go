@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+)
+
+// searchQuery matches loans by full-text search over the generated
+// search_vector, by substring (ILIKE, served by the trigram indexes) and by
+// trigram similarity for misspellings. Rank is the text rank plus the best
+// similarity across the searchable fields.
+//
+// $1 is the raw query, $2 the escaped ILIKE pattern, $3/$4 limit and offset.
+const searchQuery = `
+	WITH q AS (
+		SELECT websearch_to_tsquery('simple', $1) AS tsq
+	)
+	SELECT l.id, l.borrower_id, l.borrower_name, l.notes, l.principal_amount, l.rate, l.roi, l.product_id, l.tenor_months, l.state,
+	       l.agreement_letter_key, l.total_invested, l.created_at, l.updated_at, l.borrower_latitude, l.borrower_longitude,
+	       l.agreement_sha256, l.agreement_signature, l.agreement_signer_fingerprint, l.agreement_signed_at,
+	       a.field_validator_id, d.field_officer_id,
+	       ts_rank(l.search_vector, q.tsq) + GREATEST(
+	           similarity(l.borrower_id, $1),
+	           similarity(l.borrower_name, $1),
+	           word_similarity($1, l.notes),
+	           similarity(COALESCE(a.field_validator_id, ''), $1),
+	           similarity(COALESCE(d.field_officer_id, ''), $1)
+	       )::float8 AS rank,
+	       COUNT(*) OVER () AS total
+	FROM loans l
+	CROSS JOIN q
+	LEFT JOIN approvals a ON a.loan_id = l.id
+	LEFT JOIN disbursements d ON d.loan_id = l.id
+	WHERE l.search_vector @@ q.tsq
+	   OR l.borrower_id ILIKE $2
+	   OR l.borrower_name ILIKE $2
+	   OR l.notes ILIKE $2
+	   OR a.field_validator_id ILIKE $2
+	   OR d.field_officer_id ILIKE $2
+	   OR l.borrower_id % $1
+	   OR l.borrower_name % $1
+	   OR $1 <% l.notes
+	ORDER BY rank DESC, l.created_at DESC, l.id
+	LIMIT $3 OFFSET $4
+`
+
+// Search returns loans matching query.Text, best match first, and the total
+// number of matches.
+func (r *LoanRepository) Search(ctx context.Context, query repository.SearchQuery) ([]*repository.SearchHit, int64, error) {
+	conn := r.db.GetConn(ctx)
+
+	rows, err := conn.Query(ctx, searchQuery, query.Text, likePattern(query.Text), query.Limit, query.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search loans: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []*repository.SearchHit
+	var total int64
+	for rows.Next() {
+		var loan domain.Loan
+		hit := &repository.SearchHit{Loan: &loan}
+		var latitude, longitude *float64
+		var signature signatureColumns
+		err := rows.Scan(
+			&loan.ID,
+			&loan.BorrowerID,
+			&loan.BorrowerName,
+			&loan.Notes,
+			&loan.PrincipalAmount,
+			&loan.Rate,
+			&loan.ROI,
+			&loan.ProductID,
+			&loan.TenorMonths,
+			&loan.State,
+			&loan.AgreementLetterKey,
+			&loan.TotalInvested,
+			&loan.CreatedAt,
+			&loan.UpdatedAt,
+			&latitude,
+			&longitude,
+			&signature.sha256,
+			&signature.signature,
+			&signature.fingerprint,
+			&signature.signedAt,
+			&hit.FieldValidatorID,
+			&hit.FieldOfficerID,
+			&hit.Rank,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan search hit: %w", err)
+		}
+		loan.BorrowerLocation = geoPoint(latitude, longitude)
+		loan.AgreementSignature = signature.agreementSignature()
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to search loans: %w", err)
+	}
+
+	return hits, total, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePattern builds a "contains" ILIKE pattern with wildcards in s escaped.
+func likePattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrationCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		version uint
+		dirty   bool
+		err     error
+		wantErr bool
+	}{
+		{"at expected", 4, false, nil, false},
+		{"ahead of expected", 5, false, nil, false},
+		{"behind expected", 3, false, nil, true},
+		{"dirty", 4, true, nil, true},
+		{"unreadable", 0, false, errors.New("connection refused"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := migrationCheck(4, func(context.Context) (uint, bool, error) {
+				return tt.version, tt.dirty, tt.err
+			})
+			if err := check(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLatestMigrationVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"000001_create_loans.up.sql",
+		"000001_create_loans.down.sql",
+		"000012_add_documents.up.sql",
+		"000013_add_previews.down.sql",
+		"README.md",
+		"draft.up.sql",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	version, err := LatestMigrationVersion(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != 12 {
+		t.Errorf("expected version 12, got %d", version)
+	}
+
+	if _, err := LatestMigrationVersion(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for a missing directory")
+	}
+}
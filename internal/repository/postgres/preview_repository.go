@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type PreviewRepository struct {
+	db *DB
+}
+
+func NewPreviewRepository(db *DB) *PreviewRepository {
+	return &PreviewRepository{db: db}
+}
+
+func (r *PreviewRepository) Create(ctx context.Context, preview *domain.Preview) error {
+	conn := r.db.GetConn(ctx)
+	query := `
+		INSERT INTO previews (source_key, storage_key, content_type, size, width, height, page_count, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := conn.Exec(ctx, query,
+		preview.SourceKey,
+		preview.File.Key,
+		preview.File.ContentType,
+		preview.File.Size,
+		preview.Width,
+		preview.Height,
+		preview.PageCount,
+		preview.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create preview: %w", err)
+	}
+	return nil
+}
+
+func (r *PreviewRepository) GetBySourceKey(ctx context.Context, sourceKey string) (*domain.Preview, error) {
+	conn := r.db.GetConn(ctx)
+	query := `
+		SELECT source_key, storage_key, content_type, size, width, height, page_count, created_at
+		FROM previews
+		WHERE source_key = $1
+	`
+	var preview domain.Preview
+	err := conn.QueryRow(ctx, query, sourceKey).Scan(
+		&preview.SourceKey,
+		&preview.File.Key,
+		&preview.File.ContentType,
+		&preview.File.Size,
+		&preview.Width,
+		&preview.Height,
+		&preview.PageCount,
+		&preview.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPreviewNotFound
+		}
+		return nil, fmt.Errorf("failed to get preview: %w", err)
+	}
+	return &preview, nil
+}
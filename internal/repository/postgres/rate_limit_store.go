@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/ratelimit"
+)
+
+// RateLimitStore keeps token buckets in Postgres so limits are shared by all
+// API instances. Each take locks the bucket row for the duration of a short
+// transaction.
+type RateLimitStore struct {
+	db *DB
+}
+
+func NewRateLimitStore(db *DB) *RateLimitStore {
+	return &RateLimitStore{db: db}
+}
+
+func (s *RateLimitStore) Take(ctx context.Context, key string, policy ratelimit.Policy, now time.Time) (ratelimit.Result, error) {
+	var result ratelimit.Result
+
+	err := s.db.WithTransaction(ctx, func(txCtx context.Context) error {
+		conn := s.db.GetConn(txCtx)
+
+		_, err := conn.Exec(txCtx, `
+			INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (key) DO NOTHING
+		`, key, policy.Limit, now)
+		if err != nil {
+			return fmt.Errorf("failed to create rate limit bucket: %w", err)
+		}
+
+		var bucket ratelimit.Bucket
+		err = conn.QueryRow(txCtx, `
+			SELECT tokens, updated_at
+			FROM rate_limit_buckets
+			WHERE key = $1
+			FOR UPDATE
+		`, key).Scan(&bucket.Tokens, &bucket.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to get rate limit bucket: %w", err)
+		}
+
+		bucket, result = policy.Take(bucket, now)
+
+		_, err = conn.Exec(txCtx, `
+			UPDATE rate_limit_buckets
+			SET tokens = $2, updated_at = $3
+			WHERE key = $1
+		`, key, bucket.Tokens, bucket.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to update rate limit bucket: %w", err)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// DeleteIdle removes buckets not taken from since before. Buckets idle for
+// longer than their policy period are full again, so dropping them changes no
+// client's limit.
+func (s *RateLimitStore) DeleteIdle(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.db.GetConn(ctx).Exec(ctx, `
+		DELETE FROM rate_limit_buckets
+		WHERE updated_at < $1
+	`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete idle rate limit buckets: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+type StoredFileRepository struct {
+	db *DB
+}
+
+func NewStoredFileRepository(db *DB) *StoredFileRepository {
+	return &StoredFileRepository{db: db}
+}
+
+// ReferencedKeys returns which of keys any row still points at.
+func (r *StoredFileRepository) ReferencedKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	conn := r.db.GetConn(ctx)
+	query := `
+		SELECT picture_proof_key FROM approvals WHERE picture_proof_key = ANY($1)
+		UNION
+		SELECT signed_agreement_key FROM disbursements WHERE signed_agreement_key = ANY($1)
+		UNION
+		SELECT agreement_letter_key FROM loans WHERE agreement_letter_key = ANY($1)
+		UNION
+		SELECT storage_key FROM documents WHERE storage_key = ANY($1)
+		UNION
+		SELECT p.storage_key FROM previews p JOIN documents d ON d.storage_key = p.source_key
+		WHERE p.storage_key = ANY($1)
+	`
+	rows, err := conn.Query(ctx, query, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query referenced keys: %w", err)
+	}
+	defer rows.Close()
+
+	referenced := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan key: %w", err)
+		}
+		referenced[key] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate keys: %w", err)
+	}
+	return referenced, nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/agunghallmanmaliki/amartha/internal/telemetry"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryTracer emits a client span for every query executed through pgx and
+// logs the ones that fail. Failures the repositories map to domain errors
+// are logged at debug level, so that only unexpected ones reach error logs.
+type queryTracer struct {
+	logger *slog.Logger
+}
+
+type queryOperationKey struct{}
+
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation := queryOperation(data.SQL)
+	ctx = context.WithValue(ctx, queryOperationKey{}, operation)
+
+	ctx, _ = telemetry.Tracer().Start(ctx, "db "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.query.text", data.SQL),
+		),
+	)
+	return ctx
+}
+
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err == nil {
+		span.SetAttributes(attribute.Int64("db.response.affected_rows", data.CommandTag.RowsAffected()))
+	} else {
+		t.logger.Log(ctx, queryErrorLevel(data.Err), "query failed",
+			"operation", ctx.Value(queryOperationKey{}),
+			"error", data.Err,
+		)
+	}
+	telemetry.End(span, data.Err)
+}
+
+// queryErrorLevel is the level a failed query is logged at: debug for the
+// outcomes repositories turn into domain errors (no rows, unique and foreign
+// key violations), warn for cancelled queries and error otherwise.
+func queryErrorLevel(err error) slog.Level {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows), isPgError(err, uniqueViolation), isPgError(err, foreignKeyViolation):
+		return slog.LevelDebug
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// queryOperation returns the leading SQL keyword, e.g. SELECT or INSERT.
+func queryOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
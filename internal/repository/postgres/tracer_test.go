@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestQueryErrorLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want slog.Level
+	}{
+		{"no rows", pgx.ErrNoRows, slog.LevelDebug},
+		{"wrapped no rows", fmt.Errorf("failed to get loan: %w", pgx.ErrNoRows), slog.LevelDebug},
+		{"unique violation", &pgconn.PgError{Code: uniqueViolation}, slog.LevelDebug},
+		{"foreign key violation", &pgconn.PgError{Code: foreignKeyViolation}, slog.LevelDebug},
+		{"cancelled", context.Canceled, slog.LevelWarn},
+		{"syntax error", &pgconn.PgError{Code: "42601"}, slog.LevelError},
+		{"connection lost", errors.New("conn closed"), slog.LevelError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryErrorLevel(tt.err); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQueryOperation(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"\n\t\tSELECT id FROM loans", "SELECT"},
+		{"insert into loans values ($1)", "INSERT"},
+		{"", "QUERY"},
+	}
+
+	for _, tt := range tests {
+		if got := queryOperation(tt.sql); got != tt.want {
+			t.Errorf("expected %s for %q, got %s", tt.want, tt.sql, got)
+		}
+	}
+}
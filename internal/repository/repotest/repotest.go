@@ -0,0 +1,411 @@
+// Package repotest provides in-memory repositories for tests of the layers
+// above the repository. They keep copies of what they are given, as the
+// Postgres repositories do, but have no transactions.
+package repotest
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Repositories holds one of each repository a LoanService needs.
+type Repositories struct {
+	Loans         *LoanRepository
+	Products      *LoanProductRepository
+	Approvals     *ApprovalRepository
+	Investments   *InvestmentRepository
+	Disbursements *DisbursementRepository
+	Documents     *DocumentRepository
+	Previews      *PreviewRepository
+	TxManager     TxManager
+}
+
+func New() *Repositories {
+	return &Repositories{
+		Loans:         &LoanRepository{loans: map[uuid.UUID]*domain.Loan{}},
+		Products:      &LoanProductRepository{products: map[uuid.UUID]*domain.LoanProduct{}},
+		Approvals:     &ApprovalRepository{approvals: map[uuid.UUID]*domain.Approval{}},
+		Investments:   &InvestmentRepository{},
+		Disbursements: &DisbursementRepository{disbursements: map[uuid.UUID]*domain.Disbursement{}},
+		Documents:     &DocumentRepository{documents: map[uuid.UUID]*domain.Document{}},
+		Previews:      &PreviewRepository{previews: map[string]*domain.Preview{}},
+	}
+}
+
+// TxManager runs fn directly; nothing is rolled back when it fails.
+type TxManager struct{}
+
+func (TxManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type LoanRepository struct {
+	mu    sync.Mutex
+	loans map[uuid.UUID]*domain.Loan
+	order []uuid.UUID
+}
+
+func (r *LoanRepository) Create(ctx context.Context, loan *domain.Loan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *loan
+	r.loans[loan.ID] = &stored
+	r.order = append(r.order, loan.ID)
+	return nil
+}
+
+func (r *LoanRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Loan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	loan, ok := r.loans[id]
+	if !ok {
+		return nil, domain.ErrLoanNotFound
+	}
+	found := *loan
+	return &found, nil
+}
+
+func (r *LoanRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Loan, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *LoanRepository) Update(ctx context.Context, loan *domain.Loan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.loans[loan.ID]; !ok {
+		return domain.ErrLoanNotFound
+	}
+	stored := *loan
+	r.loans[loan.ID] = &stored
+	return nil
+}
+
+// List returns loans in the order they were created, filtered by state.
+func (r *LoanRepository) List(ctx context.Context, filter repository.LoanFilter) ([]*domain.Loan, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var loans []*domain.Loan
+	for _, id := range r.order {
+		loan := *r.loans[id]
+		if filter.State != nil && loan.State != *filter.State {
+			continue
+		}
+		loans = append(loans, &loan)
+	}
+	return loans, int64(len(loans)), nil
+}
+
+func (r *LoanRepository) Stream(ctx context.Context, filter repository.LoanFilter, fn func(*domain.Loan) error) error {
+	loans, _, _ := r.List(ctx, filter)
+	for _, loan := range loans {
+		if err := fn(loan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Search matches the query text as a substring of the borrower and notes,
+// ranking every hit equally.
+func (r *LoanRepository) Search(ctx context.Context, query repository.SearchQuery) ([]*repository.SearchHit, int64, error) {
+	loans, _, _ := r.List(ctx, repository.LoanFilter{})
+	text := strings.ToLower(query.Text)
+	var hits []*repository.SearchHit
+	for _, loan := range loans {
+		if strings.Contains(strings.ToLower(loan.BorrowerID+" "+loan.BorrowerName+" "+loan.Notes), text) {
+			hits = append(hits, &repository.SearchHit{Loan: loan, Rank: 1})
+		}
+	}
+	return hits, int64(len(hits)), nil
+}
+
+type LoanProductRepository struct {
+	mu       sync.Mutex
+	products map[uuid.UUID]*domain.LoanProduct
+}
+
+func (r *LoanProductRepository) Create(ctx context.Context, product *domain.LoanProduct) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.products {
+		if existing.Code == product.Code {
+			return domain.ErrLoanProductCodeTaken
+		}
+	}
+	stored := *product
+	r.products[product.ID] = &stored
+	return nil
+}
+
+func (r *LoanProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.LoanProduct, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	product, ok := r.products[id]
+	if !ok {
+		return nil, domain.ErrLoanProductNotFound
+	}
+	found := *product
+	return &found, nil
+}
+
+func (r *LoanProductRepository) List(ctx context.Context, filter repository.LoanProductFilter) ([]*domain.LoanProduct, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	products := []*domain.LoanProduct{}
+	for _, product := range r.products {
+		if filter.ActiveOnly && !product.Active {
+			continue
+		}
+		found := *product
+		products = append(products, &found)
+	}
+	return products, nil
+}
+
+func (r *LoanProductRepository) Update(ctx context.Context, product *domain.LoanProduct) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.products[product.ID]; !ok {
+		return domain.ErrLoanProductNotFound
+	}
+	stored := *product
+	r.products[product.ID] = &stored
+	return nil
+}
+
+func (r *LoanProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.products[id]; !ok {
+		return domain.ErrLoanProductNotFound
+	}
+	delete(r.products, id)
+	return nil
+}
+
+type ApprovalRepository struct {
+	mu        sync.Mutex
+	approvals map[uuid.UUID]*domain.Approval
+}
+
+func (r *ApprovalRepository) Create(ctx context.Context, approval *domain.Approval) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *approval
+	r.approvals[approval.LoanID] = &stored
+	return nil
+}
+
+func (r *ApprovalRepository) GetByLoanID(ctx context.Context, loanID uuid.UUID) (*domain.Approval, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	approval, ok := r.approvals[loanID]
+	if !ok {
+		return nil, domain.ErrApprovalNotFound
+	}
+	found := *approval
+	return &found, nil
+}
+
+func (r *ApprovalRepository) UpdatePictureProof(ctx context.Context, loanID uuid.UUID, file domain.StoredFile, photo domain.PhotoMetadata) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	approval, ok := r.approvals[loanID]
+	if !ok {
+		return domain.ErrApprovalNotFound
+	}
+	approval.PictureProof = file
+	approval.Photo = photo
+	return nil
+}
+
+type InvestmentRepository struct {
+	mu          sync.Mutex
+	investments []*domain.Investment
+}
+
+func (r *InvestmentRepository) Create(ctx context.Context, investment *domain.Investment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *investment
+	r.investments = append(r.investments, &stored)
+	return nil
+}
+
+func (r *InvestmentRepository) ListByLoanID(ctx context.Context, loanID uuid.UUID) ([]*domain.Investment, error) {
+	return r.list(repository.InvestmentFilter{LoanID: &loanID}), nil
+}
+
+func (r *InvestmentRepository) GetInvestorsByLoanID(ctx context.Context, loanID uuid.UUID) ([]string, error) {
+	seen := map[string]bool{}
+	var investors []string
+	for _, investment := range r.list(repository.InvestmentFilter{LoanID: &loanID}) {
+		if !seen[investment.InvestorID] {
+			seen[investment.InvestorID] = true
+			investors = append(investors, investment.InvestorID)
+		}
+	}
+	return investors, nil
+}
+
+func (r *InvestmentRepository) Stream(ctx context.Context, filter repository.InvestmentFilter, fn func(*domain.Investment) error) error {
+	for _, investment := range r.list(filter) {
+		if err := fn(investment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *InvestmentRepository) list(filter repository.InvestmentFilter) []*domain.Investment {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var investments []*domain.Investment
+	for _, investment := range r.investments {
+		if filter.LoanID != nil && investment.LoanID != *filter.LoanID {
+			continue
+		}
+		if filter.InvestorID != nil && investment.InvestorID != *filter.InvestorID {
+			continue
+		}
+		found := *investment
+		investments = append(investments, &found)
+	}
+	return investments
+}
+
+type DisbursementRepository struct {
+	mu            sync.Mutex
+	disbursements map[uuid.UUID]*domain.Disbursement
+}
+
+func (r *DisbursementRepository) Create(ctx context.Context, disbursement *domain.Disbursement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *disbursement
+	r.disbursements[disbursement.LoanID] = &stored
+	return nil
+}
+
+func (r *DisbursementRepository) GetByLoanID(ctx context.Context, loanID uuid.UUID) (*domain.Disbursement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	disbursement, ok := r.disbursements[loanID]
+	if !ok {
+		return nil, domain.ErrDisbursementNotFound
+	}
+	found := *disbursement
+	return &found, nil
+}
+
+func (r *DisbursementRepository) UpdateSignedAgreement(ctx context.Context, loanID uuid.UUID, file domain.StoredFile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	disbursement, ok := r.disbursements[loanID]
+	if !ok {
+		return domain.ErrDisbursementNotFound
+	}
+	disbursement.SignedAgreement = file
+	return nil
+}
+
+type DocumentRepository struct {
+	mu        sync.Mutex
+	documents map[uuid.UUID]*domain.Document
+}
+
+func (r *DocumentRepository) Create(ctx context.Context, document *domain.Document) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *document
+	r.documents[document.ID] = &stored
+	return nil
+}
+
+func (r *DocumentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	document, ok := r.documents[id]
+	if !ok {
+		return nil, domain.ErrDocumentNotFound
+	}
+	found := *document
+	return &found, nil
+}
+
+func (r *DocumentRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Document, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *DocumentRepository) GetBySHA256(ctx context.Context, sha256 string) (*domain.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, document := range r.documents {
+		if document.File.SHA256 == sha256 {
+			found := *document
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrDocumentNotFound
+}
+
+// ListByLoanID orders documents by purpose and then version.
+func (r *DocumentRepository) ListByLoanID(ctx context.Context, loanID uuid.UUID) ([]*domain.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var documents []*domain.Document
+	for _, document := range r.documents {
+		if document.LoanID == loanID {
+			found := *document
+			documents = append(documents, &found)
+		}
+	}
+	sort.Slice(documents, func(i, j int) bool {
+		if documents[i].Purpose != documents[j].Purpose {
+			return documents[i].Purpose < documents[j].Purpose
+		}
+		return documents[i].Version < documents[j].Version
+	})
+	return documents, nil
+}
+
+func (r *DocumentRepository) Update(ctx context.Context, document *domain.Document) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.documents[document.ID]; !ok {
+		return domain.ErrDocumentNotFound
+	}
+	stored := *document
+	r.documents[document.ID] = &stored
+	return nil
+}
+
+type PreviewRepository struct {
+	mu       sync.Mutex
+	previews map[string]*domain.Preview
+}
+
+func (r *PreviewRepository) Create(ctx context.Context, preview *domain.Preview) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *preview
+	r.previews[preview.SourceKey] = &stored
+	return nil
+}
+
+func (r *PreviewRepository) GetBySourceKey(ctx context.Context, sourceKey string) (*domain.Preview, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	preview, ok := r.previews[sourceKey]
+	if !ok {
+		return nil, domain.ErrPreviewNotFound
+	}
+	found := *preview
+	return &found, nil
+}
@@ -0,0 +1,74 @@
+package requestctx
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// HeaderForwardedFor lists the client address and the proxies a request
+// passed through, as appended to by each proxy.
+const HeaderForwardedFor = "X-Forwarded-For"
+
+// TrustedProxies are the peers, such as the API gateway, that authenticate
+// callers and forward who they are in the actor headers. The service does
+// not authenticate callers itself, so it believes the actor headers and
+// X-Forwarded-For only from these peers.
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses CIDR prefixes or single addresses.
+func ParseTrustedProxies(entries []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+			}
+			proxies = append(proxies, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+// Trusts reports whether addr is a trusted proxy.
+func (p TrustedProxies) Trusts(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range p {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address of the client behind peer. Proxies append
+// the address they received a request from to X-Forwarded-For, so walking it
+// from the right the first address that is not a trusted proxy is the client;
+// anything left of it was sent by the client and cannot be believed.
+func (p TrustedProxies) ClientIP(peer netip.Addr, forwardedFor []string) netip.Addr {
+	client := peer.Unmap()
+	if !p.Trusts(client) {
+		return client
+	}
+	for i := len(forwardedFor) - 1; i >= 0; i-- {
+		hops := strings.Split(forwardedFor[i], ",")
+		for j := len(hops) - 1; j >= 0; j-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hops[j]))
+			if err != nil {
+				return client
+			}
+			client = addr.Unmap()
+			if !p.Trusts(client) {
+				return client
+			}
+		}
+	}
+	return client
+}
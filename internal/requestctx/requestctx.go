@@ -0,0 +1,57 @@
+package requestctx
+
+import "context"
+
+const (
+	HeaderRequestID = "X-Request-ID"
+	HeaderActorID   = "X-Actor-ID"
+	HeaderActorRole = "X-Actor-Role"
+)
+
+// Info describes the request a context belongs to. It is stored by pointer so
+// that inner handlers (e.g. the router, once it has matched a route) can fill
+// in fields that outer middleware read after the handler returns.
+type Info struct {
+	RequestID string
+	Route     string
+	ActorID   string
+	ActorRole string
+	// ClientIP is the caller's address, as forwarded by a trusted proxy or
+	// else the peer's.
+	ClientIP string
+}
+
+type infoKey struct{}
+
+func NewContext(ctx context.Context, info *Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// FromContext returns the request info carried by ctx, or nil if there is none.
+func FromContext(ctx context.Context) *Info {
+	info, _ := ctx.Value(infoKey{}).(*Info)
+	return info
+}
+
+func RequestID(ctx context.Context) string {
+	if info := FromContext(ctx); info != nil {
+		return info.RequestID
+	}
+	return ""
+}
+
+const maxRequestIDLength = 128
+
+// ValidRequestID reports whether a caller-supplied request id is safe to echo
+// and log: 1-128 printable ASCII characters without spaces.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// chunkSize is how much content is sent to clamd in each INSTREAM chunk.
+const chunkSize = 32 * 1024
+
+// errReadContent marks a failure to read the content being scanned, after
+// which clamd is still waiting for more and will not answer.
+var errReadContent = errors.New("failed to read content")
+
+// Clamd scans content with a clamd daemon over its INSTREAM command.
+type Clamd struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamd returns a client of the clamd daemon at address, either
+// tcp://host:port or unix:///path/to/clamd.sock. timeout bounds every read
+// and write, including waiting for the verdict once content is sent.
+func NewClamd(address string, timeout time.Duration) (*Clamd, error) {
+	if timeout <= 0 {
+		return nil, errors.New("clamd timeout must be positive")
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clamd address %q: %w", address, err)
+	}
+	switch {
+	case u.Scheme == "tcp" && u.Host != "":
+		return &Clamd{network: "tcp", address: u.Host, timeout: timeout}, nil
+	case u.Scheme == "unix" && u.Path != "":
+		return &Clamd{network: "unix", address: u.Path, timeout: timeout}, nil
+	default:
+		return nil, fmt.Errorf("clamd address %q must be tcp://host:port or unix:///path", address)
+	}
+}
+
+// Scan streams content to clamd and returns its verdict.
+func (c *Clamd) Scan(ctx context.Context, content io.Reader) (Verdict, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	writeErr := c.send(conn, content)
+	if errors.Is(writeErr, errReadContent) {
+		return Verdict{}, writeErr
+	}
+
+	// clamd answers early, e.g. when the stream exceeds its size limit, so
+	// its reply explains a failed write better than the write error does.
+	conn.SetReadDeadline(time.Now().Add(c.timeout))
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		if ctx.Err() != nil {
+			return Verdict{}, ctx.Err()
+		}
+		if writeErr != nil {
+			return Verdict{}, writeErr
+		}
+		return Verdict{}, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return parseReply(strings.TrimSuffix(reply, "\x00"))
+}
+
+// send writes the INSTREAM command, content in length-prefixed chunks and
+// the zero-length chunk that ends it.
+func (c *Clamd) send(conn net.Conn, content io.Reader) error {
+	write := func(b []byte) error {
+		conn.SetWriteDeadline(time.Now().Add(c.timeout))
+		_, err := conn.Write(b)
+		return err
+	}
+
+	if err := write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("failed to send command to clamd: %w", err)
+	}
+	chunk := make([]byte, 4+chunkSize)
+	for {
+		n, err := content.Read(chunk[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk, uint32(n))
+			if err := write(chunk[:4+n]); err != nil {
+				return fmt.Errorf("failed to send content to clamd: %w", err)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", errReadContent, err)
+		}
+	}
+	if err := write([]byte{0, 0, 0, 0}); err != nil {
+		return fmt.Errorf("failed to send content to clamd: %w", err)
+	}
+	return nil
+}
+
+// parseReply reads "stream: OK", "stream: <signature> FOUND" or an error
+// such as "INSTREAM size limit exceeded. ERROR".
+func parseReply(reply string) (Verdict, error) {
+	result := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case result == "OK":
+		return Verdict{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return Verdict{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return Verdict{}, fmt.Errorf("clamd: %s", reply)
+	}
+}
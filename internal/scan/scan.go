@@ -0,0 +1,106 @@
+// Package scan checks uploads for malware before they are committed, since
+// staff open the documents field officers upload.
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+)
+
+// Scanner checks content for malware.
+type Scanner interface {
+	Scan(ctx context.Context, content io.Reader) (Verdict, error)
+}
+
+// Verdict is the outcome of a scan. Signature names the malware found.
+type Verdict struct {
+	Infected  bool
+	Signature string
+}
+
+// Gate saves uploads to staging while scanning them, so that only clean
+// files are handed on to be committed.
+type Gate struct {
+	scanner  Scanner
+	storage  storage.Inventory
+	failOpen bool
+	logger   *slog.Logger
+}
+
+// NewGate scans uploads saved to storage with scanner; a nil scanner saves
+// them unscanned. failOpen accepts uploads the scanner fails on instead of
+// rejecting them.
+func NewGate(scanner Scanner, storage storage.Inventory, failOpen bool, logger *slog.Logger) *Gate {
+	return &Gate{
+		scanner:  scanner,
+		storage:  storage,
+		failOpen: failOpen,
+		logger:   logger,
+	}
+}
+
+// Save stages content as storage.Storage.Save does, scanning it as it is
+// written. An infected file is moved to quarantine and reported as a
+// violation of field wrapping domain.ErrFileInfected. A file the scanner
+// fails on is discarded and domain.ErrScanUnavailable returned, unless the
+// gate fails open.
+func (g *Gate) Save(ctx context.Context, field, name string, content io.Reader) (string, error) {
+	if g.scanner == nil {
+		return g.storage.Save(ctx, name, content)
+	}
+
+	reader, writer := io.Pipe()
+	done := make(chan struct{})
+	var verdict Verdict
+	var scanErr error
+	go func() {
+		defer close(done)
+		verdict, scanErr = g.scanner.Scan(ctx, reader)
+		// Keep reading, so a scanner that stopped early does not hold up
+		// the save.
+		io.Copy(io.Discard, reader)
+	}()
+
+	key, err := g.storage.Save(ctx, name, io.TeeReader(content, writer))
+	writer.CloseWithError(err)
+	<-done
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case scanErr != nil && g.failOpen:
+		g.logger.WarnContext(ctx, "accepting upload that could not be scanned", "field", field, "key", key, "error", scanErr)
+		return key, nil
+	case scanErr != nil:
+		g.logger.ErrorContext(ctx, "rejecting upload that could not be scanned", "field", field, "key", key, "error", scanErr)
+		if err := g.storage.Discard(ctx, key); err != nil {
+			g.logger.WarnContext(ctx, "failed to discard upload", "key", key, "error", err)
+		}
+		return "", fmt.Errorf("%w: %v", domain.ErrScanUnavailable, scanErr)
+	case verdict.Infected:
+		g.logger.WarnContext(ctx, "quarantining infected upload", "field", field, "key", key, "signature", verdict.Signature)
+		if err := g.storage.Quarantine(ctx, storage.Staging, key); err != nil {
+			g.logger.ErrorContext(ctx, "failed to quarantine upload", "key", key, "error", err)
+			if err := g.storage.Discard(ctx, key); err != nil {
+				g.logger.WarnContext(ctx, "failed to discard upload", "key", key, "error", err)
+			}
+		}
+		return "", &domain.ViolationError{
+			Err: domain.ErrFileInfected,
+			Violations: []domain.Violation{{
+				Field:   field,
+				Rule:    "malware",
+				Param:   verdict.Signature,
+				Message: fmt.Sprintf("%s contains malware (%s)", field, verdict.Signature),
+			}},
+		}
+	default:
+		return key, nil
+	}
+}
@@ -0,0 +1,271 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+	"github.com/agunghallmanmaliki/amartha/internal/storage/local"
+)
+
+// eicar is the standard anti-virus test file.
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd answers INSTREAM commands like clamd: content holding the
+// EICAR string is infected, and streams over maxSize are refused.
+type fakeClamd struct {
+	listener net.Listener
+	maxSize  int
+}
+
+func startFakeClamd(t *testing.T, network, address string, maxSize int) *fakeClamd {
+	t.Helper()
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	d := &fakeClamd{listener: listener, maxSize: maxSize}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go d.serve(conn)
+		}
+	}()
+	return d
+}
+
+func (d *fakeClamd) address() string {
+	if d.listener.Addr().Network() == "unix" {
+		return "unix://" + d.listener.Addr().String()
+	}
+	return "tcp://" + d.listener.Addr().String()
+}
+
+func (d *fakeClamd) serve(conn net.Conn) {
+	defer conn.Close()
+	command := make([]byte, len("zINSTREAM\x00"))
+	if _, err := io.ReadFull(conn, command); err != nil || string(command) != "zINSTREAM\x00" {
+		conn.Write([]byte("UNKNOWN COMMAND\x00"))
+		return
+	}
+
+	var content bytes.Buffer
+	for {
+		var size uint32
+		if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+			return
+		}
+		if size == 0 {
+			break
+		}
+		if content.Len()+int(size) > d.maxSize {
+			conn.Write([]byte("INSTREAM size limit exceeded. ERROR\x00"))
+			return
+		}
+		if _, err := io.CopyN(&content, conn, int64(size)); err != nil {
+			return
+		}
+	}
+
+	if bytes.Contains(content.Bytes(), []byte(eicar)) {
+		conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		return
+	}
+	conn.Write([]byte("stream: OK\x00"))
+}
+
+func TestClamdScan(t *testing.T) {
+	tcp := startFakeClamd(t, "tcp", "127.0.0.1:0", 1<<20)
+	unix := startFakeClamd(t, "unix", filepath.Join(t.TempDir(), "clamd.sock"), 1<<20)
+
+	tests := []struct {
+		name          string
+		address       string
+		content       string
+		wantInfected  bool
+		wantSignature string
+		wantErr       bool
+	}{
+		{name: "clean", address: tcp.address(), content: "%PDF-1.4 agreement"},
+		{name: "empty", address: tcp.address(), content: ""},
+		{name: "clean across chunks", address: tcp.address(), content: strings.Repeat("a", 3*chunkSize+7)},
+		{name: "infected", address: tcp.address(), content: eicar, wantInfected: true, wantSignature: "Eicar-Test-Signature"},
+		{name: "infected after a chunk", address: tcp.address(), content: strings.Repeat("a", chunkSize+10) + eicar, wantInfected: true, wantSignature: "Eicar-Test-Signature"},
+		{name: "over the size limit", address: tcp.address(), content: strings.Repeat("a", 1<<20+1), wantErr: true},
+		{name: "unix socket", address: unix.address(), content: eicar, wantInfected: true, wantSignature: "Eicar-Test-Signature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clamd, err := NewClamd(tt.address, 5*time.Second)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+			verdict, err := clamd.Scan(context.Background(), strings.NewReader(tt.content))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", verdict)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if verdict.Infected != tt.wantInfected || verdict.Signature != tt.wantSignature {
+				t.Errorf("expected infected=%v %q, got %+v", tt.wantInfected, tt.wantSignature, verdict)
+			}
+		})
+	}
+}
+
+func TestClamdUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	address := "tcp://" + listener.Addr().String()
+	listener.Close()
+
+	clamd, err := NewClamd(address, time.Second)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, err := clamd.Scan(context.Background(), strings.NewReader("data")); err == nil {
+		t.Error("expected an error when clamd is not listening")
+	}
+}
+
+func TestNewClamd(t *testing.T) {
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{address: "tcp://clamd:3310"},
+		{address: "unix:///run/clamav/clamd.ctl"},
+		{address: "clamd:3310", wantErr: true},
+		{address: "tcp://", wantErr: true},
+		{address: "http://clamd:3310", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		_, err := NewClamd(tt.address, time.Second)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.address, tt.wantErr, err)
+		}
+	}
+}
+
+type scannerFunc func(ctx context.Context, content io.Reader) (Verdict, error)
+
+func (f scannerFunc) Scan(ctx context.Context, content io.Reader) (Verdict, error) {
+	return f(ctx, content)
+}
+
+func TestGateSave(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	clamd := startFakeClamd(t, "tcp", "127.0.0.1:0", 1<<20)
+	client, err := NewClamd(clamd.address(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	unavailable := scannerFunc(func(ctx context.Context, content io.Reader) (Verdict, error) {
+		return Verdict{}, errors.New("connection refused")
+	})
+
+	tests := []struct {
+		name     string
+		scanner  Scanner
+		failOpen bool
+		content  string
+		wantErr  error
+		wantArea storage.Area
+	}{
+		{name: "clean", scanner: client, content: "%PDF-1.4 agreement", wantArea: storage.Staging},
+		{name: "infected", scanner: client, content: eicar, wantErr: domain.ErrFileInfected, wantArea: storage.Quarantined},
+		{name: "unavailable fails closed", scanner: unavailable, content: "%PDF-1.4", wantErr: domain.ErrScanUnavailable},
+		{name: "unavailable fails open", scanner: unavailable, failOpen: true, content: "%PDF-1.4", wantArea: storage.Staging},
+		{name: "scanning disabled", content: eicar, wantArea: storage.Staging},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := local.NewLocalStorage(t.TempDir(), "http://localhost:8080", []byte("secret"))
+			if err != nil {
+				t.Fatalf("failed to create storage: %v", err)
+			}
+			gate := NewGate(tt.scanner, store, tt.failOpen, logger)
+
+			key, err := gate.Save(context.Background(), "signed_agreement", "signed_agreement.pdf", strings.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			var verr *domain.ViolationError
+			if errors.Is(err, domain.ErrFileInfected) && (!errors.As(err, &verr) || verr.Violations[0].Field != "signed_agreement" || verr.Violations[0].Param != "Eicar-Test-Signature") {
+				t.Errorf("expected a malware violation on signed_agreement, got %v", err)
+			}
+
+			areas := map[storage.Area]int{}
+			for _, area := range []storage.Area{storage.Staging, storage.Committed, storage.Quarantined} {
+				store.List(context.Background(), area, func(object storage.Object) error {
+					areas[area]++
+					if err == nil && object.Key != key {
+						t.Errorf("expected key %s, got %s", key, object.Key)
+					}
+					return nil
+				})
+			}
+			want := map[storage.Area]int{}
+			if tt.wantArea != "" {
+				want[tt.wantArea] = 1
+			}
+			for _, area := range []storage.Area{storage.Staging, storage.Committed, storage.Quarantined} {
+				if areas[area] != want[area] {
+					t.Errorf("expected %d files in %s, got %d", want[area], area, areas[area])
+				}
+			}
+		})
+	}
+}
+
+func TestGateSaveFailure(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store, err := local.NewLocalStorage(t.TempDir(), "http://localhost:8080", []byte("secret"))
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	scanned := make(chan error, 1)
+	scanner := scannerFunc(func(ctx context.Context, content io.Reader) (Verdict, error) {
+		_, err := io.Copy(io.Discard, content)
+		scanned <- err
+		return Verdict{}, err
+	})
+	gate := NewGate(scanner, store, false, logger)
+
+	readErr := errors.New("client went away")
+	content := io.MultiReader(strings.NewReader("partial"), &failingReader{err: readErr})
+	if _, err := gate.Save(context.Background(), "signed_agreement", "signed_agreement.pdf", content); !errors.Is(err, readErr) {
+		t.Errorf("expected the read error, got %v", err)
+	}
+	if err := <-scanned; !errors.Is(err, readErr) {
+		t.Errorf("expected the scanner to see the read error, got %v", err)
+	}
+}
+
+type failingReader struct{ err error }
+
+func (r *failingReader) Read(p []byte) (int, error) { return 0, r.err }
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/signing"
+	"github.com/agunghallmanmaliki/amartha/internal/telemetry"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SignatureStatus says what checking a document against a loan's agreement
+// signature found.
+type SignatureStatus string
+
+const (
+	// SignatureValid means the signature is ours over the document.
+	SignatureValid SignatureStatus = "valid"
+	// SignatureInvalid means the signature is not over the document.
+	SignatureInvalid SignatureStatus = "invalid"
+	// SignatureUnknownSigner means the letter was signed with a key other
+	// than the one now configured, so the signature cannot be checked.
+	SignatureUnknownSigner SignatureStatus = "unknown_signer"
+	// SignatureUnchecked means signing is not configured, so the signature
+	// cannot be checked.
+	SignatureUnchecked SignatureStatus = "unchecked"
+	// SignatureMissing means the letter was issued unsigned.
+	SignatureMissing SignatureStatus = "unsigned"
+)
+
+// AgreementVerification is how a document compares with the agreement letter
+// issued for a loan. SHA256 is the document's digest; Signature is the
+// letter's signature, nil if it was issued unsigned.
+type AgreementVerification struct {
+	LoanID          uuid.UUID
+	SHA256          string
+	Matches         bool
+	Signature       *domain.AgreementSignature
+	SignatureStatus SignatureStatus
+}
+
+// SigningCertificate returns the PEM certificate, or public key, agreement
+// letters are signed with, or nil if they are not signed.
+func (s *LoanService) SigningCertificate() []byte {
+	if s.signer == nil {
+		return nil
+	}
+	return s.signer.PEM()
+}
+
+// signAgreement signs the loan's new agreement letter, file, replacing the
+// signature of any letter before it. Only without a signer is the letter left
+// unsigned; once signing is configured, a letter without a checksum to sign
+// is an error.
+func (s *LoanService) signAgreement(loan *domain.Loan, file domain.StoredFile) error {
+	loan.AgreementSignature = nil
+	if s.signer == nil {
+		return nil
+	}
+	if file.SHA256 == "" {
+		return fmt.Errorf("failed to sign agreement letter %s: no checksum recorded", file.Key)
+	}
+	signature, err := s.signer.Sign(file.SHA256)
+	if err != nil {
+		return err
+	}
+	loan.AgreementSignature = signature
+	return nil
+}
+
+// VerifyAgreementLetter checks whether document is the agreement letter
+// issued for the loan and whether the letter's signature is ours over it.
+func (s *LoanService) VerifyAgreementLetter(ctx context.Context, loanID uuid.UUID, document io.Reader) (_ *AgreementVerification, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.VerifyAgreementLetter", attribute.String("loan.id", loanID.String()))
+	defer func() { telemetry.End(span, err) }()
+
+	loan, err := s.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if loan.AgreementLetterKey == nil {
+		return nil, domain.ErrAgreementLetterNotFound
+	}
+
+	sum, err := digest(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document: %w", err)
+	}
+
+	verification := &AgreementVerification{
+		LoanID:    loanID,
+		SHA256:    sum,
+		Signature: loan.AgreementSignature,
+	}
+	if loan.AgreementSignature == nil {
+		// Letters issued unsigned are compared with the stored letter.
+		issued, err := s.storedDigest(ctx, *loan.AgreementLetterKey)
+		if err != nil {
+			return nil, err
+		}
+		verification.Matches = sum == issued
+		verification.SignatureStatus = SignatureMissing
+		return verification, nil
+	}
+
+	verification.Matches = sum == loan.AgreementSignature.SHA256
+	if s.signer == nil {
+		verification.SignatureStatus = SignatureUnchecked
+	} else {
+		verification.SignatureStatus = signatureStatus(s.signer.Verify(sum, loan.AgreementSignature))
+	}
+	return verification, nil
+}
+
+func signatureStatus(err error) SignatureStatus {
+	switch {
+	case err == nil:
+		return SignatureValid
+	case errors.Is(err, signing.ErrUnknownSigner):
+		return SignatureUnknownSigner
+	default:
+		return SignatureInvalid
+	}
+}
+
+func (s *LoanService) storedDigest(ctx context.Context, key string) (string, error) {
+	reader, err := s.storage.Open(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	sum, err := digest(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read agreement letter: %w", err)
+	}
+	return sum, nil
+}
+
+func digest(r io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
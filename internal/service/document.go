@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/telemetry"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// newDocument registers file as the first version of a loan document. If an
+// identical file was uploaded before, the document reuses its storage key and
+// the new upload can be discarded.
+func (s *LoanService) newDocument(ctx context.Context, loanID uuid.UUID, purpose domain.DocumentPurpose, uploadedBy string, file domain.StoredFile) (*domain.Document, error) {
+	file, err := s.deduplicate(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewDocument(loanID, purpose, uploadedBy, file), nil
+}
+
+func (s *LoanService) deduplicate(ctx context.Context, file domain.StoredFile) (domain.StoredFile, error) {
+	if file.SHA256 == "" {
+		return file, nil
+	}
+	existing, err := s.documentRepo.GetBySHA256(ctx, file.SHA256)
+	if errors.Is(err, domain.ErrDocumentNotFound) {
+		return file, nil
+	}
+	if err != nil {
+		return file, err
+	}
+	file.Key = existing.File.Key
+	return file, nil
+}
+
+func (s *LoanService) GetDocument(ctx context.Context, documentID uuid.UUID) (_ *domain.Document, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.GetDocument", attribute.String("document.id", documentID.String()))
+	defer func() { telemetry.End(span, err) }()
+
+	return s.documentRepo.GetByID(ctx, documentID)
+}
+
+// ListDocuments returns every version of the loan's documents.
+func (s *LoanService) ListDocuments(ctx context.Context, loanID uuid.UUID) (_ []*domain.Document, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.ListDocuments", attribute.String("loan.id", loanID.String()))
+	defer func() { telemetry.End(span, err) }()
+
+	if _, err := s.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+	return s.documentRepo.ListByLoanID(ctx, loanID)
+}
+
+// SupersedeDocument adds file as the next version of the document, which
+// must be the current one, and points the approval or disbursement (and the
+// loan's agreement letter, which is signed again) at it. role must be allowed
+// the action the document was uploaded for, so only disbursing staff can
+// have a new agreement signed; otherwise domain.ErrRoleNotPermitted is
+// returned and nothing changes. A new picture proof must pass the photo
+// policy, as at approval; photo is ignored for other documents.
+func (s *LoanService) SupersedeDocument(ctx context.Context, documentID uuid.UUID, role domain.Role, uploadedBy string, file domain.StoredFile, photo domain.PhotoMetadata) (_ *domain.Document, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.SupersedeDocument", attribute.String("document.id", documentID.String()))
+	defer func() { telemetry.End(span, err) }()
+
+	var next *domain.Document
+
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		current, err := s.documentRepo.GetByIDForUpdate(txCtx, documentID)
+		if err != nil {
+			return err
+		}
+		if !role.Can(current.Purpose.Action()) {
+			return domain.ErrRoleNotPermitted
+		}
+
+		stored, err := s.deduplicate(txCtx, file)
+		if err != nil {
+			return err
+		}
+		next, err = current.Supersede(uploadedBy, stored)
+		if err != nil {
+			return err
+		}
+
+		// The current version must give up its place before the next one is
+		// inserted; only one version per purpose may be current.
+		if err := s.documentRepo.Update(txCtx, current); err != nil {
+			return err
+		}
+		if err := s.documentRepo.Create(txCtx, next); err != nil {
+			return err
+		}
+
+		switch current.Purpose {
+		case domain.DocumentPictureProof:
+			loan, err := s.loanRepo.GetByID(txCtx, current.LoanID)
+			if err != nil {
+				return err
+			}
+			if err := s.photoPolicy.Check(photo, loan, time.Now()); err != nil {
+				return err
+			}
+			return s.approvalRepo.UpdatePictureProof(txCtx, current.LoanID, stored, photo)
+		case domain.DocumentSignedAgreement:
+			loan, err := s.loanRepo.GetByIDForUpdate(txCtx, current.LoanID)
+			if err != nil {
+				return err
+			}
+			loan.AgreementLetterKey = &stored.Key
+			if err := s.signAgreement(loan, stored); err != nil {
+				return err
+			}
+			loan.UpdatedAt = time.Now()
+			if err := s.loanRepo.Update(txCtx, loan); err != nil {
+				return err
+			}
+			return s.disbursementRepo.UpdateSignedAgreement(txCtx, current.LoanID, stored)
+		default:
+			return fmt.Errorf("unknown document purpose %q", current.Purpose)
+		}
+	})
+	s.finishUpload(ctx, file.Key, err == nil && next.File.Key == file.Key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "document superseded",
+		"document_id", documentID,
+		"new_document_id", next.ID,
+		"version", next.Version,
+		"uploaded_by", uploadedBy,
+	)
+
+	s.generatePreview(ctx, next.File)
+
+	return next, nil
+}
+
+// OpenDocument returns the document and a reader for its content after
+// checking the stored file against the recorded checksum, failing with
+// domain.ErrDocumentTampered if it does not match. The returned reader hashes
+// what it serves as well, and fails with domain.ErrDocumentTampered instead
+// of io.EOF if the file changed after the check. Documents registered before
+// checksums were recorded are returned unverified.
+func (s *LoanService) OpenDocument(ctx context.Context, documentID uuid.UUID) (_ *domain.Document, _ io.ReadCloser, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.OpenDocument", attribute.String("document.id", documentID.String()))
+	defer func() { telemetry.End(span, err) }()
+
+	document, err := s.documentRepo.GetByID(ctx, documentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if document.File.SHA256 != "" {
+		if err := s.verifyDocument(ctx, document); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	reader, err := s.storage.Open(ctx, document.File.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if document.File.SHA256 != "" {
+		reader = &verifyingReader{ReadCloser: reader, hash: sha256.New(), ctx: ctx, document: document, logger: s.logger}
+	}
+	return document, reader, nil
+}
+
+// verifyDocument reads the whole file before any of it is served, so that a
+// tampered document is rejected rather than cut off part way.
+func (s *LoanService) verifyDocument(ctx context.Context, document *domain.Document) error {
+	reader, err := s.storage.Open(ctx, document.File.Key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return fmt.Errorf("failed to read document: %w", err)
+	}
+	return checkDocumentSum(ctx, s.logger, document, hash)
+}
+
+// verifyingReader hashes the content as it is read and checks it against the
+// recorded checksum at the end, so the bytes actually served are verified
+// even if the file changes between verifyDocument and the read.
+type verifyingReader struct {
+	io.ReadCloser
+	hash     hash.Hash
+	ctx      context.Context
+	document *domain.Document
+	logger   *slog.Logger
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF {
+		if err := checkDocumentSum(r.ctx, r.logger, r.document, r.hash); err != nil {
+			return n, err
+		}
+	}
+	return n, err
+}
+
+func checkDocumentSum(ctx context.Context, logger *slog.Logger, document *domain.Document, hash hash.Hash) error {
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != document.File.SHA256 {
+		logger.ErrorContext(ctx, "document does not match its checksum",
+			"document_id", document.ID,
+			"key", document.File.Key,
+			"expected_sha256", document.File.SHA256,
+			"actual_sha256", sum,
+		)
+		return domain.ErrDocumentTampered
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/signing"
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+	"github.com/google/uuid"
+)
+
+// changingStorage returns the next of contents on each Open, standing in for
+// a file that is rewritten between reads, and records discarded uploads.
+type changingStorage struct {
+	storage.Storage
+	contents  [][]byte
+	discarded []string
+}
+
+func (s *changingStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if len(s.contents) == 0 {
+		return nil, errors.New("file not found")
+	}
+	content := s.contents[0]
+	if len(s.contents) > 1 {
+		s.contents = s.contents[1:]
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (s *changingStorage) Promote(ctx context.Context, key string) error {
+	return nil
+}
+
+func (s *changingStorage) Discard(ctx context.Context, key string) error {
+	s.discarded = append(s.discarded, key)
+	return nil
+}
+
+func TestOpenDocument(t *testing.T) {
+	original := []byte("signed agreement")
+	sum := sha256.Sum256(original)
+
+	tests := []struct {
+		name        string
+		contents    [][]byte
+		wantOpenErr error
+		wantReadErr error
+	}{
+		{"unchanged", [][]byte{original}, nil, nil},
+		{"tampered at rest", [][]byte{[]byte("forged agreement")}, domain.ErrDocumentTampered, nil},
+		{"changed after the check", [][]byte{original, []byte("forged agreement")}, nil, domain.ErrDocumentTampered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repos, _ := newTestService(t)
+			svc.storage = &changingStorage{contents: tt.contents}
+
+			document := domain.NewDocument(uuid.New(), domain.DocumentSignedAgreement, "officer-1", domain.StoredFile{
+				Key:         "agreement.pdf",
+				ContentType: "application/pdf",
+				Size:        int64(len(original)),
+				SHA256:      hex.EncodeToString(sum[:]),
+			})
+			if err := repos.Documents.Create(context.Background(), document); err != nil {
+				t.Fatal(err)
+			}
+
+			_, reader, err := svc.OpenDocument(context.Background(), document.ID)
+			if !errors.Is(err, tt.wantOpenErr) {
+				t.Fatalf("expected open error %v, got %v", tt.wantOpenErr, err)
+			}
+			if err != nil {
+				return
+			}
+			defer reader.Close()
+
+			if _, err := io.ReadAll(reader); !errors.Is(err, tt.wantReadErr) {
+				t.Errorf("expected read error %v, got %v", tt.wantReadErr, err)
+			}
+		})
+	}
+}
+
+func TestSupersedeSignedAgreement(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    domain.Role
+		wantErr error
+	}{
+		{"field validator", domain.RoleFieldValidator, domain.ErrRoleNotPermitted},
+		{"investor", domain.RoleInvestor, domain.ErrRoleNotPermitted},
+		{"no role", "", domain.ErrRoleNotPermitted},
+		{"field officer", domain.RoleFieldOfficer, nil},
+		{"admin", domain.RoleAdmin, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc, repos, _ := newTestService(t)
+			fake := &changingStorage{}
+			svc.storage = fake
+
+			svc.signer = newTestSigner(t)
+
+			signed := storedPDF("agreement.pdf", "signed agreement")
+			loan := domain.NewLoan("borrower-1", 1000000, domain.MustParseDecimal("0.15"), domain.MustParseDecimal("0.12"))
+			loan.AgreementLetterKey = &signed.Key
+			if err := svc.signAgreement(loan, signed); err != nil {
+				t.Fatal(err)
+			}
+			if err := repos.Loans.Create(ctx, loan); err != nil {
+				t.Fatal(err)
+			}
+			if err := repos.Disbursements.Create(ctx, domain.NewDisbursement(loan.ID, "officer-1", signed)); err != nil {
+				t.Fatal(err)
+			}
+			document := domain.NewDocument(loan.ID, domain.DocumentSignedAgreement, "officer-1", signed)
+			if err := repos.Documents.Create(ctx, document); err != nil {
+				t.Fatal(err)
+			}
+
+			forged := storedPDF("forged.pdf", "forged agreement")
+			_, err := svc.SupersedeDocument(ctx, document.ID, tt.role, "staff-1", forged, domain.PhotoMetadata{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			stored, err := repos.Loans.GetByID(ctx, loan.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			current, err := repos.Documents.GetByID(ctx, document.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantErr != nil {
+				if stored.AgreementSignature == nil || stored.AgreementSignature.SHA256 != signed.SHA256 {
+					t.Errorf("expected the signature over the original agreement to be kept, got %+v", stored.AgreementSignature)
+				}
+				if *stored.AgreementLetterKey != signed.Key {
+					t.Errorf("expected agreement letter %q, got %q", signed.Key, *stored.AgreementLetterKey)
+				}
+				if current.SupersededBy != nil {
+					t.Error("expected the original agreement to stay current")
+				}
+				if len(fake.discarded) != 1 || fake.discarded[0] != forged.Key {
+					t.Errorf("expected the rejected upload to be discarded, got %v", fake.discarded)
+				}
+				return
+			}
+
+			if stored.AgreementSignature == nil || stored.AgreementSignature.SHA256 != forged.SHA256 {
+				t.Errorf("expected the new agreement to be signed, got %+v", stored.AgreementSignature)
+			}
+			if current.SupersededBy == nil {
+				t.Error("expected the original agreement to be superseded")
+			}
+		})
+	}
+}
+
+func TestSignAgreement(t *testing.T) {
+	svc, _, _ := newTestService(t)
+	signed := storedPDF("agreement.pdf", "signed agreement")
+	legacy := domain.StoredFile{Key: "legacy.pdf"}
+
+	// Without a signer letters are left unsigned
+	loan := &domain.Loan{}
+	if err := svc.signAgreement(loan, legacy); err != nil || loan.AgreementSignature != nil {
+		t.Errorf("expected an unsigned letter without a signer, got %+v, %v", loan.AgreementSignature, err)
+	}
+
+	svc.signer = newTestSigner(t)
+	if err := svc.signAgreement(loan, signed); err != nil || loan.AgreementSignature == nil {
+		t.Fatalf("expected a signed letter, got %+v, %v", loan.AgreementSignature, err)
+	}
+	if err := svc.signAgreement(loan, legacy); err == nil {
+		t.Error("expected an error signing a letter without a checksum")
+	}
+	if loan.AgreementSignature != nil {
+		t.Error("expected the previous letter's signature to be cleared")
+	}
+}
+
+func newTestSigner(t *testing.T) *signing.Signer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signing.NewSigner(key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func storedPDF(key, content string) domain.StoredFile {
+	sum := sha256.Sum256([]byte(content))
+	return domain.StoredFile{
+		Key:         key,
+		ContentType: "application/pdf",
+		Size:        int64(len(content)),
+		SHA256:      hex.EncodeToString(sum[:]),
+	}
+}
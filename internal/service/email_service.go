@@ -18,7 +18,7 @@ func NewMockEmailService(logger *slog.Logger) *MockEmailService {
 }
 
 func (s *MockEmailService) SendAgreementEmail(ctx context.Context, investorID string, loanID string, agreementURL string) error {
-	s.logger.Info("sending agreement email",
+	s.logger.InfoContext(ctx, "sending agreement email",
 		"investor_id", investorID,
 		"loan_id", loanID,
 		"agreement_url", agreementURL,
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// BatchMode selects how CreateLoans treats a failing row.
+type BatchMode string
+
+const (
+	// BatchModeAtomic creates every loan in one transaction; any failure
+	// rolls back the whole batch.
+	BatchModeAtomic BatchMode = "atomic"
+	// BatchModePartial creates each loan independently.
+	BatchModePartial BatchMode = "partial"
+)
+
+// BatchResult is the outcome for the input at the same index. Exactly one of
+// Loan and Err is set, except for rows rolled back in atomic mode, where both
+// are nil.
+type BatchResult struct {
+	Loan *domain.Loan
+	Err  error
+}
+
+// CreateLoans creates a batch of loans. In atomic mode the returned error is
+// the one that aborted the transaction, and no row has a Loan.
+func (s *LoanService) CreateLoans(ctx context.Context, inputs []CreateLoanInput, mode BatchMode) (_ []BatchResult, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.CreateLoans",
+		attribute.String("batch.mode", string(mode)),
+		attribute.Int("batch.size", len(inputs)),
+	)
+	defer func() { telemetry.End(span, err) }()
+
+	results := make([]BatchResult, len(inputs))
+
+	if mode == BatchModePartial {
+		for i, input := range inputs {
+			results[i].Loan, results[i].Err = s.createLoan(ctx, input)
+			if results[i].Err == nil {
+				s.logLoanCreated(ctx, results[i].Loan)
+			}
+		}
+		return results, nil
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		for i, input := range inputs {
+			loan, err := s.createLoan(txCtx, input)
+			if err != nil {
+				results[i].Err = err
+				return err
+			}
+			results[i].Loan = loan
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range results {
+			results[i].Loan = nil
+		}
+		return results, err
+	}
+
+	s.logger.InfoContext(ctx, "loan batch created", "count", len(inputs))
+
+	return results, nil
+}
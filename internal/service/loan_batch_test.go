@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/repository/repotest"
+)
+
+// newTestService returns a service over in-memory repositories holding one
+// active product that accepts 12-month loans of 100000 to 5000000.
+func newTestService(t *testing.T) (*LoanService, *repotest.Repositories, *domain.LoanProduct) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repos := repotest.New()
+
+	product := domain.NewLoanProduct("MICRO-12", "Micro 12 months")
+	product.MinPrincipal, product.MaxPrincipal = 100000, 5000000
+	product.TenorMonths = []int{12}
+	product.MinRate, product.MaxRate = domain.MustParseDecimal("0.1"), domain.MustParseDecimal("0.2")
+	product.MinROI, product.MaxROI = domain.MustParseDecimal("0.05"), domain.MustParseDecimal("0.15")
+	if err := repos.Products.Create(context.Background(), product); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewLoanService(repos.Loans, repos.Products, repos.Approvals, repos.Investments,
+		repos.Disbursements, repos.Documents, repos.Previews, repos.TxManager, nil,
+		NewMockEmailService(logger), domain.PhotoPolicy{}, nil, logger)
+	return svc, repos, product
+}
+
+func TestCreateLoans(t *testing.T) {
+	svc, _, product := newTestService(t)
+	input := func(principal int64) CreateLoanInput {
+		return CreateLoanInput{
+			BorrowerID:      "borrower-1",
+			PrincipalAmount: principal,
+			Rate:            domain.MustParseDecimal("0.15"),
+			ROI:             domain.MustParseDecimal("0.12"),
+			ProductID:       product.ID,
+			TenorMonths:     12,
+		}
+	}
+	inputs := []CreateLoanInput{input(1000000), input(9000000), input(2000000)}
+
+	var logs bytes.Buffer
+	svc.logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+	t.Run("atomic", func(t *testing.T) {
+		logs.Reset()
+		results, err := svc.CreateLoans(context.Background(), inputs, BatchModeAtomic)
+		if !errors.Is(err, domain.ErrLoanTermsOutOfRange) {
+			t.Fatalf("expected ErrLoanTermsOutOfRange, got %v", err)
+		}
+		for i, result := range results {
+			if result.Loan != nil {
+				t.Errorf("expected row %d to be rolled back", i)
+			}
+		}
+		if !errors.Is(results[1].Err, domain.ErrLoanTermsOutOfRange) || results[0].Err != nil || results[2].Err != nil {
+			t.Errorf("expected only the failing row to carry the error, got %+v", results)
+		}
+		if strings.Contains(logs.String(), "created") {
+			t.Errorf("expected no loans to be logged as created, got %s", logs.String())
+		}
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		logs.Reset()
+		results, err := svc.CreateLoans(context.Background(), inputs, BatchModePartial)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if results[0].Loan == nil || results[2].Loan == nil {
+			t.Errorf("expected valid rows to be created, got %+v", results)
+		}
+		if results[1].Loan != nil || !errors.Is(results[1].Err, domain.ErrLoanTermsOutOfRange) {
+			t.Errorf("expected row 1 to fail, got %+v", results[1])
+		}
+		if n := strings.Count(logs.String(), `msg="loan created"`); n != 2 {
+			t.Errorf("expected 2 loans to be logged as created, got %d: %s", n, logs.String())
+		}
+	})
+}
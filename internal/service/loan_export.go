@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/internal/telemetry"
+)
+
+// ExportLoans streams every loan matching filter to fn. Unlike ListLoans no
+// default page size is applied.
+func (s *LoanService) ExportLoans(ctx context.Context, filter repository.LoanFilter, fn func(*domain.Loan) error) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.ExportLoans")
+	defer func() { telemetry.End(span, err) }()
+
+	return s.loanRepo.Stream(ctx, filter, fn)
+}
+
+// ExportInvestments streams every investment matching filter to fn.
+func (s *LoanService) ExportInvestments(ctx context.Context, filter repository.InvestmentFilter, fn func(*domain.Investment) error) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.ExportInvestments")
+	defer func() { telemetry.End(span, err) }()
+
+	return s.investmentRepo.Stream(ctx, filter, fn)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/internal/telemetry"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// LoanProductInput carries the fields of a loan product. Updates replace
+// every field.
+type LoanProductInput struct {
+	Code              string
+	Name              string
+	MinPrincipal      int64
+	MaxPrincipal      int64
+	TenorMonths       []int
+	MinRate           domain.Decimal
+	MaxRate           domain.Decimal
+	MinROI            domain.Decimal
+	MaxROI            domain.Decimal
+	RequiredDocuments []string
+	Active            bool
+}
+
+func (in LoanProductInput) apply(product *domain.LoanProduct) {
+	product.Code = in.Code
+	product.Name = in.Name
+	product.MinPrincipal = in.MinPrincipal
+	product.MaxPrincipal = in.MaxPrincipal
+	product.TenorMonths = in.TenorMonths
+	product.MinRate = in.MinRate
+	product.MaxRate = in.MaxRate
+	product.MinROI = in.MinROI
+	product.MaxROI = in.MaxROI
+	product.RequiredDocuments = in.RequiredDocuments
+	product.Active = in.Active
+	if product.RequiredDocuments == nil {
+		product.RequiredDocuments = []string{}
+	}
+}
+
+func (s *LoanService) CreateLoanProduct(ctx context.Context, input LoanProductInput) (_ *domain.LoanProduct, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.CreateLoanProduct", attribute.String("product.code", input.Code))
+	defer func() { telemetry.End(span, err) }()
+
+	product := domain.NewLoanProduct(input.Code, input.Name)
+	input.apply(product)
+	if err := product.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.Create(ctx, product); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "loan product created", "product_id", product.ID, "code", product.Code)
+
+	return product, nil
+}
+
+func (s *LoanService) GetLoanProduct(ctx context.Context, id uuid.UUID) (_ *domain.LoanProduct, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.GetLoanProduct", attribute.String("product.id", id.String()))
+	defer func() { telemetry.End(span, err) }()
+
+	return s.productRepo.GetByID(ctx, id)
+}
+
+func (s *LoanService) ListLoanProducts(ctx context.Context, filter repository.LoanProductFilter) (_ []*domain.LoanProduct, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.ListLoanProducts")
+	defer func() { telemetry.End(span, err) }()
+
+	return s.productRepo.List(ctx, filter)
+}
+
+// UpdateLoanProduct replaces a product's fields. Loans already proposed under
+// the product keep the terms they were created with.
+func (s *LoanService) UpdateLoanProduct(ctx context.Context, id uuid.UUID, input LoanProductInput) (_ *domain.LoanProduct, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.UpdateLoanProduct", attribute.String("product.id", id.String()))
+	defer func() { telemetry.End(span, err) }()
+
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	input.apply(product)
+	if err := product.Validate(); err != nil {
+		return nil, err
+	}
+	product.UpdatedAt = time.Now()
+
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "loan product updated", "product_id", product.ID, "code", product.Code)
+
+	return product, nil
+}
+
+// DeleteLoanProduct removes a product no loan references. Products in use
+// should be deactivated instead.
+func (s *LoanService) DeleteLoanProduct(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.DeleteLoanProduct", attribute.String("product.id", id.String()))
+	defer func() { telemetry.End(span, err) }()
+
+	if err := s.productRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "loan product deleted", "product_id", id)
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// snippetRadius is how many characters of context are kept either side of
+// the first match when a long field such as notes is highlighted.
+const snippetRadius = 60
+
+// SearchResult is a loan matching a search. Highlights holds, per matched
+// field, an HTML-escaped snippet with the matching text wrapped in <mark>.
+type SearchResult struct {
+	Loan       *domain.Loan
+	Rank       float64
+	Highlights map[string]string
+}
+
+func (s *LoanService) SearchLoans(ctx context.Context, query repository.SearchQuery) (_ []*SearchResult, _ int64, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.SearchLoans", attribute.Int("search.query_length", len(query.Text)))
+	defer func() { telemetry.End(span, err) }()
+
+	if query.Limit <= 0 {
+		query.Limit = 10
+	}
+	if query.Limit > 100 {
+		query.Limit = 100
+	}
+
+	hits, total, err := s.loanRepo.Search(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	terms := searchTerms(query.Text)
+	results := make([]*SearchResult, len(hits))
+	for i, hit := range hits {
+		fields := map[string]string{
+			"borrower_id":   hit.Loan.BorrowerID,
+			"borrower_name": hit.Loan.BorrowerName,
+			"notes":         hit.Loan.Notes,
+		}
+		if hit.FieldValidatorID != nil {
+			fields["field_validator_id"] = *hit.FieldValidatorID
+		}
+		if hit.FieldOfficerID != nil {
+			fields["field_officer_id"] = *hit.FieldOfficerID
+		}
+
+		highlights := make(map[string]string)
+		for name, value := range fields {
+			if snippet, ok := highlight(value, terms); ok {
+				highlights[name] = snippet
+			}
+		}
+
+		results[i] = &SearchResult{Loan: hit.Loan, Rank: hit.Rank, Highlights: highlights}
+	}
+
+	return results, total, nil
+}
+
+// searchTerms returns the lower-cased query as a whole plus each of its
+// words, so both phrase and per-word matches are highlighted.
+func searchTerms(text string) [][]rune {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return nil
+	}
+
+	terms := [][]rune{[]rune(text)}
+	for _, word := range strings.Fields(text) {
+		if word != text {
+			terms = append(terms, []rune(word))
+		}
+	}
+	return terms
+}
+
+// highlight wraps every case-insensitive occurrence of terms in value with
+// <mark>, escaping the rest. Long values are cut to a snippet around the
+// first match. It reports false if nothing matched.
+func highlight(value string, terms [][]rune) (string, bool) {
+	runes := []rune(value)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	type span struct{ start, end int }
+	var spans []span
+	for _, term := range terms {
+		for i := 0; i+len(term) <= len(lower); i++ {
+			if runesEqual(lower[i:i+len(term)], term) {
+				spans = append(spans, span{i, i + len(term)})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return "", false
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	merged := spans[:1]
+	for _, sp := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if sp.start <= last.end {
+			last.end = max(last.end, sp.end)
+			continue
+		}
+		merged = append(merged, sp)
+	}
+
+	from, to := 0, len(runes)
+	if to > 2*snippetRadius {
+		from = max(0, merged[0].start-snippetRadius)
+		to = min(len(runes), merged[0].end+snippetRadius)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, sp := range merged {
+		if sp.start >= to {
+			break
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:sp.start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[sp.start:min(sp.end, to)])))
+		b.WriteString("</mark>")
+		pos = min(sp.end, to)
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:to])))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String(), true
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
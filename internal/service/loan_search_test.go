@@ -0,0 +1,52 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		query    string
+		expected string
+		matched  bool
+	}{
+		{"case insensitive", "BRW-1234", "brw", "<mark>BRW</mark>-1234", true},
+		{"each word", "Siti Aminah", "aminah siti", "<mark>Siti</mark> <mark>Aminah</mark>", true},
+		{"overlapping terms", "Nurhayati", "nur nurha", "<mark>Nurha</mark>yati", true},
+		{"escapes html", "<b>Ani</b>", "ani", "&lt;b&gt;<mark>Ani</mark>&lt;/b&gt;", true},
+		{"no match", "Budi", "xyz", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := highlight(tt.value, searchTerms(tt.query))
+			if ok != tt.matched {
+				t.Fatalf("expected matched=%v, got %v", tt.matched, ok)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	value := strings.Repeat("a", 200) + " sawah " + strings.Repeat("b", 200)
+
+	got, ok := highlight(value, searchTerms("sawah"))
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("expected snippet to be cut on both sides, got %q", got)
+	}
+	if !strings.Contains(got, "<mark>sawah</mark>") {
+		t.Errorf("expected snippet to contain the match, got %q", got)
+	}
+	if n := len([]rune(got)); n > 2*snippetRadius+len("sawah")+len("<mark></mark>")+2 {
+		t.Errorf("expected snippet of at most %d runes, got %d", 2*snippetRadius+len("sawah")+len("<mark></mark>")+2, n)
+	}
+}
@@ -2,69 +2,162 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync/atomic"
+	"time"
 
 	"github.com/agunghallmanmaliki/amartha/internal/domain"
 	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/internal/signing"
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+	"github.com/agunghallmanmaliki/amartha/internal/telemetry"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// agreementEmailURLTTL is how long agreement links in investor emails stay
+// valid; 7 days is the most S3 presigning allows.
+const agreementEmailURLTTL = 7 * 24 * time.Hour
+
 type LoanService struct {
 	loanRepo         repository.LoanRepository
+	productRepo      repository.LoanProductRepository
 	approvalRepo     repository.ApprovalRepository
 	investmentRepo   repository.InvestmentRepository
 	disbursementRepo repository.DisbursementRepository
+	documentRepo     repository.DocumentRepository
+	previewRepo      repository.PreviewRepository
 	txManager        repository.TransactionManager
+	storage          storage.Storage
 	emailService     EmailService
+	photoPolicy      domain.PhotoPolicy
+	signer           *signing.Signer
 	logger           *slog.Logger
+
+	pendingNotifications atomic.Int64
 }
 
 func NewLoanService(
 	loanRepo repository.LoanRepository,
+	productRepo repository.LoanProductRepository,
 	approvalRepo repository.ApprovalRepository,
 	investmentRepo repository.InvestmentRepository,
 	disbursementRepo repository.DisbursementRepository,
+	documentRepo repository.DocumentRepository,
+	previewRepo repository.PreviewRepository,
 	txManager repository.TransactionManager,
+	storage storage.Storage,
 	emailService EmailService,
+	photoPolicy domain.PhotoPolicy,
+	signer *signing.Signer,
 	logger *slog.Logger,
 ) *LoanService {
 	return &LoanService{
 		loanRepo:         loanRepo,
+		productRepo:      productRepo,
 		approvalRepo:     approvalRepo,
 		investmentRepo:   investmentRepo,
 		disbursementRepo: disbursementRepo,
+		documentRepo:     documentRepo,
+		previewRepo:      previewRepo,
 		txManager:        txManager,
+		storage:          storage,
 		emailService:     emailService,
+		photoPolicy:      photoPolicy,
+		signer:           signer,
 		logger:           logger,
 	}
 }
 
-func (s *LoanService) CreateLoan(ctx context.Context, borrowerID string, principalAmount int64, rate, roi float64) (*domain.Loan, error) {
-	if principalAmount <= 0 {
+// CreateLoanInput carries the fields needed to propose a new loan.
+type CreateLoanInput struct {
+	BorrowerID       string
+	BorrowerName     string
+	Notes            string
+	PrincipalAmount  int64
+	Rate             domain.Decimal
+	ROI              domain.Decimal
+	ProductID        uuid.UUID
+	TenorMonths      int
+	BorrowerLocation *domain.GeoPoint
+}
+
+func (s *LoanService) CreateLoan(ctx context.Context, input CreateLoanInput) (_ *domain.Loan, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.CreateLoan",
+		attribute.String("borrower.id", input.BorrowerID),
+		attribute.Int64("loan.principal_amount", input.PrincipalAmount),
+		attribute.String("loan.product_id", input.ProductID.String()),
+	)
+	defer func() { telemetry.End(span, err) }()
+
+	loan, err := s.createLoan(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	s.logLoanCreated(ctx, loan)
+	return loan, nil
+}
+
+// createLoan validates input against its product and stores the loan. It
+// does not log, as it may run inside a transaction that is later rolled back;
+// callers log once the loan is committed.
+func (s *LoanService) createLoan(ctx context.Context, input CreateLoanInput) (*domain.Loan, error) {
+	if input.PrincipalAmount <= 0 {
 		return nil, domain.ErrInvalidAmount
 	}
 
-	loan := domain.NewLoan(borrowerID, principalAmount, rate, roi)
+	product, err := s.productRepo.GetByID(ctx, input.ProductID)
+	if err != nil && !errors.Is(err, domain.ErrLoanProductNotFound) {
+		return nil, err
+	}
+	if product == nil || !product.Active {
+		return nil, &domain.ViolationError{
+			Err: domain.ErrLoanProductUnavailable,
+			Violations: []domain.Violation{{
+				Field: "product_id", Rule: "active",
+				Message: "product_id must reference an active loan product",
+			}},
+		}
+	}
+	if err := product.CheckTerms(input.PrincipalAmount, input.TenorMonths, input.Rate, input.ROI); err != nil {
+		return nil, err
+	}
+
+	loan := domain.NewLoan(input.BorrowerID, input.PrincipalAmount, input.Rate, input.ROI)
+	loan.BorrowerName = input.BorrowerName
+	loan.Notes = input.Notes
+	loan.ProductID = &product.ID
+	loan.TenorMonths = input.TenorMonths
+	loan.BorrowerLocation = input.BorrowerLocation
 
 	if err := s.loanRepo.Create(ctx, loan); err != nil {
 		return nil, fmt.Errorf("failed to create loan: %w", err)
 	}
 
-	s.logger.Info("loan created",
+	return loan, nil
+}
+
+func (s *LoanService) logLoanCreated(ctx context.Context, loan *domain.Loan) {
+	s.logger.InfoContext(ctx, "loan created",
 		"loan_id", loan.ID,
-		"borrower_id", borrowerID,
-		"principal", principalAmount,
+		"borrower_id", loan.BorrowerID,
+		"principal", loan.PrincipalAmount,
 	)
-
-	return loan, nil
 }
 
-func (s *LoanService) GetLoan(ctx context.Context, id uuid.UUID) (*domain.Loan, error) {
+func (s *LoanService) GetLoan(ctx context.Context, id uuid.UUID) (_ *domain.Loan, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.GetLoan", attribute.String("loan.id", id.String()))
+	defer func() { telemetry.End(span, err) }()
+
 	return s.loanRepo.GetByID(ctx, id)
 }
 
-func (s *LoanService) ListLoans(ctx context.Context, filter repository.LoanFilter) ([]*domain.Loan, int64, error) {
+func (s *LoanService) ListLoans(ctx context.Context, filter repository.LoanFilter) (_ []*domain.Loan, _ int64, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.ListLoans")
+	defer func() { telemetry.End(span, err) }()
+
 	if filter.Limit <= 0 {
 		filter.Limit = 10
 	}
@@ -74,10 +167,16 @@ func (s *LoanService) ListLoans(ctx context.Context, filter repository.LoanFilte
 	return s.loanRepo.List(ctx, filter)
 }
 
-func (s *LoanService) ApproveLoan(ctx context.Context, loanID uuid.UUID, fieldValidatorID, pictureProofURL string) (*domain.Loan, error) {
+// ApproveLoan approves a proposed loan once the picture proof's metadata
+// passes the photo policy.
+func (s *LoanService) ApproveLoan(ctx context.Context, loanID uuid.UUID, fieldValidatorID string, pictureProof domain.StoredFile, photo domain.PhotoMetadata) (_ *domain.Loan, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.ApproveLoan", attribute.String("loan.id", loanID.String()))
+	defer func() { telemetry.End(span, err) }()
+
 	var loan *domain.Loan
+	var stored domain.StoredFile
 
-	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
 		var err error
 		loan, err = s.loanRepo.GetByIDForUpdate(txCtx, loanID)
 		if err != nil {
@@ -88,6 +187,10 @@ func (s *LoanService) ApproveLoan(ctx context.Context, loanID uuid.UUID, fieldVa
 			return domain.ErrLoanAlreadyApproved
 		}
 
+		if err := s.photoPolicy.Check(photo, loan, time.Now()); err != nil {
+			return err
+		}
+
 		if err := loan.TransitionTo(domain.LoanStateApproved); err != nil {
 			return err
 		}
@@ -96,27 +199,42 @@ func (s *LoanService) ApproveLoan(ctx context.Context, loanID uuid.UUID, fieldVa
 			return err
 		}
 
-		approval := domain.NewApproval(loanID, fieldValidatorID, pictureProofURL)
+		document, err := s.newDocument(txCtx, loanID, domain.DocumentPictureProof, fieldValidatorID, pictureProof)
+		if err != nil {
+			return err
+		}
+		stored = document.File
+
+		approval := domain.NewApproval(loanID, fieldValidatorID, stored, photo)
 		if err := s.approvalRepo.Create(txCtx, approval); err != nil {
 			return err
 		}
 
-		return nil
+		return s.documentRepo.Create(txCtx, document)
 	})
+	s.finishUpload(ctx, pictureProof.Key, err == nil && stored.Key == pictureProof.Key)
 
 	if err != nil {
 		return nil, err
 	}
 
-	s.logger.Info("loan approved",
+	s.logger.InfoContext(ctx, "loan approved",
 		"loan_id", loanID,
 		"field_validator_id", fieldValidatorID,
 	)
 
+	s.generatePreview(ctx, stored)
+
 	return loan, nil
 }
 
-func (s *LoanService) AddInvestment(ctx context.Context, loanID uuid.UUID, investorID string, amount int64) (*domain.Loan, *domain.Investment, error) {
+func (s *LoanService) AddInvestment(ctx context.Context, loanID uuid.UUID, investorID string, amount int64) (_ *domain.Loan, _ *domain.Investment, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.AddInvestment",
+		attribute.String("loan.id", loanID.String()),
+		attribute.Int64("investment.amount", amount),
+	)
+	defer func() { telemetry.End(span, err) }()
+
 	if amount <= 0 {
 		return nil, nil, domain.ErrInvalidAmount
 	}
@@ -124,7 +242,7 @@ func (s *LoanService) AddInvestment(ctx context.Context, loanID uuid.UUID, inves
 	var loan *domain.Loan
 	var investment *domain.Investment
 
-	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
 		var err error
 		loan, err = s.loanRepo.GetByIDForUpdate(txCtx, loanID)
 		if err != nil {
@@ -158,7 +276,7 @@ func (s *LoanService) AddInvestment(ctx context.Context, loanID uuid.UUID, inves
 		return nil, nil, err
 	}
 
-	s.logger.Info("investment added",
+	s.logger.InfoContext(ctx, "investment added",
 		"loan_id", loanID,
 		"investor_id", investorID,
 		"amount", amount,
@@ -166,17 +284,45 @@ func (s *LoanService) AddInvestment(ctx context.Context, loanID uuid.UUID, inves
 	)
 
 	// Send emails to all investors if fully invested (async)
-	if loan.IsFullyInvested() && loan.AgreementLetterURL != nil {
-		go s.notifyInvestors(context.Background(), loanID, *loan.AgreementLetterURL)
+	if loan.IsFullyInvested() && loan.AgreementLetterKey != nil {
+		s.notifyInvestorsAsync(ctx, loanID, *loan.AgreementLetterKey)
 	}
 
 	return loan, investment, nil
 }
 
-func (s *LoanService) notifyInvestors(ctx context.Context, loanID uuid.UUID, agreementURL string) {
+// notifyInvestorsAsync sends investor emails in the background, tracking how
+// many notification runs are still in flight.
+func (s *LoanService) notifyInvestorsAsync(ctx context.Context, loanID uuid.UUID, agreementKey string) {
+	s.pendingNotifications.Add(1)
+	go func() {
+		defer s.pendingNotifications.Add(-1)
+		s.notifyInvestors(context.WithoutCancel(ctx), loanID, agreementKey)
+	}()
+}
+
+// PendingNotifications returns the number of investor notification runs that
+// have not finished yet.
+func (s *LoanService) PendingNotifications() int64 {
+	return s.pendingNotifications.Load()
+}
+
+func (s *LoanService) notifyInvestors(ctx context.Context, loanID uuid.UUID, agreementKey string) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.notifyInvestors", attribute.String("loan.id", loanID.String()))
+	defer span.End()
+
+	agreementURL, err := s.storage.SignedURL(ctx, agreementKey, agreementEmailURLTTL)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to sign agreement url for notification",
+			"loan_id", loanID,
+			"error", err,
+		)
+		return
+	}
+
 	investors, err := s.investmentRepo.GetInvestorsByLoanID(ctx, loanID)
 	if err != nil {
-		s.logger.Error("failed to get investors for notification",
+		s.logger.ErrorContext(ctx, "failed to get investors for notification",
 			"loan_id", loanID,
 			"error", err,
 		)
@@ -185,7 +331,7 @@ func (s *LoanService) notifyInvestors(ctx context.Context, loanID uuid.UUID, agr
 
 	for _, investorID := range investors {
 		if err := s.emailService.SendAgreementEmail(ctx, investorID, loanID.String(), agreementURL); err != nil {
-			s.logger.Error("failed to send email to investor",
+			s.logger.ErrorContext(ctx, "failed to send email to investor",
 				"investor_id", investorID,
 				"loan_id", loanID,
 				"error", err,
@@ -194,9 +340,12 @@ func (s *LoanService) notifyInvestors(ctx context.Context, loanID uuid.UUID, agr
 	}
 }
 
-func (s *LoanService) ListInvestments(ctx context.Context, loanID uuid.UUID) ([]*domain.Investment, error) {
+func (s *LoanService) ListInvestments(ctx context.Context, loanID uuid.UUID) (_ []*domain.Investment, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.ListInvestments", attribute.String("loan.id", loanID.String()))
+	defer func() { telemetry.End(span, err) }()
+
 	// Verify loan exists
-	_, err := s.loanRepo.GetByID(ctx, loanID)
+	_, err = s.loanRepo.GetByID(ctx, loanID)
 	if err != nil {
 		return nil, err
 	}
@@ -204,10 +353,14 @@ func (s *LoanService) ListInvestments(ctx context.Context, loanID uuid.UUID) ([]
 	return s.investmentRepo.ListByLoanID(ctx, loanID)
 }
 
-func (s *LoanService) DisburseLoan(ctx context.Context, loanID uuid.UUID, fieldOfficerID, signedAgreementURL string) (*domain.Loan, error) {
+func (s *LoanService) DisburseLoan(ctx context.Context, loanID uuid.UUID, fieldOfficerID string, signedAgreement domain.StoredFile) (_ *domain.Loan, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.DisburseLoan", attribute.String("loan.id", loanID.String()))
+	defer func() { telemetry.End(span, err) }()
+
 	var loan *domain.Loan
+	var stored domain.StoredFile
 
-	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
 		var err error
 		loan, err = s.loanRepo.GetByIDForUpdate(txCtx, loanID)
 		if err != nil {
@@ -225,39 +378,73 @@ func (s *LoanService) DisburseLoan(ctx context.Context, loanID uuid.UUID, fieldO
 			return err
 		}
 
-		loan.AgreementLetterURL = &signedAgreementURL
+		document, err := s.newDocument(txCtx, loanID, domain.DocumentSignedAgreement, fieldOfficerID, signedAgreement)
+		if err != nil {
+			return err
+		}
+		stored = document.File
+
+		loan.AgreementLetterKey = &stored.Key
+		if err := s.signAgreement(loan, stored); err != nil {
+			return err
+		}
 
 		if err := s.loanRepo.Update(txCtx, loan); err != nil {
 			return err
 		}
 
-		disbursement := domain.NewDisbursement(loanID, fieldOfficerID, signedAgreementURL)
+		disbursement := domain.NewDisbursement(loanID, fieldOfficerID, stored)
 		if err := s.disbursementRepo.Create(txCtx, disbursement); err != nil {
 			return err
 		}
 
-		return nil
+		return s.documentRepo.Create(txCtx, document)
 	})
+	s.finishUpload(ctx, signedAgreement.Key, err == nil && stored.Key == signedAgreement.Key)
 
 	if err != nil {
 		return nil, err
 	}
 
-	s.logger.Info("loan disbursed",
+	s.logger.InfoContext(ctx, "loan disbursed",
 		"loan_id", loanID,
 		"field_officer_id", fieldOfficerID,
 	)
 
+	s.generatePreview(ctx, stored)
+
 	// Notify all investors about disbursement with the signed agreement
-	go s.notifyInvestors(context.Background(), loanID, signedAgreementURL)
+	s.notifyInvestorsAsync(ctx, loanID, stored.Key)
 
 	return loan, nil
 }
 
-func (s *LoanService) GetApproval(ctx context.Context, loanID uuid.UUID) (*domain.Approval, error) {
+// finishUpload promotes a staged upload once the rows referencing it are
+// committed, or discards it if they were not or they reference an identical
+// earlier upload instead. Failures are only logged; the storage GC promotes
+// referenced files and removes stale staged ones.
+func (s *LoanService) finishUpload(ctx context.Context, key string, keep bool) {
+	if !keep {
+		if err := s.storage.Discard(ctx, key); err != nil {
+			s.logger.WarnContext(ctx, "failed to discard staged upload", "key", key, "error", err)
+		}
+		return
+	}
+	if err := s.storage.Promote(ctx, key); err != nil {
+		s.logger.ErrorContext(ctx, "failed to promote upload", "key", key, "error", err)
+	}
+}
+
+func (s *LoanService) GetApproval(ctx context.Context, loanID uuid.UUID) (_ *domain.Approval, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.GetApproval", attribute.String("loan.id", loanID.String()))
+	defer func() { telemetry.End(span, err) }()
+
 	return s.approvalRepo.GetByLoanID(ctx, loanID)
 }
 
-func (s *LoanService) GetDisbursement(ctx context.Context, loanID uuid.UUID) (*domain.Disbursement, error) {
+func (s *LoanService) GetDisbursement(ctx context.Context, loanID uuid.UUID) (_ *domain.Disbursement, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.GetDisbursement", attribute.String("loan.id", loanID.String()))
+	defer func() { telemetry.End(span, err) }()
+
 	return s.disbursementRepo.GetByLoanID(ctx, loanID)
 }
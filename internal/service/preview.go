@@ -0,0 +1,80 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/preview"
+	"github.com/agunghallmanmaliki/amartha/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetPreview returns the preview of the file stored under sourceKey, or
+// domain.ErrPreviewNotFound if it has none.
+func (s *LoanService) GetPreview(ctx context.Context, sourceKey string) (_ *domain.Preview, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.GetPreview", attribute.String("storage.key", sourceKey))
+	defer func() { telemetry.End(span, err) }()
+
+	return s.previewRepo.GetBySourceKey(ctx, sourceKey)
+}
+
+// generatePreview renders and stores a preview of a committed file unless it
+// is of a type without previews or already has one. Previews are a
+// convenience, so failures are logged rather than failing the upload.
+func (s *LoanService) generatePreview(ctx context.Context, file domain.StoredFile) {
+	if !preview.Supports(file.ContentType) {
+		return
+	}
+
+	ctx, span := telemetry.StartSpan(ctx, "LoanService.generatePreview", attribute.String("storage.key", file.Key))
+	err := s.storePreview(ctx, file)
+	telemetry.End(span, err)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to generate preview", "key", file.Key, "error", err)
+	}
+}
+
+func (s *LoanService) storePreview(ctx context.Context, file domain.StoredFile) error {
+	if _, err := s.previewRepo.GetBySourceKey(ctx, file.Key); !errors.Is(err, domain.ErrPreviewNotFound) {
+		return err
+	}
+
+	reader, err := s.storage.Open(ctx, file.Key)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(io.LimitReader(reader, preview.MaxSourceSize+1))
+	reader.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > preview.MaxSourceSize {
+		return fmt.Errorf("file is larger than %d bytes", preview.MaxSourceSize)
+	}
+
+	rendering, err := preview.Render(file.ContentType, data)
+	if err != nil {
+		return err
+	}
+
+	key, err := s.storage.Save(ctx, "preview"+rendering.Extension, bytes.NewReader(rendering.Data))
+	if err != nil {
+		return err
+	}
+	p := &domain.Preview{
+		SourceKey: file.Key,
+		File:      domain.StoredFile{Key: key, ContentType: rendering.ContentType, Size: int64(len(rendering.Data))},
+		Width:     rendering.Width,
+		Height:    rendering.Height,
+		PageCount: rendering.PageCount,
+		CreatedAt: time.Now(),
+	}
+	err = s.previewRepo.Create(ctx, p)
+	s.finishUpload(ctx, key, err == nil)
+	return err
+}
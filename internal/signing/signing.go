@@ -0,0 +1,206 @@
+// Package signing signs the agreement letters we issue, so that investors can
+// check that a letter came from us and has not been altered since.
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+)
+
+var (
+	// ErrUnknownSigner is returned when a signature was made with a key
+	// other than the signer's, e.g. before the key was replaced.
+	ErrUnknownSigner = errors.New("signature was made with another key")
+	// ErrBadSignature is returned when a signature does not match the
+	// document it is checked against.
+	ErrBadSignature = errors.New("signature does not match the document")
+)
+
+// Signer makes detached signatures over SHA-256 digests with an ECDSA or RSA
+// (PKCS #1 v1.5) key. They are ordinary signatures of the document itself,
+// so anyone holding the certificate can check them, e.g. with
+// openssl dgst -sha256 -verify.
+type Signer struct {
+	key         crypto.Signer
+	certificate *x509.Certificate
+	fingerprint string
+}
+
+// NewSigner signs with key. certificate, which may be nil, must be for key;
+// it is what the signer's fingerprint identifies when given.
+func NewSigner(key crypto.Signer, certificate *x509.Certificate) (*Signer, error) {
+	switch key.Public().(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %T; use an ECDSA or RSA key", key)
+	}
+
+	var der []byte
+	if certificate != nil {
+		public, ok := certificate.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+		if !ok || !public.Equal(key.Public()) {
+			return nil, errors.New("signing certificate is not for the signing key")
+		}
+		der = certificate.Raw
+	} else {
+		var err error
+		der, err = x509.MarshalPKIXPublicKey(key.Public())
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode public key: %w", err)
+		}
+	}
+	sum := sha256.Sum256(der)
+	return &Signer{
+		key:         key,
+		certificate: certificate,
+		fingerprint: "sha256:" + hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// LoadSigner reads a PEM private key, in PKCS #8, SEC 1 or PKCS #1 form,
+// and optionally the PEM certificate for it; certFile may be empty.
+func LoadSigner(keyFile, certFile string) (*Signer, error) {
+	key, err := loadKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	var certificate *x509.Certificate
+	if certFile != "" {
+		certificate, err = loadCertificate(certFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return NewSigner(key, certificate)
+}
+
+func loadKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	var key any
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("signing key %s holds a %q block, not a private key", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key type %T", key)
+	}
+	return signer, nil
+}
+
+func loadCertificate(path string) (*x509.Certificate, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing certificate: %w", err)
+	}
+	if block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("signing certificate %s holds a %q block, not a certificate", path, block.Type)
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing certificate: %w", err)
+	}
+	return certificate, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not PEM encoded", path)
+	}
+	return block, nil
+}
+
+// Fingerprint identifies the signer: the SHA-256 of its certificate, or of
+// its public key if it has none.
+func (s *Signer) Fingerprint() string {
+	return s.fingerprint
+}
+
+// PEM returns the signer's certificate, or its public key if it has none,
+// for others to check signatures with.
+func (s *Signer) PEM() []byte {
+	if s.certificate != nil {
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.certificate.Raw})
+	}
+	der, _ := x509.MarshalPKIXPublicKey(s.key.Public())
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+// Sign signs the document whose SHA-256 digest is sha256Hex.
+func (s *Signer) Sign(sha256Hex string) (*domain.AgreementSignature, error) {
+	digest, err := decodeDigest(sha256Hex)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := s.key.Sign(rand.Reader, digest, crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign agreement letter: %w", err)
+	}
+	return &domain.AgreementSignature{
+		SHA256:            sha256Hex,
+		Signature:         signature,
+		SignerFingerprint: s.fingerprint,
+		SignedAt:          time.Now(),
+	}, nil
+}
+
+// Verify checks that signature is ours over the document whose SHA-256
+// digest is sha256Hex, returning ErrUnknownSigner or ErrBadSignature if not.
+func (s *Signer) Verify(sha256Hex string, signature *domain.AgreementSignature) error {
+	if signature.SignerFingerprint != s.fingerprint {
+		return ErrUnknownSigner
+	}
+	digest, err := decodeDigest(sha256Hex)
+	if err != nil {
+		return err
+	}
+
+	valid := false
+	switch public := s.key.Public().(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(public, digest, signature.Signature)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(public, crypto.SHA256, digest, signature.Signature) == nil
+	}
+	if !valid {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+func decodeDigest(sha256Hex string) ([]byte, error) {
+	digest, err := hex.DecodeString(sha256Hex)
+	if err != nil || len(digest) != sha256.Size {
+		return nil, fmt.Errorf("invalid SHA-256 digest %q", sha256Hex)
+	}
+	return digest, nil
+}
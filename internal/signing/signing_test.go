@@ -0,0 +1,163 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// selfSigned returns a certificate for key signed by itself.
+func selfSigned(t *testing.T, key crypto.Signer) *x509.Certificate {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Agreement letters"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return certificate
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestSignerSignVerify(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		key       crypto.Signer
+		algorithm x509.SignatureAlgorithm
+	}{
+		{"ecdsa", ecKey, x509.ECDSAWithSHA256},
+		{"rsa", rsaKey, x509.SHA256WithRSA},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certificate := selfSigned(t, tt.key)
+			signer, err := NewSigner(tt.key, certificate)
+			if err != nil {
+				t.Fatalf("failed to create signer: %v", err)
+			}
+
+			letter := []byte("%PDF-1.7 agreement letter")
+			signature, err := signer.Sign(digest(letter))
+			if err != nil {
+				t.Fatalf("failed to sign: %v", err)
+			}
+			if signature.SHA256 != digest(letter) || signature.SignerFingerprint != signer.Fingerprint() {
+				t.Errorf("expected signature over %s by %s, got %+v", digest(letter), signer.Fingerprint(), signature)
+			}
+
+			if err := signer.Verify(digest(letter), signature); err != nil {
+				t.Errorf("expected signature to verify, got %v", err)
+			}
+			// The signature is a plain detached signature of the letter.
+			if err := certificate.CheckSignature(tt.algorithm, letter, signature.Signature); err != nil {
+				t.Errorf("expected certificate to verify the letter, got %v", err)
+			}
+
+			altered := []byte("%PDF-1.7 agreement letter, altered")
+			if err := signer.Verify(digest(altered), signature); !errors.Is(err, ErrBadSignature) {
+				t.Errorf("expected ErrBadSignature for an altered letter, got %v", err)
+			}
+
+			other, err := NewSigner(tt.key, nil)
+			if err != nil {
+				t.Fatalf("failed to create signer: %v", err)
+			}
+			if err := other.Verify(digest(letter), signature); !errors.Is(err, ErrUnknownSigner) {
+				t.Errorf("expected ErrUnknownSigner for another fingerprint, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadSigner(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(key)
+	sec1, _ := x509.MarshalECPrivateKey(key)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	keyFile := write("key.pem", "PRIVATE KEY", pkcs8)
+	ecKeyFile := write("ec.pem", "EC PRIVATE KEY", sec1)
+	certFile := write("cert.pem", "CERTIFICATE", selfSigned(t, key).Raw)
+	otherCertFile := write("other.pem", "CERTIFICATE", selfSigned(t, otherKey).Raw)
+
+	tests := []struct {
+		name     string
+		keyFile  string
+		certFile string
+		wantErr  bool
+	}{
+		{"pkcs8 key", keyFile, "", false},
+		{"sec1 key with certificate", ecKeyFile, certFile, false},
+		{"certificate for another key", keyFile, otherCertFile, true},
+		{"certificate as key", certFile, "", true},
+		{"missing key", filepath.Join(dir, "missing.pem"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := LoadSigner(tt.keyFile, tt.certFile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			block, _ := pem.Decode(signer.PEM())
+			want := "PUBLIC KEY"
+			if tt.certFile != "" {
+				want = "CERTIFICATE"
+			}
+			if block == nil || block.Type != want {
+				t.Errorf("expected PEM %s", want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Package driver builds the storage backend selected by configuration.
+package driver
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/agunghallmanmaliki/amartha/internal/config"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+	"github.com/agunghallmanmaliki/amartha/internal/storage/encrypted"
+	"github.com/agunghallmanmaliki/amartha/internal/storage/local"
+	"github.com/agunghallmanmaliki/amartha/internal/storage/s3"
+)
+
+// New returns the configured storage backend and, when the application
+// serves downloads itself, the handler that serves its signed URLs under
+// /uploads/. That is the case for local storage and for encrypted storage,
+// whose data keys are kept in dataKeys.
+func New(cfg *config.Config, dataKeys repository.DataKeyRepository, logger *slog.Logger) (storage.Backend, http.Handler, error) {
+	keyring, err := Keyring(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var signingKey []byte
+	if cfg.StorageDriver == "local" || keyring != nil {
+		signingKey = []byte(cfg.StorageSigningKey)
+		if len(signingKey) == 0 {
+			logger.Warn("STORAGE_SIGNING_KEY is not set; download URLs will only work on this instance until it restarts")
+			signingKey = make([]byte, 32)
+			if _, err := rand.Read(signingKey); err != nil {
+				return nil, nil, fmt.Errorf("failed to generate signing key: %w", err)
+			}
+		}
+	}
+
+	backend, uploads, err := newBackend(cfg, signingKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if keyring == nil {
+		return backend, uploads, nil
+	}
+
+	signer, err := storage.NewURLSigner(cfg.ServerHost, signingKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	store := encrypted.NewStorage(backend, keyring, dataKeys, signer)
+	return store, store.Handler(), nil
+}
+
+// Keyring returns the master keys files are encrypted with, or nil if
+// encryption at rest is disabled.
+func Keyring(cfg *config.Config) (*encrypted.Keyring, error) {
+	switch {
+	case cfg.StorageEncryptionKey != "" && cfg.StorageKeyringFile != "":
+		return nil, errors.New("set only one of STORAGE_ENCRYPTION_KEY and STORAGE_KEYRING_FILE")
+	case cfg.StorageEncryptionKey != "":
+		return encrypted.KeyringFromKey(cfg.StorageEncryptionKey)
+	case cfg.StorageKeyringFile != "":
+		return encrypted.LoadKeyring(cfg.StorageKeyringFile)
+	default:
+		return nil, nil
+	}
+}
+
+func newBackend(cfg *config.Config, signingKey []byte) (storage.Backend, http.Handler, error) {
+	switch cfg.StorageDriver {
+	case "local":
+		store, err := local.NewLocalStorage(cfg.StoragePath, cfg.ServerHost, signingKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store.Handler(), nil
+	case "s3":
+		store, err := s3.NewS3Storage(s3.Config{
+			Endpoint:        cfg.S3Endpoint,
+			UseSSL:          cfg.S3UseSSL,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			Prefix:          cfg.S3Prefix,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			SessionToken:    cfg.S3SessionToken,
+			PathStyle:       cfg.S3ForcePathStyle,
+			PartSize:        cfg.S3PartSize,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
@@ -0,0 +1,189 @@
+// Package encrypted keeps stored files encrypted at rest with envelope
+// encryption: every file is encrypted with its own data key, which is kept
+// in the database wrapped by a master key from a Keyring.
+package encrypted
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+)
+
+// Storage encrypts files before they reach the backend and decrypts them
+// on Open. Since the backend only holds ciphertext, downloads are served by
+// Handler rather than by the backend.
+type Storage struct {
+	storage.Backend
+	keyring  *Keyring
+	dataKeys repository.DataKeyRepository
+	signer   *storage.URLSigner
+}
+
+// NewStorage encrypts the files kept in backend. Files the backend held
+// before encryption was enabled have no data key and are read as they are.
+func NewStorage(backend storage.Backend, keyring *Keyring, dataKeys repository.DataKeyRepository, signer *storage.URLSigner) *Storage {
+	return &Storage{
+		Backend:  backend,
+		keyring:  keyring,
+		dataKeys: dataKeys,
+		signer:   signer,
+	}
+}
+
+// Save encrypts the file with a new data key and records the key once the
+// backend has assigned the file's storage key.
+func (s *Storage) Save(ctx context.Context, name string, reader io.Reader) (string, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	encrypting, err := newEncryptReader(reader, aead)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := s.Backend.Save(ctx, name, encrypting)
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := s.keyring.wrap(key, dataKey)
+	if err == nil {
+		err = s.dataKeys.Create(ctx, wrapped)
+	}
+	if err != nil {
+		s.Backend.Discard(ctx, key)
+		return "", fmt.Errorf("failed to store data key: %w", err)
+	}
+	return key, nil
+}
+
+func (s *Storage) Discard(ctx context.Context, key string) error {
+	if err := s.Backend.Discard(ctx, key); err != nil {
+		return err
+	}
+	return s.dataKeys.Delete(ctx, key)
+}
+
+func (s *Storage) Delete(ctx context.Context, area storage.Area, key string) error {
+	if err := s.Backend.Delete(ctx, area, key); err != nil {
+		return err
+	}
+	return s.dataKeys.Delete(ctx, key)
+}
+
+// Open decrypts the committed file stored under key. A file without a data
+// key is returned as stored, unless it looks encrypted.
+func (s *Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := s.Backend.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := s.dataKeys.GetByStorageKey(ctx, key)
+	if errors.Is(err, domain.ErrDataKeyNotFound) {
+		return plaintext(file, key)
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	plain, err := s.keyring.unwrap(dataKey)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	aead, err := newAEAD(plain)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	reader, err := newDecryptReader(file, aead)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", key, err)
+	}
+	return reader, nil
+}
+
+// plaintext returns a file stored before encryption was enabled. A file
+// that starts like an encrypted one has lost its data key, and reading it
+// would only return ciphertext.
+func plaintext(file io.ReadCloser, key string) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(file)
+	if head, _ := buffered.Peek(len(magic)); string(head) == magic {
+		file.Close()
+		return nil, fmt.Errorf("encrypted file %s has no data key", key)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{buffered, file}, nil
+}
+
+// SignedURL returns a URL served by Handler, which decrypts the file.
+func (s *Storage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return s.signer.URL(key, ttl), nil
+}
+
+// Handler serves decrypted files to requests carrying a valid, unexpired
+// signature. Mount it with the /uploads/ prefix stripped.
+func (s *Storage) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path
+		if !validKey(key) {
+			httperror.WriteError(w, httperror.New(http.StatusForbidden, "INVALID_SIGNATURE", "Download URL signature is invalid"))
+			return
+		}
+		remaining, verr := s.signer.Verify(r, key)
+		if verr != nil {
+			httperror.WriteError(w, verr)
+			return
+		}
+
+		file, err := s.Open(r.Context(), key)
+		if err != nil {
+			httperror.WriteError(w, httperror.NotFound("File not found"))
+			return
+		}
+		defer file.Close()
+
+		contentType := mime.TypeByExtension(path.Ext(key))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(remaining.Seconds())))
+		if _, err := io.Copy(w, file); err != nil {
+			// Cut the connection so a file that fails authentication part
+			// way is not mistaken for a complete one.
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
+
+// validKey reports whether key is a single path element, as backends
+// generate.
+func validKey(key string) bool {
+	return key != "" && key != "." && key != ".." && path.Base(key) == key
+}
@@ -0,0 +1,362 @@
+package encrypted
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+	"github.com/agunghallmanmaliki/amartha/internal/storage/local"
+)
+
+// memDataKeys keeps data keys in memory.
+type memDataKeys struct {
+	keys map[string]domain.DataKey
+}
+
+func (m *memDataKeys) Create(ctx context.Context, key *domain.DataKey) error {
+	m.keys[key.StorageKey] = *key
+	return nil
+}
+
+func (m *memDataKeys) GetByStorageKey(ctx context.Context, storageKey string) (*domain.DataKey, error) {
+	key, ok := m.keys[storageKey]
+	if !ok {
+		return nil, domain.ErrDataKeyNotFound
+	}
+	return &key, nil
+}
+
+func (m *memDataKeys) Delete(ctx context.Context, storageKey string) error {
+	delete(m.keys, storageKey)
+	return nil
+}
+
+func (m *memDataKeys) ListNotWrappedWith(ctx context.Context, masterKeyID, after string, limit int) ([]*domain.DataKey, error) {
+	var keys []*domain.DataKey
+	for _, key := range m.keys {
+		if key.MasterKeyID != masterKeyID && key.StorageKey > after {
+			keys = append(keys, &key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].StorageKey < keys[j].StorageKey })
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys, nil
+}
+
+func (m *memDataKeys) Rewrap(ctx context.Context, key *domain.DataKey, previousMasterKeyID string) error {
+	if stored, ok := m.keys[key.StorageKey]; !ok || stored.MasterKeyID != previousMasterKeyID {
+		return domain.ErrDataKeyNotFound
+	}
+	m.keys[key.StorageKey] = *key
+	return nil
+}
+
+func randomKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+type fixture struct {
+	dir      string
+	backend  *local.LocalStorage
+	dataKeys *memDataKeys
+	store    *Storage
+}
+
+func newFixture(t *testing.T, keyring *Keyring) *fixture {
+	t.Helper()
+	dir := t.TempDir()
+	backend, err := local.NewLocalStorage(dir, "http://localhost:8080", []byte("secret"))
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	signer, err := storage.NewURLSigner("http://localhost:8080", []byte("secret"))
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+	dataKeys := &memDataKeys{keys: map[string]domain.DataKey{}}
+	return &fixture{dir: dir, backend: backend, dataKeys: dataKeys, store: NewStorage(backend, keyring, dataKeys, signer)}
+}
+
+func (f *fixture) save(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	key, err := f.store.Save(context.Background(), name, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	if err := f.store.Promote(context.Background(), key); err != nil {
+		t.Fatalf("failed to promote: %v", err)
+	}
+	return key
+}
+
+func (f *fixture) read(key string) ([]byte, error) {
+	reader, err := f.store.Open(context.Background(), key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func TestStorageRoundTrip(t *testing.T) {
+	keyring, err := NewKeyring("a", map[string][]byte{"a": randomKey(t)})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	f := newFixture(t, keyring)
+
+	for _, size := range []int{0, 1, segmentSize - 1, segmentSize, segmentSize + 1, 3*segmentSize + 5} {
+		data := bytes.Repeat([]byte("agreement "), size/10+1)[:size]
+		key := f.save(t, "agreement.pdf", data)
+
+		stored, err := os.ReadFile(filepath.Join(f.dir, key))
+		if err != nil {
+			t.Fatalf("failed to read stored file: %v", err)
+		}
+		if size > 0 && bytes.Contains(stored, data[:min(size, 32)]) {
+			t.Errorf("size %d: expected the stored file to be encrypted", size)
+		}
+		got, err := f.read(key)
+		if err != nil {
+			t.Fatalf("size %d: unexpected error: %v", size, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("size %d: expected the original content back, got %d bytes", size, len(got))
+		}
+	}
+}
+
+func TestStorageRejectsTampering(t *testing.T) {
+	keyring, err := NewKeyring("a", map[string][]byte{"a": randomKey(t)})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	f := newFixture(t, keyring)
+	data := bytes.Repeat([]byte("x"), 2*segmentSize+100)
+	header := len(magic) + prefixSize
+	sealedSegment := segmentSize + 16
+
+	tests := []struct {
+		name   string
+		modify func([]byte) []byte
+	}{
+		{"flipped byte", func(b []byte) []byte { b[header+10] ^= 1; return b }},
+		{"truncated at a segment", func(b []byte) []byte { return b[:header+sealedSegment] }},
+		{"truncated mid segment", func(b []byte) []byte { return b[:header+sealedSegment+100] }},
+		{"appended data", func(b []byte) []byte { return append(b, 0) }},
+		{"swapped segments", func(b []byte) []byte {
+			first := append([]byte{}, b[header:header+sealedSegment]...)
+			copy(b[header:], b[header+sealedSegment:header+2*sealedSegment])
+			copy(b[header+sealedSegment:], first)
+			return b
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := f.save(t, "agreement.pdf", data)
+			path := filepath.Join(f.dir, key)
+			stored, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read stored file: %v", err)
+			}
+			if err := os.WriteFile(path, tt.modify(stored), 0644); err != nil {
+				t.Fatalf("failed to write stored file: %v", err)
+			}
+
+			if _, err := f.read(key); !errors.Is(err, ErrCorrupt) {
+				t.Errorf("expected ErrCorrupt, got %v", err)
+			}
+		})
+	}
+}
+
+func TestStorageUnencryptedFiles(t *testing.T) {
+	keyring, err := NewKeyring("a", map[string][]byte{"a": randomKey(t)})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	f := newFixture(t, keyring)
+	ctx := context.Background()
+
+	legacy, err := f.backend.Save(ctx, "proof.jpg", strings.NewReader("saved before encryption"))
+	if err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	if err := f.backend.Promote(ctx, legacy); err != nil {
+		t.Fatalf("failed to promote: %v", err)
+	}
+	if got, err := f.read(legacy); err != nil || string(got) != "saved before encryption" {
+		t.Errorf("expected the file as stored, got %q, %v", got, err)
+	}
+
+	orphan := f.save(t, "proof.jpg", []byte("jpeg data"))
+	delete(f.dataKeys.keys, orphan)
+	if _, err := f.read(orphan); err == nil {
+		t.Error("expected an error for an encrypted file without a data key")
+	}
+
+	discarded, err := f.store.Save(ctx, "proof.jpg", strings.NewReader("jpeg data"))
+	if err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	if err := f.store.Discard(ctx, discarded); err != nil {
+		t.Fatalf("failed to discard: %v", err)
+	}
+	if _, ok := f.dataKeys.keys[discarded]; ok {
+		t.Error("expected the data key of a discarded file to be deleted")
+	}
+}
+
+func TestStorageHandler(t *testing.T) {
+	keyring, err := NewKeyring("a", map[string][]byte{"a": randomKey(t)})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	f := newFixture(t, keyring)
+	key := f.save(t, "proof.jpg", []byte("jpeg data"))
+
+	signed, err := f.store.SignedURL(context.Background(), key, time.Minute)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("invalid URL %q: %v", signed, err)
+	}
+
+	handler := http.StripPrefix("/uploads/", f.store.Handler())
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "jpeg data" {
+		t.Errorf("expected the decrypted file, got %d %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "image/jpeg" {
+		t.Errorf("expected image/jpeg, got %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/uploads/"+key, nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without a signature, got %d", rec.Code)
+	}
+}
+
+func TestRotator(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	oldKey, newKey := randomKey(t), randomKey(t)
+
+	before, err := NewKeyring("2026-01", map[string][]byte{"2026-01": oldKey})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	f := newFixture(t, before)
+	keys := []string{f.save(t, "a.pdf", []byte("first")), f.save(t, "b.pdf", []byte("second"))}
+	stored, err := os.ReadFile(filepath.Join(f.dir, keys[0]))
+	if err != nil {
+		t.Fatalf("failed to read stored file: %v", err)
+	}
+
+	both, err := NewKeyring("2026-10", map[string][]byte{"2026-01": oldKey, "2026-10": newKey})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	report, err := NewRotator(both, f.dataKeys, logger).Run(ctx, true)
+	if err != nil || report.Rewrapped != 2 || f.dataKeys.keys[keys[0]].MasterKeyID != "2026-01" {
+		t.Fatalf("expected a dry run to report 2 keys and change none, got %+v, %v", report, err)
+	}
+	report, err = NewRotator(both, f.dataKeys, logger).Run(ctx, false)
+	if err != nil || report.Rewrapped != 2 || report.Failed != 0 {
+		t.Fatalf("expected 2 keys rewrapped, got %+v, %v", report, err)
+	}
+	if again, _ := NewRotator(both, f.dataKeys, logger).Run(ctx, false); again.Rewrapped != 0 {
+		t.Errorf("expected nothing left to rewrap, got %+v", again)
+	}
+
+	after, err := NewKeyring("2026-10", map[string][]byte{"2026-10": newKey})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	f.store.keyring = after
+	for i, want := range []string{"first", "second"} {
+		if got, err := f.read(keys[i]); err != nil || string(got) != want {
+			t.Errorf("expected %q with the new key alone, got %q, %v", want, got, err)
+		}
+	}
+	unchanged, err := os.ReadFile(filepath.Join(f.dir, keys[0]))
+	if err != nil || !bytes.Equal(unchanged, stored) {
+		t.Error("expected rotation to leave the stored file untouched")
+	}
+
+	f.dataKeys.keys["orphan.pdf"] = domain.DataKey{StorageKey: "orphan.pdf", MasterKeyID: "2025-01", Wrapped: []byte("x")}
+	if report, _ := NewRotator(after, f.dataKeys, logger).Run(ctx, false); report.Failed != 1 {
+		t.Errorf("expected a key of an unknown master key to fail, got %+v", report)
+	}
+}
+
+func TestLoadKeyring(t *testing.T) {
+	current, old := randomKey(t), randomKey(t)
+	encode := base64.StdEncoding.EncodeToString
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"valid", `{"current":"2026-10","keys":{"2026-10":"` + encode(current) + `","2026-01":"` + encode(old) + `"}}`, false},
+		{"missing current", `{"current":"2027-01","keys":{"2026-10":"` + encode(current) + `"}}`, true},
+		{"short key", `{"current":"2026-10","keys":{"2026-10":"` + encode(current[:16]) + `"}}`, true},
+		{"not base64", `{"current":"2026-10","keys":{"2026-10":"not base64!"}}`, true},
+		{"not json", `current=2026-10`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keyring.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("failed to write keyring: %v", err)
+			}
+			keyring, err := LoadKeyring(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if keyring.Current() != "2026-10" || len(keyring.keys) != 2 {
+				t.Errorf("expected 2 keys with 2026-10 current, got %q and %d keys", keyring.Current(), len(keyring.keys))
+			}
+		})
+	}
+
+	single, err := KeyringFromKey(encode(current))
+	if err != nil || single.Current() != Fingerprint(current) {
+		t.Errorf("expected a keyring named after the key's fingerprint, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package encrypted
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+)
+
+// keySize is the size of master and data keys, for AES-256.
+const keySize = 32
+
+// Keyring holds the master keys data keys are wrapped with. New data keys
+// are wrapped with the current key; the others are only kept to unwrap data
+// keys that have not been rotated yet.
+type Keyring struct {
+	current string
+	keys    map[string][]byte
+}
+
+// keyringFile is the layout of a keyring file, with base64-encoded keys:
+//
+//	{"current": "2026-10", "keys": {"2026-10": "...", "2026-01": "..."}}
+type keyringFile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// NewKeyring returns a keyring of 32-byte keys by id, current among them.
+func NewKeyring(current string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("current master key %q is not in the keyring", current)
+	}
+	for id, key := range keys {
+		if id == "" || len(id) > 64 {
+			return nil, fmt.Errorf("master key id %q must be 1 to 64 characters", id)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("master key %q must be %d bytes, got %d", id, keySize, len(key))
+		}
+	}
+	return &Keyring{current: current, keys: keys}, nil
+}
+
+// KeyringFromKey returns a keyring of the single base64-encoded key, named
+// after its fingerprint.
+func KeyringFromKey(encoded string) (*Keyring, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode master key: %w", err)
+	}
+	id := Fingerprint(key)
+	return NewKeyring(id, map[string][]byte{id: key})
+}
+
+// LoadKeyring reads a keyring file.
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring: %w", err)
+	}
+	var file keyringFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse keyring: %w", err)
+	}
+	keys := make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode master key %q: %w", id, err)
+		}
+		keys[id] = key
+	}
+	return NewKeyring(file.Current, keys)
+}
+
+// Fingerprint names a master key without revealing it.
+func Fingerprint(key []byte) string {
+	sum := sha256.Sum256(key)
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// Current returns the id of the key new data keys are wrapped with.
+func (k *Keyring) Current() string {
+	return k.current
+}
+
+// wrap seals dataKey with the current master key, bound to the storage key
+// of the file it encrypts so it cannot be moved to another file.
+func (k *Keyring) wrap(storageKey string, dataKey []byte) (*domain.DataKey, error) {
+	aead, err := newAEAD(k.keys[k.current])
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return &domain.DataKey{
+		StorageKey:  storageKey,
+		MasterKeyID: k.current,
+		Wrapped:     aead.Seal(nonce, nonce, dataKey, []byte(storageKey)),
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+func (k *Keyring) unwrap(key *domain.DataKey) ([]byte, error) {
+	master, ok := k.keys[key.MasterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("master key %q is not in the keyring", key.MasterKeyID)
+	}
+	aead, err := newAEAD(master)
+	if err != nil {
+		return nil, err
+	}
+	if len(key.Wrapped) < aead.NonceSize() {
+		return nil, errors.New("wrapped data key is truncated")
+	}
+	nonce, sealed := key.Wrapped[:aead.NonceSize()], key.Wrapped[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, sealed, []byte(key.StorageKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key of %s: %w", key.StorageKey, err)
+	}
+	return dataKey, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,87 @@
+package encrypted
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+)
+
+// rotateBatchSize is how many data keys are read from the database at once.
+const rotateBatchSize = 500
+
+// RotationReport counts what a rotation did, or would have done.
+type RotationReport struct {
+	Rewrapped int
+	Skipped   int // deleted or rewrapped by someone else meanwhile
+	Failed    int
+}
+
+type Rotator struct {
+	keyring  *Keyring
+	dataKeys repository.DataKeyRepository
+	logger   *slog.Logger
+}
+
+func NewRotator(keyring *Keyring, dataKeys repository.DataKeyRepository, logger *slog.Logger) *Rotator {
+	return &Rotator{
+		keyring:  keyring,
+		dataKeys: dataKeys,
+		logger:   logger,
+	}
+}
+
+// Run rewraps every data key wrapped by a master key other than the
+// keyring's current one. Files are not touched: a data key is only unwrapped
+// and wrapped again. Keys whose master key is missing from the keyring are
+// logged and counted as failed; Run only returns an error if the database
+// cannot be queried.
+func (r *Rotator) Run(ctx context.Context, dryRun bool) (RotationReport, error) {
+	var report RotationReport
+	current := r.keyring.Current()
+
+	after := ""
+	for {
+		keys, err := r.dataKeys.ListNotWrappedWith(ctx, current, after, rotateBatchSize)
+		if err != nil {
+			return report, err
+		}
+		for _, key := range keys {
+			switch err := r.rewrap(ctx, key, dryRun); {
+			case err == nil:
+				report.Rewrapped++
+			case errors.Is(err, domain.ErrDataKeyNotFound):
+				report.Skipped++
+			default:
+				report.Failed++
+				r.logger.ErrorContext(ctx, "failed to rewrap data key", "storage_key", key.StorageKey, "master_key_id", key.MasterKeyID, "error", err)
+			}
+		}
+		if len(keys) < rotateBatchSize {
+			return report, nil
+		}
+		after = keys[len(keys)-1].StorageKey
+	}
+}
+
+func (r *Rotator) rewrap(ctx context.Context, key *domain.DataKey, dryRun bool) error {
+	plain, err := r.keyring.unwrap(key)
+	if err != nil {
+		return err
+	}
+	wrapped, err := r.keyring.wrap(key.StorageKey, plain)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	now := time.Now()
+	wrapped.CreatedAt = key.CreatedAt
+	wrapped.RotatedAt = &now
+	return r.dataKeys.Rewrap(ctx, wrapped, key.MasterKeyID)
+}
@@ -0,0 +1,178 @@
+package encrypted
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// An encrypted file starts with magic and a random nonce prefix, followed
+// by the content in segments of segmentSize bytes. Each segment is sealed
+// with AES-GCM under a nonce made of the prefix, the segment's number and
+// whether it is the last one, so segments cannot be reordered or dropped
+// and the file cannot be truncated unnoticed.
+const (
+	magic       = "AMENC\x01"
+	prefixSize  = 7
+	segmentSize = 64 * 1024
+)
+
+// ErrCorrupt is returned when an encrypted file fails authentication.
+var ErrCorrupt = errors.New("encrypted file is corrupt or truncated")
+
+func segmentNonce(prefix []byte, segment uint32, last bool) []byte {
+	nonce := make([]byte, 0, prefixSize+5)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, segment)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// encryptReader reads the encrypted form of src.
+type encryptReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	segment uint32
+	plain   []byte
+	sealed  []byte
+	pending []byte
+	done    bool
+}
+
+func newEncryptReader(src io.Reader, aead cipher.AEAD) (*encryptReader, error) {
+	prefix := make([]byte, prefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce prefix: %w", err)
+	}
+	return &encryptReader{
+		src:     bufio.NewReaderSize(src, segmentSize),
+		aead:    aead,
+		prefix:  prefix,
+		plain:   make([]byte, segmentSize),
+		sealed:  make([]byte, 0, segmentSize+aead.Overhead()),
+		pending: append([]byte(magic), prefix...),
+	}, nil
+}
+
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.seal(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// seal encrypts the next segment. A full segment is the last one if
+// nothing follows it.
+func (r *encryptReader) seal() error {
+	n, err := io.ReadFull(r.src, r.plain)
+	last := false
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	default:
+		if _, err := r.src.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+	if r.segment == 1<<32-1 && !last {
+		return errors.New("file is too large to encrypt")
+	}
+
+	r.pending = r.aead.Seal(r.sealed[:0], segmentNonce(r.prefix, r.segment, last), r.plain[:n], nil)
+	r.segment++
+	r.done = last
+	return nil
+}
+
+// decryptReader reads the content of an encrypted file.
+type decryptReader struct {
+	src     *bufio.Reader
+	closer  io.Closer
+	aead    cipher.AEAD
+	prefix  []byte
+	segment uint32
+	sealed  []byte
+	pending []byte
+	done    bool
+}
+
+func newDecryptReader(file io.ReadCloser, aead cipher.AEAD) (*decryptReader, error) {
+	src := bufio.NewReaderSize(file, segmentSize+aead.Overhead())
+	header := make([]byte, len(magic)+prefixSize)
+	if _, err := io.ReadFull(src, header); err != nil || string(header[:len(magic)]) != magic {
+		return nil, ErrCorrupt
+	}
+	return &decryptReader{
+		src:    src,
+		closer: file,
+		aead:   aead,
+		prefix: header[len(magic):],
+		sealed: make([]byte, segmentSize+aead.Overhead()),
+	}, nil
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// open decrypts the next segment. A file that ends before a segment sealed
+// as the last one has been truncated.
+func (r *decryptReader) open() error {
+	n, err := io.ReadFull(r.src, r.sealed)
+	last := false
+	switch {
+	case errors.Is(err, io.EOF):
+		return ErrCorrupt
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	default:
+		if _, err := r.src.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	plain, err := r.aead.Open(r.sealed[:0], segmentNonce(r.prefix, r.segment, last), r.sealed[:n], nil)
+	if err != nil {
+		return ErrCorrupt
+	}
+	r.pending = plain
+	r.segment++
+	r.done = last
+	return nil
+}
+
+func (r *decryptReader) Close() error {
+	return r.closer.Close()
+}
@@ -0,0 +1,141 @@
+// Package gc reconciles stored files against the database rows that
+// reference them.
+package gc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/repository"
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+)
+
+// batchSize is how many keys are looked up in the database at once.
+const batchSize = 500
+
+// Options control what a collection run changes.
+type Options struct {
+	// DryRun reports what would change without touching storage.
+	DryRun bool
+
+	// Delete removes unreferenced committed files instead of quarantining
+	// them.
+	Delete bool
+}
+
+// Report counts what a collection run did, or would have done.
+type Report struct {
+	Promoted    int // staged files already referenced, whose promotion failed
+	Expired     int // unreferenced staged files older than the grace period
+	Quarantined int
+	Deleted     int
+	Skipped     int // unreferenced files younger than the grace period
+	Failed      int
+}
+
+type Collector struct {
+	storage storage.Inventory
+	files   repository.StoredFileRepository
+	grace   time.Duration
+	logger  *slog.Logger
+}
+
+// NewCollector returns a collector that leaves unreferenced files alone until
+// they are older than grace, so uploads whose transaction is still running
+// are not collected.
+func NewCollector(storage storage.Inventory, files repository.StoredFileRepository, grace time.Duration, logger *slog.Logger) *Collector {
+	return &Collector{
+		storage: storage,
+		files:   files,
+		grace:   grace,
+		logger:  logger,
+	}
+}
+
+// Run promotes staged files that rows reference, removes stale staged files,
+// and quarantines or deletes committed files no row references. Failures on
+// single files are logged and counted; Run only returns an error if storage
+// cannot be listed or the database cannot be queried.
+func (c *Collector) Run(ctx context.Context, opts Options) (Report, error) {
+	var report Report
+	cutoff := time.Now().Add(-c.grace)
+
+	err := c.reconcile(ctx, storage.Staging, func(object storage.Object, referenced bool) {
+		switch {
+		case referenced:
+			c.apply(ctx, opts, &report.Promoted, &report.Failed, "promote", object, func() error {
+				return c.storage.Promote(ctx, object.Key)
+			})
+		case object.ModTime.Before(cutoff):
+			c.apply(ctx, opts, &report.Expired, &report.Failed, "expire", object, func() error {
+				return c.storage.Delete(ctx, storage.Staging, object.Key)
+			})
+		default:
+			report.Skipped++
+		}
+	})
+	if err != nil {
+		return report, err
+	}
+
+	err = c.reconcile(ctx, storage.Committed, func(object storage.Object, referenced bool) {
+		switch {
+		case referenced:
+		case !object.ModTime.Before(cutoff):
+			report.Skipped++
+		case opts.Delete:
+			c.apply(ctx, opts, &report.Deleted, &report.Failed, "delete", object, func() error {
+				return c.storage.Delete(ctx, storage.Committed, object.Key)
+			})
+		default:
+			c.apply(ctx, opts, &report.Quarantined, &report.Failed, "quarantine", object, func() error {
+				return c.storage.Quarantine(ctx, storage.Committed, object.Key)
+			})
+		}
+	})
+	return report, err
+}
+
+// reconcile lists area in full before changing anything in it, then calls fn
+// for each file with whether a row references it.
+func (c *Collector) reconcile(ctx context.Context, area storage.Area, fn func(object storage.Object, referenced bool)) error {
+	var objects []storage.Object
+	err := c.storage.List(ctx, area, func(object storage.Object) error {
+		objects = append(objects, object)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(objects); start += batchSize {
+		batch := objects[start:min(start+batchSize, len(objects))]
+		keys := make([]string, len(batch))
+		for i, object := range batch {
+			keys[i] = object.Key
+		}
+		referenced, err := c.files.ReferencedKeys(ctx, keys)
+		if err != nil {
+			return fmt.Errorf("failed to look up %s files: %w", area, err)
+		}
+		for _, object := range batch {
+			fn(object, referenced[object.Key])
+		}
+	}
+	return nil
+}
+
+func (c *Collector) apply(ctx context.Context, opts Options, count, failed *int, action string, object storage.Object, fn func() error) {
+	if !opts.DryRun {
+		if err := fn(); err != nil {
+			c.logger.ErrorContext(ctx, "storage gc failed", "action", action, "key", object.Key, "error", err)
+			*failed++
+			return
+		}
+	}
+	c.logger.InfoContext(ctx, "storage gc", "action", action, "key", object.Key,
+		"size", object.Size, "modified_at", object.ModTime, "dry_run", opts.DryRun)
+	*count++
+}
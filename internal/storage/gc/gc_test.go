@@ -0,0 +1,164 @@
+package gc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+)
+
+// memInventory keeps objects per area in memory.
+type memInventory struct {
+	areas map[storage.Area]map[string]storage.Object
+}
+
+func newMemInventory() *memInventory {
+	return &memInventory{areas: map[storage.Area]map[string]storage.Object{
+		storage.Staging:     {},
+		storage.Committed:   {},
+		storage.Quarantined: {},
+	}}
+}
+
+func (m *memInventory) put(area storage.Area, key string, age time.Duration) {
+	m.areas[area][key] = storage.Object{Key: key, Size: 1, ModTime: time.Now().Add(-age)}
+}
+
+func (m *memInventory) move(key string, from, to storage.Area) error {
+	object, ok := m.areas[from][key]
+	if !ok {
+		return errors.New("not found")
+	}
+	delete(m.areas[from], key)
+	m.areas[to][key] = object
+	return nil
+}
+
+func (m *memInventory) Save(ctx context.Context, name string, reader io.Reader) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (m *memInventory) Promote(ctx context.Context, key string) error {
+	return m.move(key, storage.Staging, storage.Committed)
+}
+
+func (m *memInventory) Discard(ctx context.Context, key string) error {
+	return m.Delete(ctx, storage.Staging, key)
+}
+
+func (m *memInventory) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memInventory) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (m *memInventory) List(ctx context.Context, area storage.Area, fn func(storage.Object) error) error {
+	for _, object := range m.areas[area] {
+		if err := fn(object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memInventory) Delete(ctx context.Context, area storage.Area, key string) error {
+	if _, ok := m.areas[area][key]; !ok {
+		return errors.New("not found")
+	}
+	delete(m.areas[area], key)
+	return nil
+}
+
+func (m *memInventory) Quarantine(ctx context.Context, from storage.Area, key string) error {
+	return m.move(key, from, storage.Quarantined)
+}
+
+type referencedKeys map[string]bool
+
+func (r referencedKeys) ReferencedKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	found := map[string]bool{}
+	for _, key := range keys {
+		if r[key] {
+			found[key] = true
+		}
+	}
+	return found, nil
+}
+
+func TestCollectorRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       Options
+		wantReport Report
+		wantAreas  map[storage.Area][]string
+	}{
+		{
+			name:       "quarantine",
+			wantReport: Report{Promoted: 1, Expired: 1, Quarantined: 1, Skipped: 2},
+			wantAreas: map[storage.Area][]string{
+				storage.Staging:     {"staged-new.jpg"},
+				storage.Committed:   {"committed.jpg", "orphan-new.pdf", "promoted.jpg"},
+				storage.Quarantined: {"orphan-old.pdf"},
+			},
+		},
+		{
+			name:       "delete",
+			opts:       Options{Delete: true},
+			wantReport: Report{Promoted: 1, Expired: 1, Deleted: 1, Skipped: 2},
+			wantAreas: map[storage.Area][]string{
+				storage.Staging:     {"staged-new.jpg"},
+				storage.Committed:   {"committed.jpg", "orphan-new.pdf", "promoted.jpg"},
+				storage.Quarantined: {},
+			},
+		},
+		{
+			name:       "dry run",
+			opts:       Options{DryRun: true},
+			wantReport: Report{Promoted: 1, Expired: 1, Quarantined: 1, Skipped: 2},
+			wantAreas: map[storage.Area][]string{
+				storage.Staging:     {"promoted.jpg", "staged-new.jpg", "staged-old.jpg"},
+				storage.Committed:   {"committed.jpg", "orphan-new.pdf", "orphan-old.pdf"},
+				storage.Quarantined: {},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inventory := newMemInventory()
+			inventory.put(storage.Staging, "promoted.jpg", 48*time.Hour)
+			inventory.put(storage.Staging, "staged-old.jpg", 48*time.Hour)
+			inventory.put(storage.Staging, "staged-new.jpg", time.Minute)
+			inventory.put(storage.Committed, "committed.jpg", 48*time.Hour)
+			inventory.put(storage.Committed, "orphan-old.pdf", 48*time.Hour)
+			inventory.put(storage.Committed, "orphan-new.pdf", time.Minute)
+			refs := referencedKeys{"promoted.jpg": true, "committed.jpg": true}
+
+			collector := NewCollector(inventory, refs, 24*time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			report, err := collector.Run(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if report != tt.wantReport {
+				t.Errorf("expected report %+v, got %+v", tt.wantReport, report)
+			}
+			for area, want := range tt.wantAreas {
+				if len(inventory.areas[area]) != len(want) {
+					t.Errorf("expected %s to hold %v, got %v", area, want, inventory.areas[area])
+					continue
+				}
+				for _, key := range want {
+					if _, ok := inventory.areas[area][key]; !ok {
+						t.Errorf("expected %s to hold %s", area, key)
+					}
+				}
+			}
+		})
+	}
+}
@@ -2,26 +2,49 @@ package local
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
 	"github.com/google/uuid"
 )
 
 type LocalStorage struct {
 	basePath string
-	baseURL  string
+	signer   *storage.URLSigner
 }
 
-func NewLocalStorage(basePath, baseURL string) (*LocalStorage, error) {
-	if err := os.MkdirAll(basePath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+// Staged and quarantined files are kept in hidden directories next to the
+// committed ones, so that moving between them is a rename.
+var areaDirs = map[storage.Area]string{
+	storage.Staging:     ".staging",
+	storage.Committed:   "",
+	storage.Quarantined: ".quarantine",
+}
+
+// NewLocalStorage stores files in basePath. Download URLs point at
+// baseURL/uploads/ and are signed with signingKey, which must be shared by
+// every instance serving them.
+func NewLocalStorage(basePath, baseURL string, signingKey []byte) (*LocalStorage, error) {
+	signer, err := storage.NewURLSigner(baseURL, signingKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range areaDirs {
+		if err := os.MkdirAll(filepath.Join(basePath, dir), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create storage directory: %w", err)
+		}
 	}
 	return &LocalStorage{
 		basePath: basePath,
-		baseURL:  baseURL,
+		signer:   signer,
 	}, nil
 }
 
@@ -29,7 +52,7 @@ func (s *LocalStorage) Save(ctx context.Context, filename string, reader io.Read
 	ext := filepath.Ext(filename)
 	newFilename := uuid.New().String() + ext
 
-	fullPath := filepath.Join(s.basePath, newFilename)
+	fullPath := s.path(storage.Staging, newFilename)
 
 	file, err := os.Create(fullPath)
 	if err != nil {
@@ -38,12 +61,144 @@ func (s *LocalStorage) Save(ctx context.Context, filename string, reader io.Read
 	defer file.Close()
 
 	if _, err := io.Copy(file, reader); err != nil {
+		os.Remove(fullPath)
 		return "", fmt.Errorf("failed to write file: %w", err)
 	}
 
 	return newFilename, nil
 }
 
-func (s *LocalStorage) GetURL(path string) string {
-	return fmt.Sprintf("%s/uploads/%s", s.baseURL, path)
+func (s *LocalStorage) Promote(ctx context.Context, key string) error {
+	return s.move(key, storage.Staging, storage.Committed)
+}
+
+func (s *LocalStorage) Discard(ctx context.Context, key string) error {
+	return s.Delete(ctx, storage.Staging, key)
+}
+
+func (s *LocalStorage) Quarantine(ctx context.Context, from storage.Area, key string) error {
+	return s.move(key, from, storage.Quarantined)
+}
+
+// List skips hidden files, which hold the other areas and health checks.
+func (s *LocalStorage) List(ctx context.Context, area storage.Area, fn func(storage.Object) error) error {
+	entries, err := os.ReadDir(s.path(area, ""))
+	if err != nil {
+		return fmt.Errorf("failed to list %s files: %w", area, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to stat file: %w", err)
+		}
+		if err := fn(storage.Object{Key: entry.Name(), Size: info.Size(), ModTime: info.ModTime()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, area storage.Area, key string) error {
+	if !validKey(key) {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	if err := os.Remove(s.path(area, key)); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) move(key string, from, to storage.Area) error {
+	if !validKey(key) {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	if err := os.Rename(s.path(from, key), s.path(to, key)); err != nil {
+		return fmt.Errorf("failed to move file to %s: %w", to, err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) path(area storage.Area, key string) string {
+	return filepath.Join(s.basePath, areaDirs[area], key)
+}
+
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if !validKey(key) {
+		return nil, fmt.Errorf("invalid storage key %q", key)
+	}
+	file, err := os.Open(s.path(storage.Committed, key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return file, nil
+}
+
+// SignedURL returns baseURL/uploads/<key> signed with the storage's URLSigner,
+// which Handler verifies.
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return s.signer.URL(key, ttl), nil
+}
+
+// Handler serves stored files to requests carrying a valid, unexpired
+// signature. Mount it with the /uploads/ prefix stripped.
+func (s *LocalStorage) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path
+		if !validKey(key) {
+			httperror.WriteError(w, httperror.New(http.StatusForbidden, "INVALID_SIGNATURE", "Download URL signature is invalid"))
+			return
+		}
+		remaining, verr := s.signer.Verify(r, key)
+		if verr != nil {
+			httperror.WriteError(w, verr)
+			return
+		}
+
+		file, err := os.Open(filepath.Join(s.basePath, key))
+		if err != nil {
+			httperror.WriteError(w, httperror.NotFound("File not found"))
+			return
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil || info.IsDir() {
+			httperror.WriteError(w, httperror.NotFound("File not found"))
+			return
+		}
+
+		w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(remaining.Seconds())))
+		http.ServeContent(w, r, key, info.ModTime(), file)
+	})
+}
+
+// validKey reports whether key names a file directly inside the storage
+// directory, as Save generates.
+func validKey(key string) bool {
+	return key != "" && key != "." && key != ".." && filepath.Base(key) == key
+}
+
+// CheckWritable verifies that files can be created in the storage directory.
+func (s *LocalStorage) CheckWritable(ctx context.Context) error {
+	file, err := os.CreateTemp(s.basePath, ".healthcheck-*")
+	if err != nil {
+		return fmt.Errorf("storage directory is not writable: %w", err)
+	}
+	name := file.Name()
+	defer os.Remove(name)
+
+	if _, err := file.Write([]byte("ok")); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write to storage directory: %w", err)
+	}
+	return file.Close()
 }
@@ -0,0 +1,186 @@
+package local
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+)
+
+func TestLocalStorageSignedURL(t *testing.T) {
+	storage, err := NewLocalStorage(t.TempDir(), "http://localhost:8080", []byte("secret"))
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	key, err := storage.Save(context.Background(), "proof.jpg", strings.NewReader("jpeg data"))
+	if err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	other, err := storage.Save(context.Background(), "other.jpg", strings.NewReader("other data"))
+	if err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	staged, err := storage.Save(context.Background(), "staged.jpg", strings.NewReader("staged data"))
+	if err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	for _, key := range []string{key, other} {
+		if err := storage.Promote(context.Background(), key); err != nil {
+			t.Fatalf("failed to promote: %v", err)
+		}
+	}
+
+	sign := func(key string, ttl time.Duration) *url.URL {
+		t.Helper()
+		signed, err := storage.SignedURL(context.Background(), key, ttl)
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		u, err := url.Parse(signed)
+		if err != nil {
+			t.Fatalf("invalid URL %q: %v", signed, err)
+		}
+		return u
+	}
+
+	valid := sign(key, time.Minute)
+	if !strings.HasPrefix(valid.String(), "http://localhost:8080/uploads/"+key+"?") {
+		t.Errorf("expected URL under /uploads/%s, got %q", key, valid)
+	}
+
+	tampered := sign(key, time.Minute)
+	query := tampered.Query()
+	query.Set("expires", "9999999999")
+	tampered.RawQuery = query.Encode()
+
+	swapped := sign(key, time.Minute)
+	swapped.Path = "/uploads/" + other
+
+	tests := []struct {
+		name       string
+		url        *url.URL
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "valid", url: valid, wantStatus: http.StatusOK, wantBody: "jpeg data"},
+		{name: "unsigned", url: &url.URL{Path: "/uploads/" + key}, wantStatus: http.StatusForbidden, wantBody: "INVALID_SIGNATURE"},
+		{name: "extended expiry", url: tampered, wantStatus: http.StatusForbidden, wantBody: "INVALID_SIGNATURE"},
+		{name: "other file", url: swapped, wantStatus: http.StatusForbidden, wantBody: "INVALID_SIGNATURE"},
+		{name: "expired", url: sign(key, -time.Minute), wantStatus: http.StatusForbidden, wantBody: "URL_EXPIRED"},
+		{name: "missing file", url: sign("missing.jpg", time.Minute), wantStatus: http.StatusNotFound},
+		{name: "staged file", url: sign(staged, time.Minute), wantStatus: http.StatusNotFound},
+		{name: "staging directory", url: sign(".staging", time.Minute), wantStatus: http.StatusNotFound},
+	}
+
+	handler := http.StripPrefix("/uploads/", storage.Handler())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url.RequestURI(), nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("expected body to contain %q, got %q", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestLocalStorageSignedURLRejectsPaths(t *testing.T) {
+	storage, err := NewLocalStorage(t.TempDir(), "http://localhost:8080", []byte("secret"))
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	for _, key := range []string{"", "..", "../etc/passwd", "a/b.jpg"} {
+		if _, err := storage.SignedURL(context.Background(), key, time.Minute); err == nil {
+			t.Errorf("expected error for key %q", key)
+		}
+	}
+}
+
+func TestLocalStorageAreas(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStorage(t.TempDir(), "http://localhost:8080", []byte("secret"))
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	if err := store.CheckWritable(ctx); err != nil {
+		t.Fatalf("failed to check storage: %v", err)
+	}
+
+	save := func(name string) string {
+		t.Helper()
+		key, err := store.Save(ctx, name, strings.NewReader(name))
+		if err != nil {
+			t.Fatalf("failed to save: %v", err)
+		}
+		return key
+	}
+	committed, staged, discarded, quarantined := save("a.pdf"), save("b.pdf"), save("c.pdf"), save("d.pdf")
+
+	for _, key := range []string{committed, quarantined} {
+		if err := store.Promote(ctx, key); err != nil {
+			t.Fatalf("failed to promote: %v", err)
+		}
+	}
+	if err := store.Discard(ctx, discarded); err != nil {
+		t.Fatalf("failed to discard: %v", err)
+	}
+	if err := store.Quarantine(ctx, storage.Committed, quarantined); err != nil {
+		t.Fatalf("failed to quarantine: %v", err)
+	}
+	if err := store.Promote(ctx, discarded); err == nil {
+		t.Error("expected error promoting a discarded file")
+	}
+
+	reader, err := store.Open(ctx, committed)
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(data) != "a.pdf" {
+		t.Errorf("expected committed file to read %q, got %q", "a.pdf", data)
+	}
+	if _, err := store.Open(ctx, staged); err == nil {
+		t.Error("expected error opening a staged file")
+	}
+
+	tests := []struct {
+		area     storage.Area
+		wantKey  string
+		wantData string
+	}{
+		{area: storage.Staging, wantKey: staged, wantData: "b.pdf"},
+		{area: storage.Committed, wantKey: committed, wantData: "a.pdf"},
+		{area: storage.Quarantined, wantKey: quarantined, wantData: "d.pdf"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.area), func(t *testing.T) {
+			var keys []string
+			err := store.List(ctx, tt.area, func(object storage.Object) error {
+				keys = append(keys, object.Key)
+				if object.Size != int64(len(tt.wantData)) || object.ModTime.IsZero() {
+					t.Errorf("expected size %d and a modification time, got %+v", len(tt.wantData), object)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(keys) != 1 || keys[0] != tt.wantKey {
+				t.Errorf("expected [%s], got %v", tt.wantKey, keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// DefaultPartSize is the smallest part size S3 accepts for multipart uploads.
+const DefaultPartSize = 5 * 1024 * 1024
+
+// areaPrefixes place staged and quarantined objects under the configured
+// prefix, so a bucket lifecycle rule can expire .staging/ as a backstop.
+var areaPrefixes = map[storage.Area]string{
+	storage.Staging:     ".staging",
+	storage.Committed:   "",
+	storage.Quarantined: ".quarantine",
+}
+
+type Config struct {
+	Endpoint        string // host[:port], e.g. s3.amazonaws.com or minio:9000
+	UseSSL          bool
+	Region          string
+	Bucket          string
+	Prefix          string // optional key prefix, e.g. "uploads"
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// PathStyle addresses objects as endpoint/bucket/key instead of
+	// bucket.endpoint/key, as MinIO and most S3-compatible stores expect.
+	PathStyle bool
+
+	// PartSize is the size above which uploads switch to multipart and the
+	// size of each part. Defaults to DefaultPartSize.
+	PartSize int64
+
+	// Transport overrides the HTTP transport, mainly for tests.
+	Transport http.RoundTripper
+}
+
+// S3Storage stores uploads in an S3-compatible bucket so that any API
+// instance can read what another one wrote.
+type S3Storage struct {
+	client   *minio.Client
+	bucket   string
+	prefix   string
+	partSize int64
+}
+
+func NewS3Storage(cfg Config) (*S3Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("s3 bucket is required")
+	}
+	if cfg.PartSize == 0 {
+		cfg.PartSize = DefaultPartSize
+	}
+	if cfg.PartSize < DefaultPartSize {
+		return nil, fmt.Errorf("s3 part size must be at least %d bytes", DefaultPartSize)
+	}
+
+	lookup := minio.BucketLookupDNS
+	if cfg.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken),
+		Secure:       cfg.UseSSL,
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+		Transport:    cfg.Transport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+
+	return &S3Storage{
+		client:   client,
+		bucket:   cfg.Bucket,
+		prefix:   strings.Trim(cfg.Prefix, "/"),
+		partSize: cfg.PartSize,
+	}, nil
+}
+
+// Save stages the file under a new name and returns that name. Files that
+// fit in one part are sent with a single PUT; larger ones use a multipart
+// upload so the whole file is never held in memory.
+func (s *S3Storage) Save(ctx context.Context, filename string, reader io.Reader) (string, error) {
+	ext := filepath.Ext(filename)
+	newFilename := uuid.New().String() + ext
+
+	opts := minio.PutObjectOptions{
+		ContentType: mime.TypeByExtension(ext),
+		PartSize:    uint64(s.partSize),
+	}
+
+	var head bytes.Buffer
+	n, err := io.CopyN(&head, reader, s.partSize+1)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if n <= s.partSize {
+		_, err = s.client.PutObject(ctx, s.bucket, s.key(storage.Staging, newFilename), &head, n, opts)
+	} else {
+		_, err = s.client.PutObject(ctx, s.bucket, s.key(storage.Staging, newFilename), io.MultiReader(&head, reader), -1, opts)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	return newFilename, nil
+}
+
+// Promote copies the staged object to its committed key and removes it from
+// staging; S3 has no rename.
+func (s *S3Storage) Promote(ctx context.Context, key string) error {
+	return s.move(ctx, key, storage.Staging, storage.Committed)
+}
+
+func (s *S3Storage) Discard(ctx context.Context, key string) error {
+	return s.Delete(ctx, storage.Staging, key)
+}
+
+func (s *S3Storage) Quarantine(ctx context.Context, from storage.Area, key string) error {
+	return s.move(ctx, key, from, storage.Quarantined)
+}
+
+// List skips hidden names, which hold the other areas and health checks.
+func (s *S3Storage) List(ctx context.Context, area storage.Area, fn func(storage.Object) error) error {
+	prefix := s.key(area, "")
+	if prefix != "" {
+		prefix += "/"
+	}
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix}) {
+		if object.Err != nil {
+			return fmt.Errorf("failed to list %s objects: %w", area, object.Err)
+		}
+		name := strings.TrimPrefix(object.Key, prefix)
+		if name == "" || strings.HasSuffix(name, "/") || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if err := fn(storage.Object{Key: name, Size: object.Size, ModTime: object.LastModified}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, area storage.Area, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, s.key(area, key), minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	return nil
+}
+
+func (s *S3Storage) move(ctx context.Context, key string, from, to storage.Area) error {
+	_, err := s.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: s.bucket, Object: s.key(to, key)},
+		minio.CopySrcOptions{Bucket: s.bucket, Object: s.key(from, key)},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to copy object to %s: %w", to, err)
+	}
+	return s.Delete(ctx, from, key)
+}
+
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, s.key(storage.Committed, key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	// GetObject is lazy; Stat makes the request so a missing object fails here.
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	return object, nil
+}
+
+// SignedURL returns a presigned GET URL, so the bucket itself can stay
+// private. S3 limits ttl to 7 days.
+func (s *S3Storage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, s.key(storage.Committed, key), ttl, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign url: %w", err)
+	}
+	return u.String(), nil
+}
+
+// CheckWritable verifies that objects can be created in the bucket.
+func (s *S3Storage) CheckWritable(ctx context.Context) error {
+	key := s.key(storage.Committed, ".healthcheck-"+uuid.New().String())
+
+	if _, err := s.client.PutObject(ctx, s.bucket, key, strings.NewReader("ok"), 2, minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("bucket is not writable: %w", err)
+	}
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to remove healthcheck object: %w", err)
+	}
+	return nil
+}
+
+func (s *S3Storage) key(area storage.Area, name string) string {
+	return path.Join(s.prefix, areaPrefixes[area], name)
+}
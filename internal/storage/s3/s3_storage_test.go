@@ -0,0 +1,433 @@
+package s3
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/internal/storage"
+)
+
+// fakeS3 is an in-process stand-in for the subset of the S3 API the storage
+// uses: single PUTs, multipart uploads, copies, reads, listings and deletes.
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string][]byte // "bucket/key"
+	types    map[string]string
+	modified map[string]time.Time
+	uploads  map[string]map[int][]byte
+	hosts    []string
+	parts    int
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		objects:  map[string][]byte{},
+		types:    map[string]string{},
+		modified: map[string]time.Time{},
+		uploads:  map[string]map[int][]byte{},
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	host := r.Host
+	if h, _, err := net.SplitHostPort(r.Host); err == nil {
+		host = h
+	}
+	f.hosts = append(f.hosts, host)
+
+	// Virtual-host requests name the bucket in the host; path-style ones in
+	// the first path segment.
+	object := strings.TrimPrefix(r.URL.Path, "/")
+	if bucket, _, ok := strings.Cut(host, "."); ok && net.ParseIP(host) == nil {
+		object = bucket + "/" + object
+	}
+
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		id := strconv.Itoa(len(f.uploads) + 1)
+		f.uploads[id] = map[int][]byte{}
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", id)
+
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		parts, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchUpload</Code></Error>", http.StatusNotFound)
+			return
+		}
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		parts[number] = readBody(r)
+		f.parts++
+		w.Header().Set("ETag", fmt.Sprintf(`"part-%d"`, number))
+
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		parts := f.uploads[query.Get("uploadId")]
+		numbers := make([]int, 0, len(parts))
+		for number := range parts {
+			numbers = append(numbers, number)
+		}
+		sort.Ints(numbers)
+
+		var data []byte
+		for _, number := range numbers {
+			data = append(data, parts[number]...)
+		}
+		f.objects[object] = data
+		f.modified[object] = time.Now()
+		delete(f.uploads, query.Get("uploadId"))
+		bucket, key, _ := strings.Cut(object, "/")
+		fmt.Fprintf(w, "<CompleteMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><ETag>done</ETag></CompleteMultipartUploadResult>", bucket, key)
+
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		source, _ := url.PathUnescape(strings.TrimPrefix(r.Header.Get("X-Amz-Copy-Source"), "/"))
+		data, ok := f.objects[source]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		f.objects[object] = data
+		f.types[object] = f.types[source]
+		f.modified[object] = time.Now()
+		fmt.Fprintf(w, "<CopyObjectResult><ETag>\"object\"</ETag><LastModified>%s</LastModified></CopyObjectResult>", time.Now().UTC().Format(time.RFC3339))
+
+	case r.Method == http.MethodPut:
+		f.objects[object] = readBody(r)
+		f.types[object] = r.Header.Get("Content-Type")
+		f.modified[object] = time.Now()
+		w.Header().Set("ETag", `"object"`)
+
+	case r.Method == http.MethodGet && query.Get("list-type") == "2":
+		f.list(w, strings.TrimSuffix(object, "/"), query.Get("prefix"), query.Get("delimiter"))
+
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := f.objects[object]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "<Error><Code>NoSuchKey</Code><Message>missing</Message></Error>")
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Header().Set("Content-Type", f.types[object])
+		w.Header().Set("ETag", `"object"`)
+		w.Header().Set("Last-Modified", f.modified[object].UTC().Format(http.TimeFormat))
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+
+	case r.Method == http.MethodDelete:
+		delete(f.objects, object)
+		delete(f.modified, object)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "<Error><Code>NotImplemented</Code></Error>", http.StatusNotImplemented)
+	}
+}
+
+// list writes a single-page ListObjectsV2 result, grouping keys below the
+// next delimiter into common prefixes.
+func (f *fakeS3) list(w http.ResponseWriter, bucket, prefix, delimiter string) {
+	var keys []string
+	prefixes := map[string]bool{}
+	for object := range f.objects {
+		key, ok := strings.CutPrefix(object, bucket+"/")
+		if !ok || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+			prefixes[key[:len(prefix)+i+1]] = true
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(w, "<ListBucketResult><Name>%s</Name><Prefix>%s</Prefix><KeyCount>%d</KeyCount><IsTruncated>false</IsTruncated>", bucket, prefix, len(keys))
+	for _, key := range keys {
+		fmt.Fprintf(w, "<Contents><Key>%s</Key><Size>%d</Size><LastModified>%s</LastModified><ETag>\"object\"</ETag></Contents>",
+			key, len(f.objects[bucket+"/"+key]), f.modified[bucket+"/"+key].UTC().Format(time.RFC3339))
+	}
+	for p := range prefixes {
+		fmt.Fprintf(w, "<CommonPrefixes><Prefix>%s</Prefix></CommonPrefixes>", p)
+	}
+	fmt.Fprint(w, "</ListBucketResult>")
+}
+
+// readBody returns the payload, decoding the aws-chunked encoding that
+// streaming signatures and checksum trailers use.
+func readBody(r *http.Request) []byte {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") &&
+		!strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") {
+		data, _ := io.ReadAll(r.Body)
+		return data
+	}
+
+	var data []byte
+	br := bufio.NewReader(r.Body)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return data
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil || size == 0 {
+			return data
+		}
+		chunk := make([]byte, size)
+		if _, err := io.ReadFull(br, chunk); err != nil {
+			return data
+		}
+		data = append(data, chunk...)
+		br.ReadString('\n')
+	}
+}
+
+func (f *fakeS3) object(key string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.objects[key]
+	return data, ok
+}
+
+func newTestStorage(t *testing.T, cfg Config) (*S3Storage, *fakeS3) {
+	t.Helper()
+
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	addr := server.Listener.Addr().String()
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = addr
+	}
+	cfg.Region = "us-east-1"
+	cfg.AccessKeyID = "test"
+	cfg.SecretAccessKey = "secret"
+
+	// Dial the fake whatever host the client addresses, so virtual-host
+	// style works without DNS.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}
+	cfg.Transport = transport
+
+	storage, err := NewS3Storage(cfg)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	return storage, fake
+}
+
+func TestS3StorageSave(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		wantHost string
+		wantKey  string
+	}{
+		{
+			name:     "path style",
+			cfg:      Config{Bucket: "loans", Prefix: "uploads", PathStyle: true},
+			wantHost: "127.0.0.1",
+			wantKey:  "loans/uploads/.staging/",
+		},
+		{
+			name:     "virtual host",
+			cfg:      Config{Endpoint: "s3.test", Bucket: "loans", Prefix: "/docs/"},
+			wantHost: "loans.s3.test",
+			wantKey:  "loans/docs/.staging/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, fake := newTestStorage(t, tt.cfg)
+
+			name, err := storage.Save(context.Background(), "proof.jpg", strings.NewReader("jpeg data"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.HasSuffix(name, ".jpg") || strings.Contains(name, "/") {
+				t.Errorf("expected a bare .jpg name, got %q", name)
+			}
+
+			data, ok := fake.object(tt.wantKey + name)
+			if !ok {
+				t.Fatalf("expected object %q to be stored", tt.wantKey+name)
+			}
+			if string(data) != "jpeg data" {
+				t.Errorf("expected stored data %q, got %q", "jpeg data", data)
+			}
+			if got := fake.types[tt.wantKey+name]; got != "image/jpeg" {
+				t.Errorf("expected content type image/jpeg, got %q", got)
+			}
+			if fake.hosts[0] != tt.wantHost {
+				t.Errorf("expected request to host %q, got %q", tt.wantHost, fake.hosts[0])
+			}
+		})
+	}
+}
+
+func TestS3StorageSaveMultipart(t *testing.T) {
+	storage, fake := newTestStorage(t, Config{Bucket: "loans", PathStyle: true})
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), (2*DefaultPartSize+1024)/16)
+	name, err := storage.Save(context.Background(), "agreement.pdf", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, ok := fake.object("loans/.staging/" + name)
+	if !ok {
+		t.Fatal("expected object to be stored")
+	}
+	if !bytes.Equal(stored, data) {
+		t.Errorf("expected %d bytes to round-trip, got %d", len(data), len(stored))
+	}
+	if fake.parts != 3 {
+		t.Errorf("expected 3 parts, got %d", fake.parts)
+	}
+}
+
+func TestS3StorageSignedURL(t *testing.T) {
+	storage, fake := newTestStorage(t, Config{Endpoint: "s3.test", Bucket: "loans", Prefix: "docs"})
+
+	name, err := storage.Save(context.Background(), "agreement.pdf", strings.NewReader("pdf data"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := storage.Promote(context.Background(), name); err != nil {
+		t.Fatalf("failed to promote: %v", err)
+	}
+
+	signed, err := storage.SignedURL(context.Background(), name, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("invalid URL %q: %v", signed, err)
+	}
+	if u.Host != "loans.s3.test" || u.Path != "/docs/"+name {
+		t.Errorf("expected URL for loans.s3.test/docs/%s, got %q", name, signed)
+	}
+	if got := u.Query().Get("X-Amz-Expires"); got != "900" {
+		t.Errorf("expected X-Amz-Expires 900, got %q", got)
+	}
+	if u.Query().Get("X-Amz-Signature") == "" {
+		t.Error("expected URL to be signed")
+	}
+
+	if data, _ := fake.object("loans/docs/" + name); string(data) != "pdf data" {
+		t.Errorf("expected object to be stored under the signed key, got %q", data)
+	}
+
+	if _, err := storage.SignedURL(context.Background(), name, 8*24*time.Hour); err == nil {
+		t.Error("expected error for expiry beyond 7 days")
+	}
+}
+
+func TestS3StorageAreas(t *testing.T) {
+	ctx := context.Background()
+	store, fake := newTestStorage(t, Config{Bucket: "loans", Prefix: "docs", PathStyle: true})
+
+	save := func(name string) string {
+		t.Helper()
+		key, err := store.Save(ctx, name, strings.NewReader(name))
+		if err != nil {
+			t.Fatalf("failed to save: %v", err)
+		}
+		return key
+	}
+	committed, staged, discarded, quarantined := save("a.pdf"), save("b.pdf"), save("c.pdf"), save("d.pdf")
+	fake.objects["loans/docs/.healthcheck-1"] = []byte("ok")
+
+	for _, key := range []string{committed, quarantined} {
+		if err := store.Promote(ctx, key); err != nil {
+			t.Fatalf("failed to promote: %v", err)
+		}
+	}
+	if err := store.Discard(ctx, discarded); err != nil {
+		t.Fatalf("failed to discard: %v", err)
+	}
+	if err := store.Quarantine(ctx, storage.Committed, quarantined); err != nil {
+		t.Fatalf("failed to quarantine: %v", err)
+	}
+
+	reader, err := store.Open(ctx, committed)
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(data) != "a.pdf" {
+		t.Errorf("expected committed file to read %q, got %q", "a.pdf", data)
+	}
+	if _, err := store.Open(ctx, staged); err == nil {
+		t.Error("expected error opening a staged file")
+	}
+
+	tests := []struct {
+		area     storage.Area
+		wantKey  string
+		wantData string
+	}{
+		{area: storage.Staging, wantKey: staged, wantData: "b.pdf"},
+		{area: storage.Committed, wantKey: committed, wantData: "a.pdf"},
+		{area: storage.Quarantined, wantKey: quarantined, wantData: "d.pdf"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.area), func(t *testing.T) {
+			var keys []string
+			err := store.List(ctx, tt.area, func(object storage.Object) error {
+				keys = append(keys, object.Key)
+				if object.Size != int64(len(tt.wantData)) || object.ModTime.IsZero() {
+					t.Errorf("expected size %d and a modification time, got %+v", len(tt.wantData), object)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(keys) != 1 || keys[0] != tt.wantKey {
+				t.Errorf("expected [%s], got %v", tt.wantKey, keys)
+			}
+		})
+	}
+}
+
+func TestS3StorageCheckWritable(t *testing.T) {
+	storage, fake := newTestStorage(t, Config{Bucket: "loans", PathStyle: true})
+
+	if err := storage.CheckWritable(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.objects) != 0 {
+		t.Errorf("expected healthcheck object to be removed, got %d objects", len(fake.objects))
+	}
+}
+
+func TestNewS3StorageRejectsSmallParts(t *testing.T) {
+	if _, err := NewS3Storage(Config{Endpoint: "s3.test", Bucket: "loans", PartSize: 1024}); err == nil {
+		t.Error("expected error for part size below the S3 minimum")
+	}
+}
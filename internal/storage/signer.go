@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/agunghallmanmaliki/amartha/pkg/httperror"
+)
+
+// URLSigner signs the download URLs of files the application serves itself
+// under /uploads/: an expiry and an HMAC-SHA256 over the key and expiry.
+type URLSigner struct {
+	baseURL string
+	key     []byte
+}
+
+// NewURLSigner signs URLs under baseURL/uploads/ with key, which must be
+// shared by every instance serving them.
+func NewURLSigner(baseURL string, key []byte) (*URLSigner, error) {
+	if len(key) == 0 {
+		return nil, errors.New("signing key is required")
+	}
+	return &URLSigner{baseURL: baseURL, key: key}, nil
+}
+
+// URL returns baseURL/uploads/<key> valid until ttl has elapsed.
+func (s *URLSigner) URL(key string, ttl time.Duration) string {
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(key, expires))
+	return fmt.Sprintf("%s/uploads/%s?%s", s.baseURL, url.PathEscape(key), query.Encode())
+}
+
+// Verify checks the signature and expiry r carries for key and returns how
+// long the URL remains valid.
+func (s *URLSigner) Verify(r *http.Request, key string) (time.Duration, *httperror.HTTPError) {
+	expires := r.URL.Query().Get("expires")
+	signature := r.URL.Query().Get("signature")
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return 0, httperror.New(http.StatusForbidden, "INVALID_SIGNATURE", "Download URL signature is invalid")
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	remaining := time.Until(time.Unix(expiresAt, 0))
+	if err != nil || remaining <= 0 {
+		return 0, httperror.New(http.StatusForbidden, "URL_EXPIRED", "Download URL has expired")
+	}
+	return remaining, nil
+}
+
+func (s *URLSigner) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -3,9 +3,65 @@ package storage
 import (
 	"context"
 	"io"
+	"time"
+)
+
+// Area is a part of storage a file moves through. Uploads are saved to
+// Staging and promoted to Committed once the database rows referencing them
+// are committed; only committed files are served.
+type Area string
+
+const (
+	Staging     Area = "staging"
+	Committed   Area = "committed"
+	Quarantined Area = "quarantine"
 )
 
 type Storage interface {
-	Save(ctx context.Context, path string, reader io.Reader) (string, error)
-	GetURL(path string) string
+	// Save stages the file under a new key that keeps the extension of name
+	// and returns the key.
+	Save(ctx context.Context, name string, reader io.Reader) (string, error)
+
+	// Promote moves a staged file to Committed under the same key.
+	Promote(ctx context.Context, key string) error
+
+	// Discard removes a staged file that will not be committed.
+	Discard(ctx context.Context, key string) error
+
+	// Open reads the committed file stored under key.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// SignedURL returns a URL that downloads the committed file stored under
+	// key until ttl has elapsed.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Object describes a stored file.
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Inventory is a Storage whose contents can be listed, so that files no
+// database row references can be found and removed.
+type Inventory interface {
+	Storage
+
+	// List calls fn for every file in area, in no particular order.
+	List(ctx context.Context, area Area, fn func(Object) error) error
+
+	// Delete removes the file stored under key in area.
+	Delete(ctx context.Context, area Area, key string) error
+
+	// Quarantine moves a staged or committed file aside so it is never
+	// served but can still be inspected or restored.
+	Quarantine(ctx context.Context, from Area, key string) error
+}
+
+// Backend is a storage backend that can be reconciled and can report
+// whether it accepts writes.
+type Backend interface {
+	Inventory
+	CheckWritable(ctx context.Context) error
 }
@@ -0,0 +1,177 @@
+// Package upload checks uploaded documents against per-field policies before
+// they reach storage.
+package upload
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// sniffLen is how much of a file is read to detect its type.
+const sniffLen = 3072
+
+// headLen is how much of a file is kept for reading metadata. JPEG EXIF
+// segments are at most 64KiB and come first, after any JFIF segment.
+const headLen = 128 * 1024
+
+// extensions lists the file name extensions accepted for each type a policy
+// may allow; the first is the one files are stored with.
+var extensions = map[string][]string{
+	"image/jpeg":      {".jpg", ".jpeg", ".jpe"},
+	"image/png":       {".png"},
+	"image/webp":      {".webp"},
+	"image/heic":      {".heic", ".heif"},
+	"application/pdf": {".pdf"},
+}
+
+// Policy restricts what may be uploaded for one form field.
+type Policy struct {
+	Field   string
+	MaxSize int64
+	Types   []string
+}
+
+// PictureProof is the policy for approval photos.
+func PictureProof(maxSize int64) Policy {
+	return Policy{Field: "picture_proof", MaxSize: maxSize, Types: []string{"image/jpeg", "image/png", "image/webp", "image/heic"}}
+}
+
+// SignedAgreement is the policy for signed agreements, which are scanned to
+// PDF or photographed.
+func SignedAgreement(maxSize int64) Policy {
+	return Policy{Field: "signed_agreement", MaxSize: maxSize, Types: []string{"application/pdf", "image/jpeg"}}
+}
+
+// File is an upload that passed its policy's type checks. Reading it yields
+// the whole file and fails with a *domain.ViolationError wrapping
+// domain.ErrFileTooLarge once more than MaxSize bytes have been read.
+type File struct {
+	ContentType string
+	Extension   string
+
+	policy Policy
+	reader io.Reader
+	hash   hash.Hash
+	head   []byte
+	size   int64
+	err    error
+}
+
+// Open detects the type of the file in r from its first bytes and checks it
+// against the policy. filename is the client's name for the file; if it has
+// an extension, it must match the detected type. Violations are returned as
+// a *domain.ViolationError.
+func (p Policy) Open(filename string, r io.Reader) (*File, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	head = head[:n]
+
+	detected := mimetype.Detect(head)
+	contentType := ""
+	for _, t := range p.Types {
+		if detected.Is(t) {
+			contentType = t
+			break
+		}
+	}
+	if contentType == "" {
+		return nil, p.violation(domain.ErrUnsupportedFileType, "mime", strings.Join(p.Types, ","),
+			fmt.Sprintf("%s must be one of %s; got %s", p.Field, strings.Join(p.Types, ", "), detected.String()))
+	}
+
+	allowed := extensions[contentType]
+	if ext := strings.ToLower(filepath.Ext(filename)); ext != "" && !slices.Contains(allowed, ext) {
+		return nil, p.violation(domain.ErrFileExtensionMismatch, "extension", strings.Join(allowed, ","),
+			fmt.Sprintf("%s is %s but its name ends in %s", p.Field, contentType, ext))
+	}
+
+	f := &File{
+		ContentType: contentType,
+		Extension:   allowed[0],
+		policy:      p,
+		reader:      io.MultiReader(bytes.NewReader(head), r),
+		hash:        sha256.New(),
+	}
+	return f, nil
+}
+
+func (f *File) Read(b []byte) (int, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	n, err := f.reader.Read(b)
+	f.hash.Write(b[:n])
+	if room := headLen - len(f.head); room > 0 {
+		f.head = append(f.head, b[:min(n, room)]...)
+	}
+	f.size += int64(n)
+	if f.size > f.policy.MaxSize {
+		f.err = f.policy.TooLarge()
+		return n, f.err
+	}
+	return n, err
+}
+
+// Name is a file name with the extension for the detected type, to pass to
+// storage.Storage.Save.
+func (f *File) Name() string {
+	return f.policy.Field + f.Extension
+}
+
+// Size is the number of bytes read so far; the file's size once it has been
+// read to the end.
+func (f *File) Size() int64 {
+	return f.size
+}
+
+// Head returns up to the first 128KiB read, enough to find the metadata
+// at the start of a file.
+func (f *File) Head() []byte {
+	return f.head
+}
+
+// Err returns the violation that stopped reading, if any. Storage backends
+// may not wrap read errors, so callers check Err after a failed save.
+func (f *File) Err() error {
+	return f.err
+}
+
+// Stored returns the file's record, including the checksum of everything
+// read, after it was saved under key.
+func (f *File) Stored(key string) domain.StoredFile {
+	return domain.StoredFile{
+		Key:         key,
+		ContentType: f.ContentType,
+		Size:        f.size,
+		SHA256:      hex.EncodeToString(f.hash.Sum(nil)),
+	}
+}
+
+// TooLarge returns the violation for a file over MaxSize, for callers that
+// detect it before the file is read, e.g. from a request body limit.
+func (p Policy) TooLarge() error {
+	return p.violation(domain.ErrFileTooLarge, "max_size", strconv.FormatInt(p.MaxSize, 10),
+		fmt.Sprintf("%s must be at most %d bytes", p.Field, p.MaxSize))
+}
+
+func (p Policy) violation(err error, rule, param, message string) error {
+	return &domain.ViolationError{
+		Err:        err,
+		Violations: []domain.Violation{{Field: p.Field, Rule: rule, Param: param, Message: message}},
+	}
+}
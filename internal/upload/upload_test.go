@@ -0,0 +1,123 @@
+package upload
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+)
+
+var (
+	jpegData = append([]byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00}, bytes.Repeat([]byte{0}, 64)...)
+	pngData  = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+	pdfData  = []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n1 0 obj\n<<>>\nendobj\n")
+)
+
+func TestPolicyOpen(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          Policy
+		filename        string
+		data            []byte
+		wantErr         error
+		wantRule        string
+		wantContentType string
+		wantName        string
+	}{
+		{name: "jpeg proof", policy: PictureProof(1024), filename: "proof.JPG", data: jpegData, wantContentType: "image/jpeg", wantName: "picture_proof.jpg"},
+		{name: "png proof", policy: PictureProof(1024), filename: "proof.png", data: pngData, wantContentType: "image/png", wantName: "picture_proof.png"},
+		{name: "no extension", policy: PictureProof(1024), filename: "proof", data: jpegData, wantContentType: "image/jpeg", wantName: "picture_proof.jpg"},
+		{name: "pdf agreement", policy: SignedAgreement(1024), filename: "agreement.pdf", data: pdfData, wantContentType: "application/pdf", wantName: "signed_agreement.pdf"},
+		{name: "jpeg agreement", policy: SignedAgreement(1024), filename: "agreement.jpeg", data: jpegData, wantContentType: "image/jpeg", wantName: "signed_agreement.jpg"},
+		{name: "pdf proof", policy: PictureProof(1024), filename: "proof.pdf", data: pdfData, wantErr: domain.ErrUnsupportedFileType, wantRule: "mime"},
+		{name: "png agreement", policy: SignedAgreement(1024), filename: "agreement.png", data: pngData, wantErr: domain.ErrUnsupportedFileType, wantRule: "mime"},
+		{name: "text disguised as jpeg", policy: PictureProof(1024), filename: "proof.jpg", data: []byte("hello"), wantErr: domain.ErrUnsupportedFileType, wantRule: "mime"},
+		{name: "pdf named jpg", policy: SignedAgreement(1024), filename: "agreement.jpg", data: pdfData, wantErr: domain.ErrFileExtensionMismatch, wantRule: "extension"},
+		{name: "jpeg named exe", policy: PictureProof(1024), filename: "proof.exe", data: jpegData, wantErr: domain.ErrFileExtensionMismatch, wantRule: "extension"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.policy.Open(tt.filename, bytes.NewReader(tt.data))
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				var verr *domain.ViolationError
+				if !errors.As(err, &verr) || verr.Violations[0].Field != tt.policy.Field || verr.Violations[0].Rule != tt.wantRule {
+					t.Errorf("expected %s violation on %s, got %v", tt.wantRule, tt.policy.Field, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if f.ContentType != tt.wantContentType {
+				t.Errorf("expected content type %q, got %q", tt.wantContentType, f.ContentType)
+			}
+			if f.Name() != tt.wantName {
+				t.Errorf("expected name %q, got %q", tt.wantName, f.Name())
+			}
+			data, err := io.ReadAll(f)
+			if err != nil {
+				t.Fatalf("unexpected read error: %v", err)
+			}
+			if !bytes.Equal(data, tt.data) {
+				t.Error("expected the whole file to be readable after sniffing")
+			}
+			if stored := f.Stored("key"); stored.Size != int64(len(tt.data)) || stored.ContentType != tt.wantContentType {
+				t.Errorf("expected stored size %d and type %q, got %+v", len(tt.data), tt.wantContentType, stored)
+			}
+			if sum := sha256.Sum256(tt.data); f.Stored("key").SHA256 != hex.EncodeToString(sum[:]) {
+				t.Errorf("expected checksum %x, got %s", sum, f.Stored("key").SHA256)
+			}
+			if !bytes.Equal(f.Head(), tt.data) {
+				t.Error("expected the head to hold the whole small file")
+			}
+		})
+	}
+}
+
+func TestFileMaxSize(t *testing.T) {
+	large := append(append([]byte{}, pdfData...), bytes.Repeat([]byte("x"), 5000)...)
+
+	tests := []struct {
+		name    string
+		maxSize int64
+		wantErr bool
+	}{
+		{name: "at limit", maxSize: int64(len(large))},
+		{name: "over limit within sniffed bytes", maxSize: 100, wantErr: true},
+		{name: "over limit after sniffed bytes", maxSize: int64(len(large)) - 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := SignedAgreement(tt.maxSize).Open("agreement.pdf", bytes.NewReader(large))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, err = io.Copy(io.Discard, f)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrFileTooLarge) || !errors.Is(f.Err(), domain.ErrFileTooLarge) {
+					t.Errorf("expected ErrFileTooLarge, got %v", err)
+				}
+				if err != nil && !strings.Contains(err.Error(), "at most") {
+					t.Errorf("expected a size message, got %q", err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
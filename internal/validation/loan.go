@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/agunghallmanmaliki/amartha/internal/service"
+	"github.com/google/uuid"
+)
+
+// Loan is a loan proposal, whichever API it arrived through.
+type Loan struct {
+	BorrowerID      string         `json:"borrower_id" validate:"required"`
+	BorrowerName    string         `json:"borrower_name" validate:"max=255"`
+	Notes           string         `json:"notes" validate:"max=2000"`
+	PrincipalAmount int64          `json:"principal_amount" validate:"required,gt=0"`
+	Rate            domain.Decimal `json:"rate" validate:"required,gte=0"`
+	ROI             domain.Decimal `json:"roi" validate:"required,gte=0"`
+	ProductID       string         `json:"product_id" validate:"required,uuid"`
+	TenorMonths     int            `json:"tenor_months" validate:"required,gt=0"`
+	// BorrowerLocation is where the field validator's picture proof is
+	// expected to be taken.
+	BorrowerLocation *Location `json:"borrower_location"`
+}
+
+// Location is a position in decimal degrees.
+type Location struct {
+	Latitude  *float64 `json:"latitude" validate:"required,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" validate:"required,gte=-180,lte=180"`
+}
+
+// GeoPoint converts a location that has passed validation.
+func (l *Location) GeoPoint() *domain.GeoPoint {
+	if l == nil {
+		return nil
+	}
+	return &domain.GeoPoint{Latitude: *l.Latitude, Longitude: *l.Longitude}
+}
+
+// ToInput converts a loan that has passed validation.
+func (r Loan) ToInput() service.CreateLoanInput {
+	productID, _ := uuid.Parse(r.ProductID)
+	return service.CreateLoanInput{
+		BorrowerID:       r.BorrowerID,
+		BorrowerName:     r.BorrowerName,
+		Notes:            r.Notes,
+		PrincipalAmount:  r.PrincipalAmount,
+		Rate:             r.Rate,
+		ROI:              r.ROI,
+		ProductID:        productID,
+		TenorMonths:      r.TenorMonths,
+		BorrowerLocation: r.BorrowerLocation.GeoPoint(),
+	}
+}
+
+// Investment is an investment in a loan.
+type Investment struct {
+	InvestorID string `json:"investor_id" validate:"required"`
+	Amount     int64  `json:"amount" validate:"required,gt=0"`
+}
@@ -0,0 +1,33 @@
+// Package validation holds the request rules shared by the REST and gRPC
+// APIs, so that both reject the same input with the same field errors.
+package validation
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/go-playground/validator/v10"
+)
+
+// New returns the validator for requests. Fields are reported by their JSON
+// names, which the gRPC messages share, so validation problems refer to what
+// the client sent. Decimals are checked as numbers, so rules such as gte=0
+// apply to them.
+func New() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(domain.Decimal).Float64()
+	}, domain.Decimal{})
+	return v
+}
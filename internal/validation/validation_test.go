@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/domain"
+	"github.com/go-playground/validator/v10"
+)
+
+func TestLoan(t *testing.T) {
+	latitude, longitude := -6.2, 106.8
+	outOfRange := 91.0
+	valid := func() Loan {
+		return Loan{
+			BorrowerID:       "borrower-1",
+			PrincipalAmount:  1000000,
+			Rate:             domain.MustParseDecimal("0.15"),
+			ROI:              domain.MustParseDecimal("0.12"),
+			ProductID:        "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+			TenorMonths:      12,
+			BorrowerLocation: &Location{Latitude: &latitude, Longitude: &longitude},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Loan)
+		field  string
+		rule   string
+	}{
+		{"valid", func(*Loan) {}, "", ""},
+		{"missing borrower", func(l *Loan) { l.BorrowerID = "" }, "borrower_id", "required"},
+		{"negative rate", func(l *Loan) { l.Rate = domain.MustParseDecimal("-0.1") }, "rate", "gte"},
+		{"bad product id", func(l *Loan) { l.ProductID = "micro" }, "product_id", "uuid"},
+		{"latitude out of range", func(l *Loan) { l.BorrowerLocation.Latitude = &outOfRange }, "latitude", "lte"},
+		{"latitude missing", func(l *Loan) { l.BorrowerLocation.Latitude = nil }, "latitude", "required"},
+	}
+
+	v := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := valid()
+			tt.modify(&loan)
+
+			err := v.Struct(loan)
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if input := loan.ToInput(); input.ProductID.String() != loan.ProductID || input.BorrowerLocation == nil {
+					t.Errorf("expected the product and location to be converted, got %+v", input)
+				}
+				return
+			}
+
+			errs, ok := err.(validator.ValidationErrors)
+			if !ok || len(errs) != 1 {
+				t.Fatalf("expected one validation error, got %v", err)
+			}
+			if errs[0].Field() != tt.field || errs[0].Tag() != tt.rule {
+				t.Errorf("expected %s %s, got %s %s", tt.field, tt.rule, errs[0].Field(), errs[0].Tag())
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_disbursements_field_officer_id_trgm;
+DROP INDEX IF EXISTS idx_approvals_field_validator_id_trgm;
+DROP INDEX IF EXISTS idx_loans_notes_trgm;
+DROP INDEX IF EXISTS idx_loans_borrower_name_trgm;
+DROP INDEX IF EXISTS idx_loans_borrower_id_trgm;
+DROP INDEX IF EXISTS idx_loans_search_vector;
+
+ALTER TABLE loans
+    DROP COLUMN IF EXISTS search_vector,
+    DROP COLUMN IF EXISTS notes,
+    DROP COLUMN IF EXISTS borrower_name;
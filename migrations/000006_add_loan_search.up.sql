@@ -0,0 +1,17 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE loans
+    ADD COLUMN borrower_name VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN notes TEXT NOT NULL DEFAULT '',
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', borrower_id), 'A') ||
+        setweight(to_tsvector('simple', borrower_name), 'A') ||
+        setweight(to_tsvector('simple', notes), 'B')
+    ) STORED;
+
+CREATE INDEX idx_loans_search_vector ON loans USING GIN (search_vector);
+CREATE INDEX idx_loans_borrower_id_trgm ON loans USING GIN (borrower_id gin_trgm_ops);
+CREATE INDEX idx_loans_borrower_name_trgm ON loans USING GIN (borrower_name gin_trgm_ops);
+CREATE INDEX idx_loans_notes_trgm ON loans USING GIN (notes gin_trgm_ops);
+CREATE INDEX idx_approvals_field_validator_id_trgm ON approvals USING GIN (field_validator_id gin_trgm_ops);
+CREATE INDEX idx_disbursements_field_officer_id_trgm ON disbursements USING GIN (field_officer_id gin_trgm_ops);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_loans_product_id;
+
+ALTER TABLE loans
+    DROP COLUMN IF EXISTS tenor_months,
+    DROP COLUMN IF EXISTS product_id;
+
+DROP TABLE IF EXISTS loan_products;
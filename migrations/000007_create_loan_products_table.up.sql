@@ -0,0 +1,22 @@
+CREATE TABLE loan_products (
+    id UUID PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    min_principal BIGINT NOT NULL CHECK (min_principal > 0),
+    max_principal BIGINT NOT NULL CHECK (max_principal >= min_principal),
+    tenor_months INTEGER[] NOT NULL CHECK (cardinality(tenor_months) > 0),
+    min_rate DECIMAL(10, 4) NOT NULL CHECK (min_rate >= 0),
+    max_rate DECIMAL(10, 4) NOT NULL CHECK (max_rate >= min_rate),
+    min_roi DECIMAL(10, 4) NOT NULL CHECK (min_roi >= 0),
+    max_roi DECIMAL(10, 4) NOT NULL CHECK (max_roi >= min_roi),
+    required_documents TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE loans
+    ADD COLUMN product_id UUID REFERENCES loan_products(id) ON DELETE RESTRICT,
+    ADD COLUMN tenor_months INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_loans_product_id ON loans(product_id);
@@ -0,0 +1,4 @@
+-- Stored keys are left as they are; the original URLs cannot be rebuilt.
+ALTER TABLE loans RENAME COLUMN agreement_letter_key TO agreement_letter_url;
+ALTER TABLE disbursements RENAME COLUMN signed_agreement_key TO signed_agreement_url;
+ALTER TABLE approvals RENAME COLUMN picture_proof_key TO picture_proof_url;
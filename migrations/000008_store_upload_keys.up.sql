@@ -0,0 +1,10 @@
+-- Uploads are referenced by storage key and served through signed URLs, so
+-- existing rows keep only the object name from the URL they stored.
+ALTER TABLE approvals RENAME COLUMN picture_proof_url TO picture_proof_key;
+ALTER TABLE disbursements RENAME COLUMN signed_agreement_url TO signed_agreement_key;
+ALTER TABLE loans RENAME COLUMN agreement_letter_url TO agreement_letter_key;
+
+UPDATE approvals SET picture_proof_key = regexp_replace(picture_proof_key, '^.*/', '');
+UPDATE disbursements SET signed_agreement_key = regexp_replace(signed_agreement_key, '^.*/', '');
+UPDATE loans SET agreement_letter_key = regexp_replace(agreement_letter_key, '^.*/', '')
+WHERE agreement_letter_key IS NOT NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE disbursements
+    DROP COLUMN IF EXISTS signed_agreement_size,
+    DROP COLUMN IF EXISTS signed_agreement_content_type;
+
+ALTER TABLE approvals
+    DROP COLUMN IF EXISTS picture_proof_size,
+    DROP COLUMN IF EXISTS picture_proof_content_type;
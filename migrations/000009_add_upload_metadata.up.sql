@@ -0,0 +1,9 @@
+-- Content types are detected from the file's bytes; rows from before
+-- detection keep an empty type and zero size.
+ALTER TABLE approvals
+    ADD COLUMN picture_proof_content_type VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN picture_proof_size BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE disbursements
+    ADD COLUMN signed_agreement_content_type VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN signed_agreement_size BIGINT NOT NULL DEFAULT 0;
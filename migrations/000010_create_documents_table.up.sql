@@ -0,0 +1,30 @@
+CREATE TABLE documents (
+    id UUID PRIMARY KEY,
+    loan_id UUID NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    purpose VARCHAR(32) NOT NULL CHECK (purpose IN ('picture_proof', 'signed_agreement')),
+    version INTEGER NOT NULL CHECK (version > 0),
+    storage_key TEXT NOT NULL,
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    size BIGINT NOT NULL DEFAULT 0,
+    sha256 CHAR(64),
+    uploaded_by VARCHAR(255) NOT NULL,
+    -- Deferred so a new version can be referenced before it is inserted.
+    superseded_by UUID REFERENCES documents(id) DEFERRABLE INITIALLY DEFERRED,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (loan_id, purpose, version)
+);
+
+-- One current version per loan and purpose
+CREATE UNIQUE INDEX idx_documents_current ON documents(loan_id, purpose) WHERE superseded_by IS NULL;
+CREATE INDEX idx_documents_sha256 ON documents(sha256) WHERE sha256 IS NOT NULL;
+CREATE INDEX idx_documents_storage_key ON documents(storage_key);
+
+-- Register existing uploads as first versions. Their checksums were never
+-- recorded, so they are neither deduplicated against nor verified.
+INSERT INTO documents (id, loan_id, purpose, version, storage_key, content_type, size, uploaded_by, created_at)
+SELECT gen_random_uuid(), loan_id, 'picture_proof', 1, picture_proof_key, picture_proof_content_type, picture_proof_size, field_validator_id, approved_at
+FROM approvals;
+
+INSERT INTO documents (id, loan_id, purpose, version, storage_key, content_type, size, uploaded_by, created_at)
+SELECT gen_random_uuid(), loan_id, 'signed_agreement', 1, signed_agreement_key, signed_agreement_content_type, signed_agreement_size, field_officer_id, disbursed_at
+FROM disbursements;
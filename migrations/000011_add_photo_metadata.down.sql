@@ -0,0 +1,11 @@
+ALTER TABLE approvals
+    DROP COLUMN IF EXISTS photo_device_model,
+    DROP COLUMN IF EXISTS photo_device_make,
+    DROP COLUMN IF EXISTS photo_longitude,
+    DROP COLUMN IF EXISTS photo_latitude,
+    DROP COLUMN IF EXISTS photo_captured_at;
+
+ALTER TABLE loans
+    DROP CONSTRAINT IF EXISTS loans_borrower_location_check,
+    DROP COLUMN IF EXISTS borrower_longitude,
+    DROP COLUMN IF EXISTS borrower_latitude;
@@ -0,0 +1,14 @@
+-- Where the borrower lives, for checking where picture proofs were taken.
+-- Loans from before locations were recorded have none.
+ALTER TABLE loans
+    ADD COLUMN borrower_latitude DOUBLE PRECISION,
+    ADD COLUMN borrower_longitude DOUBLE PRECISION,
+    ADD CONSTRAINT loans_borrower_location_check CHECK ((borrower_latitude IS NULL) = (borrower_longitude IS NULL));
+
+-- What the picture proof's EXIF records; empty when it records nothing.
+ALTER TABLE approvals
+    ADD COLUMN photo_captured_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN photo_latitude DOUBLE PRECISION,
+    ADD COLUMN photo_longitude DOUBLE PRECISION,
+    ADD COLUMN photo_device_make VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN photo_device_model VARCHAR(255) NOT NULL DEFAULT '';
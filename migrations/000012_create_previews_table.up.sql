@@ -0,0 +1,14 @@
+-- Previews are keyed by the file they preview, so a deduplicated upload
+-- shares its preview with every document that stores the same file.
+CREATE TABLE previews (
+    source_key TEXT PRIMARY KEY,
+    storage_key TEXT NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    page_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_previews_storage_key ON previews(storage_key);
@@ -0,0 +1,12 @@
+-- Data keys of encrypted files, wrapped by a master key. Keeping them here
+-- rather than in the files lets a master key be rotated without rewriting
+-- any file.
+CREATE TABLE data_keys (
+    storage_key TEXT PRIMARY KEY,
+    master_key_id VARCHAR(64) NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    rotated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_data_keys_master_key_id ON data_keys(master_key_id);
@@ -0,0 +1,6 @@
+ALTER TABLE loans
+    DROP CONSTRAINT IF EXISTS loans_agreement_signature_check,
+    DROP COLUMN IF EXISTS agreement_signed_at,
+    DROP COLUMN IF EXISTS agreement_signer_fingerprint,
+    DROP COLUMN IF EXISTS agreement_signature,
+    DROP COLUMN IF EXISTS agreement_sha256;
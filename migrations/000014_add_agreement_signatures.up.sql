@@ -0,0 +1,14 @@
+-- Our detached signature over the agreement letter: the letter's SHA-256,
+-- the signature of that digest and the fingerprint of the certificate (or
+-- public key) that made it. Letters issued while signing was not configured
+-- have none.
+ALTER TABLE loans
+    ADD COLUMN agreement_sha256 VARCHAR(64),
+    ADD COLUMN agreement_signature BYTEA,
+    ADD COLUMN agreement_signer_fingerprint VARCHAR(71),
+    ADD COLUMN agreement_signed_at TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT loans_agreement_signature_check CHECK (
+        (agreement_signature IS NULL) = (agreement_sha256 IS NULL)
+        AND (agreement_signature IS NULL) = (agreement_signer_fingerprint IS NULL)
+        AND (agreement_signature IS NULL) = (agreement_signed_at IS NULL)
+    );
@@ -2,24 +2,54 @@ package httperror
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"github.com/go-playground/validator/v10"
 )
 
+// ContentType is the media type of RFC 7807 problem details.
+const ContentType = "application/problem+json"
+
+// TypeBaseURI prefixes every problem type. Types are relative URI references
+// resolved against the API host, e.g. /problems/validation-error.
+const TypeBaseURI = "/problems/"
+
+// HTTPError is an RFC 7807 problem details object. Code and RequestID are
+// extension members kept for clients that switch on the machine-readable code.
 type HTTPError struct {
-	StatusCode int    `json:"-"`
-	Code       string `json:"code"`
-	Message    string `json:"message"`
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes a single failed validation rule.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
 }
 
 func (e *HTTPError) Error() string {
-	return e.Message
+	return e.Detail
 }
 
-func New(statusCode int, code, message string) *HTTPError {
+func New(status int, code, detail string) *HTTPError {
 	return &HTTPError{
-		StatusCode: statusCode,
-		Code:       code,
-		Message:    message,
+		Type:   TypeBaseURI + strings.ReplaceAll(strings.ToLower(code), "_", "-"),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
 	}
 }
 
@@ -27,20 +57,98 @@ func BadRequest(message string) *HTTPError {
 	return New(http.StatusBadRequest, "BAD_REQUEST", message)
 }
 
+func Forbidden(message string) *HTTPError {
+	return New(http.StatusForbidden, "FORBIDDEN", message)
+}
+
 func NotFound(message string) *HTTPError {
 	return New(http.StatusNotFound, "NOT_FOUND", message)
 }
 
+func MethodNotAllowed(message string) *HTTPError {
+	return New(http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", message)
+}
+
 func UnprocessableEntity(message string) *HTTPError {
 	return New(http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", message)
 }
 
+func TooManyRequests(message string) *HTTPError {
+	return New(http.StatusTooManyRequests, "RATE_LIMITED", message)
+}
+
 func InternalServerError(message string) *HTTPError {
 	return New(http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", message)
 }
 
+// Validation builds a VALIDATION_ERROR problem listing each field error.
+func Validation(fieldErrors ...FieldError) *HTTPError {
+	e := New(http.StatusBadRequest, "VALIDATION_ERROR", "Request validation failed")
+	e.Errors = fieldErrors
+	return e
+}
+
+// Required is the field error reported for a missing form value or file.
+func Required(field string) FieldError {
+	return FieldError{Field: field, Rule: "required", Message: field + " is required"}
+}
+
+// FromValidator converts go-playground/validator output into a validation
+// problem. Field names are whatever the validator's tag name func reports.
+func FromValidator(err error) *HTTPError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return New(http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed")
+	}
+
+	fieldErrors := make([]FieldError, 0, len(validationErrs))
+	for _, e := range validationErrs {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   e.Field(),
+			Rule:    e.Tag(),
+			Param:   e.Param(),
+			Message: fieldMessage(e),
+		})
+	}
+	return Validation(fieldErrors...)
+}
+
+func fieldMessage(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return e.Field() + " is required"
+	case "gt":
+		return e.Field() + " must be greater than " + e.Param()
+	case "gte":
+		return e.Field() + " must be greater than or equal to " + e.Param()
+	case "lt":
+		return e.Field() + " must be less than " + e.Param()
+	case "lte":
+		return e.Field() + " must be less than or equal to " + e.Param()
+	case "max":
+		if e.Kind() == reflect.String {
+			return e.Field() + " must be at most " + e.Param() + " characters long"
+		}
+		return e.Field() + " must be at most " + e.Param()
+	case "min":
+		if e.Kind() == reflect.String {
+			return e.Field() + " must be at least " + e.Param() + " characters long"
+		}
+		return e.Field() + " must be at least " + e.Param()
+	case "oneof":
+		return e.Field() + " must be one of: " + e.Param()
+	default:
+		return e.Field() + " is invalid"
+	}
+}
+
 func WriteError(w http.ResponseWriter, err *HTTPError) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(err.StatusCode)
+	if err.RequestID == "" {
+		// Echo the request id the request-id middleware set on the response
+		err.RequestID = w.Header().Get(requestctx.HeaderRequestID)
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(err.Status)
 	json.NewEncoder(w).Encode(err)
 }
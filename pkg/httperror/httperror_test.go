@@ -0,0 +1,145 @@
+package httperror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agunghallmanmaliki/amartha/internal/requestctx"
+	"github.com/go-playground/validator/v10"
+)
+
+type loanRequest struct {
+	BorrowerID string  `json:"borrower_id" validate:"required"`
+	Name       string  `json:"name" validate:"max=5"`
+	Principal  float64 `json:"principal" validate:"gt=0"`
+	Rate       float64 `json:"rate" validate:"lte=100"`
+	Status     string  `json:"status" validate:"oneof=proposed approved"`
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		return name
+	})
+	return v
+}
+
+func TestFromValidator(t *testing.T) {
+	err := newValidator().Struct(loanRequest{
+		Name:      "too long",
+		Principal: 0,
+		Rate:      120,
+		Status:    "closed",
+	})
+
+	problem := FromValidator(err)
+	if problem.Status != http.StatusBadRequest || problem.Code != "VALIDATION_ERROR" {
+		t.Errorf("expected 400 VALIDATION_ERROR, got %d %s", problem.Status, problem.Code)
+	}
+
+	want := []FieldError{
+		{Field: "borrower_id", Rule: "required", Message: "borrower_id is required"},
+		{Field: "name", Rule: "max", Param: "5", Message: "name must be at most 5 characters long"},
+		{Field: "principal", Rule: "gt", Param: "0", Message: "principal must be greater than 0"},
+		{Field: "rate", Rule: "lte", Param: "100", Message: "rate must be less than or equal to 100"},
+		{Field: "status", Rule: "oneof", Param: "proposed approved", Message: "status must be one of: proposed approved"},
+	}
+	if !reflect.DeepEqual(problem.Errors, want) {
+		t.Errorf("expected errors %+v, got %+v", want, problem.Errors)
+	}
+}
+
+func TestFromValidatorOtherError(t *testing.T) {
+	problem := FromValidator(errors.New("not a validation error"))
+	if problem.Status != http.StatusBadRequest || problem.Code != "VALIDATION_ERROR" || len(problem.Errors) != 0 {
+		t.Errorf("expected a bare validation problem, got %+v", problem)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     *HTTPError
+		status  int
+		typeURI string
+		title   string
+		code    string
+	}{
+		{"bad request", BadRequest("bad"), http.StatusBadRequest, "/problems/bad-request", "Bad Request", "BAD_REQUEST"},
+		{"not found", NotFound("missing"), http.StatusNotFound, "/problems/not-found", "Not Found", "NOT_FOUND"},
+		{"rate limited", TooManyRequests("slow down"), http.StatusTooManyRequests, "/problems/rate-limited", "Too Many Requests", "RATE_LIMITED"},
+		{"validation", Validation(Required("file")), http.StatusBadRequest, "/problems/validation-error", "Bad Request", "VALIDATION_ERROR"},
+		{"custom", New(http.StatusConflict, "LOAN_STATE_CONFLICT", "conflict"), http.StatusConflict, "/problems/loan-state-conflict", "Conflict", "LOAN_STATE_CONFLICT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Status != tt.status || tt.err.Type != tt.typeURI || tt.err.Title != tt.title || tt.err.Code != tt.code {
+				t.Errorf("expected %d %s %q %s, got %d %s %q %s",
+					tt.status, tt.typeURI, tt.title, tt.code,
+					tt.err.Status, tt.err.Type, tt.err.Title, tt.err.Code)
+			}
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name          string
+		headerID      string
+		requestID     string
+		wantRequestID string
+	}{
+		{"request id from header", "req-123", "", "req-123"},
+		{"request id already set", "req-123", "req-456", "req-456"},
+		{"no request id", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if tt.headerID != "" {
+				rec.Header().Set(requestctx.HeaderRequestID, tt.headerID)
+			}
+			problem := Validation(Required("picture_proof"))
+			problem.RequestID = tt.requestID
+			WriteError(rec, problem)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != ContentType {
+				t.Errorf("expected content type %s, got %s", ContentType, got)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if body["type"] != "/problems/validation-error" || body["status"] != float64(http.StatusBadRequest) {
+				t.Errorf("expected validation-error type and status 400, got %v", body)
+			}
+			requestID, ok := body["request_id"]
+			if tt.wantRequestID == "" {
+				if ok {
+					t.Errorf("expected no request_id, got %v", requestID)
+				}
+			} else if requestID != tt.wantRequestID {
+				t.Errorf("expected request_id %s, got %v", tt.wantRequestID, requestID)
+			}
+			errs, _ := body["errors"].([]any)
+			if len(errs) != 1 {
+				t.Fatalf("expected one field error, got %v", body["errors"])
+			}
+			if field := errs[0].(map[string]any); field["field"] != "picture_proof" || field["rule"] != "required" {
+				t.Errorf("expected picture_proof required, got %v", field)
+			}
+		})
+	}
+}
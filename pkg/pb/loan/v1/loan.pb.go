@@ -0,0 +1,1496 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: loan/v1/loan.proto
+
+package loanv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LoanState int32
+
+const (
+	LoanState_LOAN_STATE_UNSPECIFIED LoanState = 0
+	LoanState_LOAN_STATE_PROPOSED    LoanState = 1
+	LoanState_LOAN_STATE_APPROVED    LoanState = 2
+	LoanState_LOAN_STATE_INVESTED    LoanState = 3
+	LoanState_LOAN_STATE_DISBURSED   LoanState = 4
+)
+
+// Enum value maps for LoanState.
+var (
+	LoanState_name = map[int32]string{
+		0: "LOAN_STATE_UNSPECIFIED",
+		1: "LOAN_STATE_PROPOSED",
+		2: "LOAN_STATE_APPROVED",
+		3: "LOAN_STATE_INVESTED",
+		4: "LOAN_STATE_DISBURSED",
+	}
+	LoanState_value = map[string]int32{
+		"LOAN_STATE_UNSPECIFIED": 0,
+		"LOAN_STATE_PROPOSED":    1,
+		"LOAN_STATE_APPROVED":    2,
+		"LOAN_STATE_INVESTED":    3,
+		"LOAN_STATE_DISBURSED":   4,
+	}
+)
+
+func (x LoanState) Enum() *LoanState {
+	p := new(LoanState)
+	*p = x
+	return p
+}
+
+func (x LoanState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LoanState) Descriptor() protoreflect.EnumDescriptor {
+	return file_loan_v1_loan_proto_enumTypes[0].Descriptor()
+}
+
+func (LoanState) Type() protoreflect.EnumType {
+	return &file_loan_v1_loan_proto_enumTypes[0]
+}
+
+func (x LoanState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LoanState.Descriptor instead.
+func (LoanState) EnumDescriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{0}
+}
+
+type Loan struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	BorrowerId      string                 `protobuf:"bytes,2,opt,name=borrower_id,json=borrowerId,proto3" json:"borrower_id,omitempty"`
+	PrincipalAmount int64                  `protobuf:"varint,3,opt,name=principal_amount,json=principalAmount,proto3" json:"principal_amount,omitempty"`
+	// Decimal string, e.g. "0.15".
+	Rate string `protobuf:"bytes,4,opt,name=rate,proto3" json:"rate,omitempty"`
+	// Decimal string, e.g. "0.12".
+	Roi   string    `protobuf:"bytes,5,opt,name=roi,proto3" json:"roi,omitempty"`
+	State LoanState `protobuf:"varint,6,opt,name=state,proto3,enum=amartha.loan.v1.LoanState" json:"state,omitempty"`
+	// REST path that redirects to a signed download URL; set once disbursed.
+	AgreementLetterUrl *string                `protobuf:"bytes,7,opt,name=agreement_letter_url,json=agreementLetterUrl,proto3,oneof" json:"agreement_letter_url,omitempty"`
+	TotalInvested      int64                  `protobuf:"varint,8,opt,name=total_invested,json=totalInvested,proto3" json:"total_invested,omitempty"`
+	RemainingAmount    int64                  `protobuf:"varint,9,opt,name=remaining_amount,json=remainingAmount,proto3" json:"remaining_amount,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt          *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	BorrowerName       string                 `protobuf:"bytes,12,opt,name=borrower_name,json=borrowerName,proto3" json:"borrower_name,omitempty"`
+	Notes              string                 `protobuf:"bytes,13,opt,name=notes,proto3" json:"notes,omitempty"`
+	// Unset for loans created before the product catalogue.
+	ProductId   *string `protobuf:"bytes,14,opt,name=product_id,json=productId,proto3,oneof" json:"product_id,omitempty"`
+	TenorMonths int32   `protobuf:"varint,15,opt,name=tenor_months,json=tenorMonths,proto3" json:"tenor_months,omitempty"`
+	// Unset when no location was given.
+	BorrowerLocation *GeoPoint `protobuf:"bytes,16,opt,name=borrower_location,json=borrowerLocation,proto3" json:"borrower_location,omitempty"`
+	// Our signature over the agreement letter; unset if it was issued unsigned.
+	AgreementSignature *AgreementSignature `protobuf:"bytes,17,opt,name=agreement_signature,json=agreementSignature,proto3" json:"agreement_signature,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Loan) Reset() {
+	*x = Loan{}
+	mi := &file_loan_v1_loan_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Loan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Loan) ProtoMessage() {}
+
+func (x *Loan) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Loan.ProtoReflect.Descriptor instead.
+func (*Loan) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Loan) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Loan) GetBorrowerId() string {
+	if x != nil {
+		return x.BorrowerId
+	}
+	return ""
+}
+
+func (x *Loan) GetPrincipalAmount() int64 {
+	if x != nil {
+		return x.PrincipalAmount
+	}
+	return 0
+}
+
+func (x *Loan) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *Loan) GetRoi() string {
+	if x != nil {
+		return x.Roi
+	}
+	return ""
+}
+
+func (x *Loan) GetState() LoanState {
+	if x != nil {
+		return x.State
+	}
+	return LoanState_LOAN_STATE_UNSPECIFIED
+}
+
+func (x *Loan) GetAgreementLetterUrl() string {
+	if x != nil && x.AgreementLetterUrl != nil {
+		return *x.AgreementLetterUrl
+	}
+	return ""
+}
+
+func (x *Loan) GetTotalInvested() int64 {
+	if x != nil {
+		return x.TotalInvested
+	}
+	return 0
+}
+
+func (x *Loan) GetRemainingAmount() int64 {
+	if x != nil {
+		return x.RemainingAmount
+	}
+	return 0
+}
+
+func (x *Loan) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Loan) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Loan) GetBorrowerName() string {
+	if x != nil {
+		return x.BorrowerName
+	}
+	return ""
+}
+
+func (x *Loan) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *Loan) GetProductId() string {
+	if x != nil && x.ProductId != nil {
+		return *x.ProductId
+	}
+	return ""
+}
+
+func (x *Loan) GetTenorMonths() int32 {
+	if x != nil {
+		return x.TenorMonths
+	}
+	return 0
+}
+
+func (x *Loan) GetBorrowerLocation() *GeoPoint {
+	if x != nil {
+		return x.BorrowerLocation
+	}
+	return nil
+}
+
+func (x *Loan) GetAgreementSignature() *AgreementSignature {
+	if x != nil {
+		return x.AgreementSignature
+	}
+	return nil
+}
+
+// A detached signature of the agreement letter's SHA-256 digest, checkable
+// against the REST signing certificate.
+type AgreementSignature struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Hex digest of the signed letter.
+	Sha256            string                 `protobuf:"bytes,1,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Signature         []byte                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	SignerFingerprint string                 `protobuf:"bytes,3,opt,name=signer_fingerprint,json=signerFingerprint,proto3" json:"signer_fingerprint,omitempty"`
+	SignedAt          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=signed_at,json=signedAt,proto3" json:"signed_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AgreementSignature) Reset() {
+	*x = AgreementSignature{}
+	mi := &file_loan_v1_loan_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgreementSignature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgreementSignature) ProtoMessage() {}
+
+func (x *AgreementSignature) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgreementSignature.ProtoReflect.Descriptor instead.
+func (*AgreementSignature) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AgreementSignature) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *AgreementSignature) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *AgreementSignature) GetSignerFingerprint() string {
+	if x != nil {
+		return x.SignerFingerprint
+	}
+	return ""
+}
+
+func (x *AgreementSignature) GetSignedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SignedAt
+	}
+	return nil
+}
+
+// A position in decimal degrees.
+type GeoPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeoPoint) Reset() {
+	*x = GeoPoint{}
+	mi := &file_loan_v1_loan_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoPoint) ProtoMessage() {}
+
+func (x *GeoPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoPoint.ProtoReflect.Descriptor instead.
+func (*GeoPoint) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GeoPoint) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *GeoPoint) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+type Investment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	LoanId        string                 `protobuf:"bytes,2,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	InvestorId    string                 `protobuf:"bytes,3,opt,name=investor_id,json=investorId,proto3" json:"investor_id,omitempty"`
+	Amount        int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Investment) Reset() {
+	*x = Investment{}
+	mi := &file_loan_v1_loan_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Investment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Investment) ProtoMessage() {}
+
+func (x *Investment) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Investment.ProtoReflect.Descriptor instead.
+func (*Investment) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Investment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Investment) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *Investment) GetInvestorId() string {
+	if x != nil {
+		return x.InvestorId
+	}
+	return ""
+}
+
+func (x *Investment) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Investment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateLoanRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	BorrowerId      string                 `protobuf:"bytes,1,opt,name=borrower_id,json=borrowerId,proto3" json:"borrower_id,omitempty"`
+	PrincipalAmount int64                  `protobuf:"varint,2,opt,name=principal_amount,json=principalAmount,proto3" json:"principal_amount,omitempty"`
+	// Decimal string, e.g. "0.15".
+	Rate string `protobuf:"bytes,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	// Decimal string, e.g. "0.12".
+	Roi          string `protobuf:"bytes,4,opt,name=roi,proto3" json:"roi,omitempty"`
+	BorrowerName string `protobuf:"bytes,5,opt,name=borrower_name,json=borrowerName,proto3" json:"borrower_name,omitempty"`
+	Notes        string `protobuf:"bytes,6,opt,name=notes,proto3" json:"notes,omitempty"`
+	// The loan product whose limits the terms must fall within.
+	ProductId   string `protobuf:"bytes,7,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	TenorMonths int32  `protobuf:"varint,8,opt,name=tenor_months,json=tenorMonths,proto3" json:"tenor_months,omitempty"`
+	// Where picture proofs are expected to be taken; optional.
+	BorrowerLocation *GeoPoint `protobuf:"bytes,9,opt,name=borrower_location,json=borrowerLocation,proto3" json:"borrower_location,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateLoanRequest) Reset() {
+	*x = CreateLoanRequest{}
+	mi := &file_loan_v1_loan_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLoanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLoanRequest) ProtoMessage() {}
+
+func (x *CreateLoanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLoanRequest.ProtoReflect.Descriptor instead.
+func (*CreateLoanRequest) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateLoanRequest) GetBorrowerId() string {
+	if x != nil {
+		return x.BorrowerId
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetPrincipalAmount() int64 {
+	if x != nil {
+		return x.PrincipalAmount
+	}
+	return 0
+}
+
+func (x *CreateLoanRequest) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetRoi() string {
+	if x != nil {
+		return x.Roi
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetBorrowerName() string {
+	if x != nil {
+		return x.BorrowerName
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetTenorMonths() int32 {
+	if x != nil {
+		return x.TenorMonths
+	}
+	return 0
+}
+
+func (x *CreateLoanRequest) GetBorrowerLocation() *GeoPoint {
+	if x != nil {
+		return x.BorrowerLocation
+	}
+	return nil
+}
+
+type GetLoanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLoanRequest) Reset() {
+	*x = GetLoanRequest{}
+	mi := &file_loan_v1_loan_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLoanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLoanRequest) ProtoMessage() {}
+
+func (x *GetLoanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLoanRequest.ProtoReflect.Descriptor instead.
+func (*GetLoanRequest) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetLoanRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListLoansRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to 10.
+	Limit         int32     `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32     `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	State         LoanState `protobuf:"varint,3,opt,name=state,proto3,enum=amartha.loan.v1.LoanState" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoansRequest) Reset() {
+	*x = ListLoansRequest{}
+	mi := &file_loan_v1_loan_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoansRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoansRequest) ProtoMessage() {}
+
+func (x *ListLoansRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoansRequest.ProtoReflect.Descriptor instead.
+func (*ListLoansRequest) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListLoansRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListLoansRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListLoansRequest) GetState() LoanState {
+	if x != nil {
+		return x.State
+	}
+	return LoanState_LOAN_STATE_UNSPECIFIED
+}
+
+type ListLoansResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Loans         []*Loan                `protobuf:"bytes,1,rep,name=loans,proto3" json:"loans,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoansResponse) Reset() {
+	*x = ListLoansResponse{}
+	mi := &file_loan_v1_loan_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoansResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoansResponse) ProtoMessage() {}
+
+func (x *ListLoansResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoansResponse.ProtoReflect.Descriptor instead.
+func (*ListLoansResponse) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListLoansResponse) GetLoans() []*Loan {
+	if x != nil {
+		return x.Loans
+	}
+	return nil
+}
+
+func (x *ListLoansResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListLoansResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListLoansResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type FileMetadata struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Original file name; only its extension is kept.
+	Filename      string `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileMetadata) Reset() {
+	*x = FileMetadata{}
+	mi := &file_loan_v1_loan_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileMetadata) ProtoMessage() {}
+
+func (x *FileMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileMetadata.ProtoReflect.Descriptor instead.
+func (*FileMetadata) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FileMetadata) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+type ApproveLoanRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ApproveLoanRequest_Metadata_
+	//	*ApproveLoanRequest_Chunk
+	Payload       isApproveLoanRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveLoanRequest) Reset() {
+	*x = ApproveLoanRequest{}
+	mi := &file_loan_v1_loan_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveLoanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveLoanRequest) ProtoMessage() {}
+
+func (x *ApproveLoanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveLoanRequest.ProtoReflect.Descriptor instead.
+func (*ApproveLoanRequest) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ApproveLoanRequest) GetPayload() isApproveLoanRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ApproveLoanRequest) GetMetadata() *ApproveLoanRequest_Metadata {
+	if x != nil {
+		if x, ok := x.Payload.(*ApproveLoanRequest_Metadata_); ok {
+			return x.Metadata
+		}
+	}
+	return nil
+}
+
+func (x *ApproveLoanRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*ApproveLoanRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isApproveLoanRequest_Payload interface {
+	isApproveLoanRequest_Payload()
+}
+
+type ApproveLoanRequest_Metadata_ struct {
+	Metadata *ApproveLoanRequest_Metadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+}
+
+type ApproveLoanRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*ApproveLoanRequest_Metadata_) isApproveLoanRequest_Payload() {}
+
+func (*ApproveLoanRequest_Chunk) isApproveLoanRequest_Payload() {}
+
+type AddInvestmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoanId        string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	InvestorId    string                 `protobuf:"bytes,2,opt,name=investor_id,json=investorId,proto3" json:"investor_id,omitempty"`
+	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddInvestmentRequest) Reset() {
+	*x = AddInvestmentRequest{}
+	mi := &file_loan_v1_loan_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddInvestmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddInvestmentRequest) ProtoMessage() {}
+
+func (x *AddInvestmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddInvestmentRequest.ProtoReflect.Descriptor instead.
+func (*AddInvestmentRequest) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AddInvestmentRequest) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *AddInvestmentRequest) GetInvestorId() string {
+	if x != nil {
+		return x.InvestorId
+	}
+	return ""
+}
+
+func (x *AddInvestmentRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type AddInvestmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Loan          *Loan                  `protobuf:"bytes,1,opt,name=loan,proto3" json:"loan,omitempty"`
+	Investment    *Investment            `protobuf:"bytes,2,opt,name=investment,proto3" json:"investment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddInvestmentResponse) Reset() {
+	*x = AddInvestmentResponse{}
+	mi := &file_loan_v1_loan_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddInvestmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddInvestmentResponse) ProtoMessage() {}
+
+func (x *AddInvestmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddInvestmentResponse.ProtoReflect.Descriptor instead.
+func (*AddInvestmentResponse) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AddInvestmentResponse) GetLoan() *Loan {
+	if x != nil {
+		return x.Loan
+	}
+	return nil
+}
+
+func (x *AddInvestmentResponse) GetInvestment() *Investment {
+	if x != nil {
+		return x.Investment
+	}
+	return nil
+}
+
+type ListInvestmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoanId        string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInvestmentsRequest) Reset() {
+	*x = ListInvestmentsRequest{}
+	mi := &file_loan_v1_loan_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInvestmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInvestmentsRequest) ProtoMessage() {}
+
+func (x *ListInvestmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInvestmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListInvestmentsRequest) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListInvestmentsRequest) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+type ListInvestmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Investments   []*Investment          `protobuf:"bytes,1,rep,name=investments,proto3" json:"investments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInvestmentsResponse) Reset() {
+	*x = ListInvestmentsResponse{}
+	mi := &file_loan_v1_loan_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInvestmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInvestmentsResponse) ProtoMessage() {}
+
+func (x *ListInvestmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInvestmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListInvestmentsResponse) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListInvestmentsResponse) GetInvestments() []*Investment {
+	if x != nil {
+		return x.Investments
+	}
+	return nil
+}
+
+type DisburseLoanRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*DisburseLoanRequest_Metadata_
+	//	*DisburseLoanRequest_Chunk
+	Payload       isDisburseLoanRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisburseLoanRequest) Reset() {
+	*x = DisburseLoanRequest{}
+	mi := &file_loan_v1_loan_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisburseLoanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisburseLoanRequest) ProtoMessage() {}
+
+func (x *DisburseLoanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisburseLoanRequest.ProtoReflect.Descriptor instead.
+func (*DisburseLoanRequest) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DisburseLoanRequest) GetPayload() isDisburseLoanRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *DisburseLoanRequest) GetMetadata() *DisburseLoanRequest_Metadata {
+	if x != nil {
+		if x, ok := x.Payload.(*DisburseLoanRequest_Metadata_); ok {
+			return x.Metadata
+		}
+	}
+	return nil
+}
+
+func (x *DisburseLoanRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*DisburseLoanRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isDisburseLoanRequest_Payload interface {
+	isDisburseLoanRequest_Payload()
+}
+
+type DisburseLoanRequest_Metadata_ struct {
+	Metadata *DisburseLoanRequest_Metadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+}
+
+type DisburseLoanRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*DisburseLoanRequest_Metadata_) isDisburseLoanRequest_Payload() {}
+
+func (*DisburseLoanRequest_Chunk) isDisburseLoanRequest_Payload() {}
+
+type ApproveLoanRequest_Metadata struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	LoanId           string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	FieldValidatorId string                 `protobuf:"bytes,2,opt,name=field_validator_id,json=fieldValidatorId,proto3" json:"field_validator_id,omitempty"`
+	PictureProof     *FileMetadata          `protobuf:"bytes,3,opt,name=picture_proof,json=pictureProof,proto3" json:"picture_proof,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ApproveLoanRequest_Metadata) Reset() {
+	*x = ApproveLoanRequest_Metadata{}
+	mi := &file_loan_v1_loan_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveLoanRequest_Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveLoanRequest_Metadata) ProtoMessage() {}
+
+func (x *ApproveLoanRequest_Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveLoanRequest_Metadata.ProtoReflect.Descriptor instead.
+func (*ApproveLoanRequest_Metadata) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{9, 0}
+}
+
+func (x *ApproveLoanRequest_Metadata) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *ApproveLoanRequest_Metadata) GetFieldValidatorId() string {
+	if x != nil {
+		return x.FieldValidatorId
+	}
+	return ""
+}
+
+func (x *ApproveLoanRequest_Metadata) GetPictureProof() *FileMetadata {
+	if x != nil {
+		return x.PictureProof
+	}
+	return nil
+}
+
+type DisburseLoanRequest_Metadata struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	LoanId          string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	FieldOfficerId  string                 `protobuf:"bytes,2,opt,name=field_officer_id,json=fieldOfficerId,proto3" json:"field_officer_id,omitempty"`
+	SignedAgreement *FileMetadata          `protobuf:"bytes,3,opt,name=signed_agreement,json=signedAgreement,proto3" json:"signed_agreement,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DisburseLoanRequest_Metadata) Reset() {
+	*x = DisburseLoanRequest_Metadata{}
+	mi := &file_loan_v1_loan_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisburseLoanRequest_Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisburseLoanRequest_Metadata) ProtoMessage() {}
+
+func (x *DisburseLoanRequest_Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisburseLoanRequest_Metadata.ProtoReflect.Descriptor instead.
+func (*DisburseLoanRequest_Metadata) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{14, 0}
+}
+
+func (x *DisburseLoanRequest_Metadata) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *DisburseLoanRequest_Metadata) GetFieldOfficerId() string {
+	if x != nil {
+		return x.FieldOfficerId
+	}
+	return ""
+}
+
+func (x *DisburseLoanRequest_Metadata) GetSignedAgreement() *FileMetadata {
+	if x != nil {
+		return x.SignedAgreement
+	}
+	return nil
+}
+
+var File_loan_v1_loan_proto protoreflect.FileDescriptor
+
+const file_loan_v1_loan_proto_rawDesc = "" +
+	"\n" +
+	"\x12loan/v1/loan.proto\x12\x0famartha.loan.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x81\x06\n" +
+	"\x04Loan\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vborrower_id\x18\x02 \x01(\tR\n" +
+	"borrowerId\x12)\n" +
+	"\x10principal_amount\x18\x03 \x01(\x03R\x0fprincipalAmount\x12\x12\n" +
+	"\x04rate\x18\x04 \x01(\tR\x04rate\x12\x10\n" +
+	"\x03roi\x18\x05 \x01(\tR\x03roi\x120\n" +
+	"\x05state\x18\x06 \x01(\x0e2\x1a.amartha.loan.v1.LoanStateR\x05state\x125\n" +
+	"\x14agreement_letter_url\x18\a \x01(\tH\x00R\x12agreementLetterUrl\x88\x01\x01\x12%\n" +
+	"\x0etotal_invested\x18\b \x01(\x03R\rtotalInvested\x12)\n" +
+	"\x10remaining_amount\x18\t \x01(\x03R\x0fremainingAmount\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12#\n" +
+	"\rborrower_name\x18\f \x01(\tR\fborrowerName\x12\x14\n" +
+	"\x05notes\x18\r \x01(\tR\x05notes\x12\"\n" +
+	"\n" +
+	"product_id\x18\x0e \x01(\tH\x01R\tproductId\x88\x01\x01\x12!\n" +
+	"\ftenor_months\x18\x0f \x01(\x05R\vtenorMonths\x12F\n" +
+	"\x11borrower_location\x18\x10 \x01(\v2\x19.amartha.loan.v1.GeoPointR\x10borrowerLocation\x12T\n" +
+	"\x13agreement_signature\x18\x11 \x01(\v2#.amartha.loan.v1.AgreementSignatureR\x12agreementSignatureB\x17\n" +
+	"\x15_agreement_letter_urlB\r\n" +
+	"\v_product_id\"\xb2\x01\n" +
+	"\x12AgreementSignature\x12\x16\n" +
+	"\x06sha256\x18\x01 \x01(\tR\x06sha256\x12\x1c\n" +
+	"\tsignature\x18\x02 \x01(\fR\tsignature\x12-\n" +
+	"\x12signer_fingerprint\x18\x03 \x01(\tR\x11signerFingerprint\x127\n" +
+	"\tsigned_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bsignedAt\"D\n" +
+	"\bGeoPoint\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"\xa9\x01\n" +
+	"\n" +
+	"Investment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\aloan_id\x18\x02 \x01(\tR\x06loanId\x12\x1f\n" +
+	"\vinvestor_id\x18\x03 \x01(\tR\n" +
+	"investorId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xca\x02\n" +
+	"\x11CreateLoanRequest\x12\x1f\n" +
+	"\vborrower_id\x18\x01 \x01(\tR\n" +
+	"borrowerId\x12)\n" +
+	"\x10principal_amount\x18\x02 \x01(\x03R\x0fprincipalAmount\x12\x12\n" +
+	"\x04rate\x18\x03 \x01(\tR\x04rate\x12\x10\n" +
+	"\x03roi\x18\x04 \x01(\tR\x03roi\x12#\n" +
+	"\rborrower_name\x18\x05 \x01(\tR\fborrowerName\x12\x14\n" +
+	"\x05notes\x18\x06 \x01(\tR\x05notes\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\a \x01(\tR\tproductId\x12!\n" +
+	"\ftenor_months\x18\b \x01(\x05R\vtenorMonths\x12F\n" +
+	"\x11borrower_location\x18\t \x01(\v2\x19.amartha.loan.v1.GeoPointR\x10borrowerLocation\" \n" +
+	"\x0eGetLoanRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"r\n" +
+	"\x10ListLoansRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x120\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x1a.amartha.loan.v1.LoanStateR\x05state\"\x84\x01\n" +
+	"\x11ListLoansResponse\x12+\n" +
+	"\x05loans\x18\x01 \x03(\v2\x15.amartha.loan.v1.LoanR\x05loans\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"*\n" +
+	"\fFileMetadata\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\"\x9b\x02\n" +
+	"\x12ApproveLoanRequest\x12J\n" +
+	"\bmetadata\x18\x01 \x01(\v2,.amartha.loan.v1.ApproveLoanRequest.MetadataH\x00R\bmetadata\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunk\x1a\x95\x01\n" +
+	"\bMetadata\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\x12,\n" +
+	"\x12field_validator_id\x18\x02 \x01(\tR\x10fieldValidatorId\x12B\n" +
+	"\rpicture_proof\x18\x03 \x01(\v2\x1d.amartha.loan.v1.FileMetadataR\fpictureProofB\t\n" +
+	"\apayload\"h\n" +
+	"\x14AddInvestmentRequest\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\x12\x1f\n" +
+	"\vinvestor_id\x18\x02 \x01(\tR\n" +
+	"investorId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\"\x7f\n" +
+	"\x15AddInvestmentResponse\x12)\n" +
+	"\x04loan\x18\x01 \x01(\v2\x15.amartha.loan.v1.LoanR\x04loan\x12;\n" +
+	"\n" +
+	"investment\x18\x02 \x01(\v2\x1b.amartha.loan.v1.InvestmentR\n" +
+	"investment\"1\n" +
+	"\x16ListInvestmentsRequest\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\"X\n" +
+	"\x17ListInvestmentsResponse\x12=\n" +
+	"\vinvestments\x18\x01 \x03(\v2\x1b.amartha.loan.v1.InvestmentR\vinvestments\"\x9f\x02\n" +
+	"\x13DisburseLoanRequest\x12K\n" +
+	"\bmetadata\x18\x01 \x01(\v2-.amartha.loan.v1.DisburseLoanRequest.MetadataH\x00R\bmetadata\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunk\x1a\x97\x01\n" +
+	"\bMetadata\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\x12(\n" +
+	"\x10field_officer_id\x18\x02 \x01(\tR\x0efieldOfficerId\x12H\n" +
+	"\x10signed_agreement\x18\x03 \x01(\v2\x1d.amartha.loan.v1.FileMetadataR\x0fsignedAgreementB\t\n" +
+	"\apayload*\x8c\x01\n" +
+	"\tLoanState\x12\x1a\n" +
+	"\x16LOAN_STATE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13LOAN_STATE_PROPOSED\x10\x01\x12\x17\n" +
+	"\x13LOAN_STATE_APPROVED\x10\x02\x12\x17\n" +
+	"\x13LOAN_STATE_INVESTED\x10\x03\x12\x18\n" +
+	"\x14LOAN_STATE_DISBURSED\x10\x042\xcf\x04\n" +
+	"\vLoanService\x12G\n" +
+	"\n" +
+	"CreateLoan\x12\".amartha.loan.v1.CreateLoanRequest\x1a\x15.amartha.loan.v1.Loan\x12A\n" +
+	"\aGetLoan\x12\x1f.amartha.loan.v1.GetLoanRequest\x1a\x15.amartha.loan.v1.Loan\x12R\n" +
+	"\tListLoans\x12!.amartha.loan.v1.ListLoansRequest\x1a\".amartha.loan.v1.ListLoansResponse\x12K\n" +
+	"\vApproveLoan\x12#.amartha.loan.v1.ApproveLoanRequest\x1a\x15.amartha.loan.v1.Loan(\x01\x12^\n" +
+	"\rAddInvestment\x12%.amartha.loan.v1.AddInvestmentRequest\x1a&.amartha.loan.v1.AddInvestmentResponse\x12d\n" +
+	"\x0fListInvestments\x12'.amartha.loan.v1.ListInvestmentsRequest\x1a(.amartha.loan.v1.ListInvestmentsResponse\x12M\n" +
+	"\fDisburseLoan\x12$.amartha.loan.v1.DisburseLoanRequest\x1a\x15.amartha.loan.v1.Loan(\x01B=Z;github.com/agunghallmanmaliki/amartha/pkg/pb/loan/v1;loanv1b\x06proto3"
+
+var (
+	file_loan_v1_loan_proto_rawDescOnce sync.Once
+	file_loan_v1_loan_proto_rawDescData []byte
+)
+
+func file_loan_v1_loan_proto_rawDescGZIP() []byte {
+	file_loan_v1_loan_proto_rawDescOnce.Do(func() {
+		file_loan_v1_loan_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_loan_v1_loan_proto_rawDesc), len(file_loan_v1_loan_proto_rawDesc)))
+	})
+	return file_loan_v1_loan_proto_rawDescData
+}
+
+var file_loan_v1_loan_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_loan_v1_loan_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_loan_v1_loan_proto_goTypes = []any{
+	(LoanState)(0),                       // 0: amartha.loan.v1.LoanState
+	(*Loan)(nil),                         // 1: amartha.loan.v1.Loan
+	(*AgreementSignature)(nil),           // 2: amartha.loan.v1.AgreementSignature
+	(*GeoPoint)(nil),                     // 3: amartha.loan.v1.GeoPoint
+	(*Investment)(nil),                   // 4: amartha.loan.v1.Investment
+	(*CreateLoanRequest)(nil),            // 5: amartha.loan.v1.CreateLoanRequest
+	(*GetLoanRequest)(nil),               // 6: amartha.loan.v1.GetLoanRequest
+	(*ListLoansRequest)(nil),             // 7: amartha.loan.v1.ListLoansRequest
+	(*ListLoansResponse)(nil),            // 8: amartha.loan.v1.ListLoansResponse
+	(*FileMetadata)(nil),                 // 9: amartha.loan.v1.FileMetadata
+	(*ApproveLoanRequest)(nil),           // 10: amartha.loan.v1.ApproveLoanRequest
+	(*AddInvestmentRequest)(nil),         // 11: amartha.loan.v1.AddInvestmentRequest
+	(*AddInvestmentResponse)(nil),        // 12: amartha.loan.v1.AddInvestmentResponse
+	(*ListInvestmentsRequest)(nil),       // 13: amartha.loan.v1.ListInvestmentsRequest
+	(*ListInvestmentsResponse)(nil),      // 14: amartha.loan.v1.ListInvestmentsResponse
+	(*DisburseLoanRequest)(nil),          // 15: amartha.loan.v1.DisburseLoanRequest
+	(*ApproveLoanRequest_Metadata)(nil),  // 16: amartha.loan.v1.ApproveLoanRequest.Metadata
+	(*DisburseLoanRequest_Metadata)(nil), // 17: amartha.loan.v1.DisburseLoanRequest.Metadata
+	(*timestamppb.Timestamp)(nil),        // 18: google.protobuf.Timestamp
+}
+var file_loan_v1_loan_proto_depIdxs = []int32{
+	0,  // 0: amartha.loan.v1.Loan.state:type_name -> amartha.loan.v1.LoanState
+	18, // 1: amartha.loan.v1.Loan.created_at:type_name -> google.protobuf.Timestamp
+	18, // 2: amartha.loan.v1.Loan.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 3: amartha.loan.v1.Loan.borrower_location:type_name -> amartha.loan.v1.GeoPoint
+	2,  // 4: amartha.loan.v1.Loan.agreement_signature:type_name -> amartha.loan.v1.AgreementSignature
+	18, // 5: amartha.loan.v1.AgreementSignature.signed_at:type_name -> google.protobuf.Timestamp
+	18, // 6: amartha.loan.v1.Investment.created_at:type_name -> google.protobuf.Timestamp
+	3,  // 7: amartha.loan.v1.CreateLoanRequest.borrower_location:type_name -> amartha.loan.v1.GeoPoint
+	0,  // 8: amartha.loan.v1.ListLoansRequest.state:type_name -> amartha.loan.v1.LoanState
+	1,  // 9: amartha.loan.v1.ListLoansResponse.loans:type_name -> amartha.loan.v1.Loan
+	16, // 10: amartha.loan.v1.ApproveLoanRequest.metadata:type_name -> amartha.loan.v1.ApproveLoanRequest.Metadata
+	1,  // 11: amartha.loan.v1.AddInvestmentResponse.loan:type_name -> amartha.loan.v1.Loan
+	4,  // 12: amartha.loan.v1.AddInvestmentResponse.investment:type_name -> amartha.loan.v1.Investment
+	4,  // 13: amartha.loan.v1.ListInvestmentsResponse.investments:type_name -> amartha.loan.v1.Investment
+	17, // 14: amartha.loan.v1.DisburseLoanRequest.metadata:type_name -> amartha.loan.v1.DisburseLoanRequest.Metadata
+	9,  // 15: amartha.loan.v1.ApproveLoanRequest.Metadata.picture_proof:type_name -> amartha.loan.v1.FileMetadata
+	9,  // 16: amartha.loan.v1.DisburseLoanRequest.Metadata.signed_agreement:type_name -> amartha.loan.v1.FileMetadata
+	5,  // 17: amartha.loan.v1.LoanService.CreateLoan:input_type -> amartha.loan.v1.CreateLoanRequest
+	6,  // 18: amartha.loan.v1.LoanService.GetLoan:input_type -> amartha.loan.v1.GetLoanRequest
+	7,  // 19: amartha.loan.v1.LoanService.ListLoans:input_type -> amartha.loan.v1.ListLoansRequest
+	10, // 20: amartha.loan.v1.LoanService.ApproveLoan:input_type -> amartha.loan.v1.ApproveLoanRequest
+	11, // 21: amartha.loan.v1.LoanService.AddInvestment:input_type -> amartha.loan.v1.AddInvestmentRequest
+	13, // 22: amartha.loan.v1.LoanService.ListInvestments:input_type -> amartha.loan.v1.ListInvestmentsRequest
+	15, // 23: amartha.loan.v1.LoanService.DisburseLoan:input_type -> amartha.loan.v1.DisburseLoanRequest
+	1,  // 24: amartha.loan.v1.LoanService.CreateLoan:output_type -> amartha.loan.v1.Loan
+	1,  // 25: amartha.loan.v1.LoanService.GetLoan:output_type -> amartha.loan.v1.Loan
+	8,  // 26: amartha.loan.v1.LoanService.ListLoans:output_type -> amartha.loan.v1.ListLoansResponse
+	1,  // 27: amartha.loan.v1.LoanService.ApproveLoan:output_type -> amartha.loan.v1.Loan
+	12, // 28: amartha.loan.v1.LoanService.AddInvestment:output_type -> amartha.loan.v1.AddInvestmentResponse
+	14, // 29: amartha.loan.v1.LoanService.ListInvestments:output_type -> amartha.loan.v1.ListInvestmentsResponse
+	1,  // 30: amartha.loan.v1.LoanService.DisburseLoan:output_type -> amartha.loan.v1.Loan
+	24, // [24:31] is the sub-list for method output_type
+	17, // [17:24] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_loan_v1_loan_proto_init() }
+func file_loan_v1_loan_proto_init() {
+	if File_loan_v1_loan_proto != nil {
+		return
+	}
+	file_loan_v1_loan_proto_msgTypes[0].OneofWrappers = []any{}
+	file_loan_v1_loan_proto_msgTypes[9].OneofWrappers = []any{
+		(*ApproveLoanRequest_Metadata_)(nil),
+		(*ApproveLoanRequest_Chunk)(nil),
+	}
+	file_loan_v1_loan_proto_msgTypes[14].OneofWrappers = []any{
+		(*DisburseLoanRequest_Metadata_)(nil),
+		(*DisburseLoanRequest_Chunk)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_loan_v1_loan_proto_rawDesc), len(file_loan_v1_loan_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_loan_v1_loan_proto_goTypes,
+		DependencyIndexes: file_loan_v1_loan_proto_depIdxs,
+		EnumInfos:         file_loan_v1_loan_proto_enumTypes,
+		MessageInfos:      file_loan_v1_loan_proto_msgTypes,
+	}.Build()
+	File_loan_v1_loan_proto = out.File
+	file_loan_v1_loan_proto_goTypes = nil
+	file_loan_v1_loan_proto_depIdxs = nil
+}
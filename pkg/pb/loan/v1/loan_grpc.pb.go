@@ -0,0 +1,348 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: loan/v1/loan.proto
+
+package loanv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LoanService_CreateLoan_FullMethodName      = "/amartha.loan.v1.LoanService/CreateLoan"
+	LoanService_GetLoan_FullMethodName         = "/amartha.loan.v1.LoanService/GetLoan"
+	LoanService_ListLoans_FullMethodName       = "/amartha.loan.v1.LoanService/ListLoans"
+	LoanService_ApproveLoan_FullMethodName     = "/amartha.loan.v1.LoanService/ApproveLoan"
+	LoanService_AddInvestment_FullMethodName   = "/amartha.loan.v1.LoanService/AddInvestment"
+	LoanService_ListInvestments_FullMethodName = "/amartha.loan.v1.LoanService/ListInvestments"
+	LoanService_DisburseLoan_FullMethodName    = "/amartha.loan.v1.LoanService/DisburseLoan"
+)
+
+// LoanServiceClient is the client API for LoanService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LoanService mirrors the REST API under /api/v1/loans.
+type LoanServiceClient interface {
+	CreateLoan(ctx context.Context, in *CreateLoanRequest, opts ...grpc.CallOption) (*Loan, error)
+	GetLoan(ctx context.Context, in *GetLoanRequest, opts ...grpc.CallOption) (*Loan, error)
+	ListLoans(ctx context.Context, in *ListLoansRequest, opts ...grpc.CallOption) (*ListLoansResponse, error)
+	// ApproveLoan takes the approval metadata as the first message, followed by
+	// the picture proof in one or more chunks.
+	ApproveLoan(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ApproveLoanRequest, Loan], error)
+	AddInvestment(ctx context.Context, in *AddInvestmentRequest, opts ...grpc.CallOption) (*AddInvestmentResponse, error)
+	ListInvestments(ctx context.Context, in *ListInvestmentsRequest, opts ...grpc.CallOption) (*ListInvestmentsResponse, error)
+	// DisburseLoan takes the disbursement metadata as the first message,
+	// followed by the signed agreement in one or more chunks.
+	DisburseLoan(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[DisburseLoanRequest, Loan], error)
+}
+
+type loanServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLoanServiceClient(cc grpc.ClientConnInterface) LoanServiceClient {
+	return &loanServiceClient{cc}
+}
+
+func (c *loanServiceClient) CreateLoan(ctx context.Context, in *CreateLoanRequest, opts ...grpc.CallOption) (*Loan, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Loan)
+	err := c.cc.Invoke(ctx, LoanService_CreateLoan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) GetLoan(ctx context.Context, in *GetLoanRequest, opts ...grpc.CallOption) (*Loan, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Loan)
+	err := c.cc.Invoke(ctx, LoanService_GetLoan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) ListLoans(ctx context.Context, in *ListLoansRequest, opts ...grpc.CallOption) (*ListLoansResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLoansResponse)
+	err := c.cc.Invoke(ctx, LoanService_ListLoans_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) ApproveLoan(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ApproveLoanRequest, Loan], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LoanService_ServiceDesc.Streams[0], LoanService_ApproveLoan_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ApproveLoanRequest, Loan]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LoanService_ApproveLoanClient = grpc.ClientStreamingClient[ApproveLoanRequest, Loan]
+
+func (c *loanServiceClient) AddInvestment(ctx context.Context, in *AddInvestmentRequest, opts ...grpc.CallOption) (*AddInvestmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddInvestmentResponse)
+	err := c.cc.Invoke(ctx, LoanService_AddInvestment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) ListInvestments(ctx context.Context, in *ListInvestmentsRequest, opts ...grpc.CallOption) (*ListInvestmentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInvestmentsResponse)
+	err := c.cc.Invoke(ctx, LoanService_ListInvestments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) DisburseLoan(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[DisburseLoanRequest, Loan], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LoanService_ServiceDesc.Streams[1], LoanService_DisburseLoan_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DisburseLoanRequest, Loan]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LoanService_DisburseLoanClient = grpc.ClientStreamingClient[DisburseLoanRequest, Loan]
+
+// LoanServiceServer is the server API for LoanService service.
+// All implementations must embed UnimplementedLoanServiceServer
+// for forward compatibility.
+//
+// LoanService mirrors the REST API under /api/v1/loans.
+type LoanServiceServer interface {
+	CreateLoan(context.Context, *CreateLoanRequest) (*Loan, error)
+	GetLoan(context.Context, *GetLoanRequest) (*Loan, error)
+	ListLoans(context.Context, *ListLoansRequest) (*ListLoansResponse, error)
+	// ApproveLoan takes the approval metadata as the first message, followed by
+	// the picture proof in one or more chunks.
+	ApproveLoan(grpc.ClientStreamingServer[ApproveLoanRequest, Loan]) error
+	AddInvestment(context.Context, *AddInvestmentRequest) (*AddInvestmentResponse, error)
+	ListInvestments(context.Context, *ListInvestmentsRequest) (*ListInvestmentsResponse, error)
+	// DisburseLoan takes the disbursement metadata as the first message,
+	// followed by the signed agreement in one or more chunks.
+	DisburseLoan(grpc.ClientStreamingServer[DisburseLoanRequest, Loan]) error
+	mustEmbedUnimplementedLoanServiceServer()
+}
+
+// UnimplementedLoanServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLoanServiceServer struct{}
+
+func (UnimplementedLoanServiceServer) CreateLoan(context.Context, *CreateLoanRequest) (*Loan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLoan not implemented")
+}
+func (UnimplementedLoanServiceServer) GetLoan(context.Context, *GetLoanRequest) (*Loan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLoan not implemented")
+}
+func (UnimplementedLoanServiceServer) ListLoans(context.Context, *ListLoansRequest) (*ListLoansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLoans not implemented")
+}
+func (UnimplementedLoanServiceServer) ApproveLoan(grpc.ClientStreamingServer[ApproveLoanRequest, Loan]) error {
+	return status.Errorf(codes.Unimplemented, "method ApproveLoan not implemented")
+}
+func (UnimplementedLoanServiceServer) AddInvestment(context.Context, *AddInvestmentRequest) (*AddInvestmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddInvestment not implemented")
+}
+func (UnimplementedLoanServiceServer) ListInvestments(context.Context, *ListInvestmentsRequest) (*ListInvestmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvestments not implemented")
+}
+func (UnimplementedLoanServiceServer) DisburseLoan(grpc.ClientStreamingServer[DisburseLoanRequest, Loan]) error {
+	return status.Errorf(codes.Unimplemented, "method DisburseLoan not implemented")
+}
+func (UnimplementedLoanServiceServer) mustEmbedUnimplementedLoanServiceServer() {}
+func (UnimplementedLoanServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeLoanServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LoanServiceServer will
+// result in compilation errors.
+type UnsafeLoanServiceServer interface {
+	mustEmbedUnimplementedLoanServiceServer()
+}
+
+func RegisterLoanServiceServer(s grpc.ServiceRegistrar, srv LoanServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLoanServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LoanService_ServiceDesc, srv)
+}
+
+func _LoanService_CreateLoan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLoanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).CreateLoan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_CreateLoan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).CreateLoan(ctx, req.(*CreateLoanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_GetLoan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLoanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).GetLoan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_GetLoan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).GetLoan(ctx, req.(*GetLoanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_ListLoans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLoansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).ListLoans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_ListLoans_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).ListLoans(ctx, req.(*ListLoansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_ApproveLoan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LoanServiceServer).ApproveLoan(&grpc.GenericServerStream[ApproveLoanRequest, Loan]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LoanService_ApproveLoanServer = grpc.ClientStreamingServer[ApproveLoanRequest, Loan]
+
+func _LoanService_AddInvestment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddInvestmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).AddInvestment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_AddInvestment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).AddInvestment(ctx, req.(*AddInvestmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_ListInvestments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvestmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).ListInvestments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_ListInvestments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).ListInvestments(ctx, req.(*ListInvestmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_DisburseLoan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LoanServiceServer).DisburseLoan(&grpc.GenericServerStream[DisburseLoanRequest, Loan]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LoanService_DisburseLoanServer = grpc.ClientStreamingServer[DisburseLoanRequest, Loan]
+
+// LoanService_ServiceDesc is the grpc.ServiceDesc for LoanService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LoanService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "amartha.loan.v1.LoanService",
+	HandlerType: (*LoanServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateLoan",
+			Handler:    _LoanService_CreateLoan_Handler,
+		},
+		{
+			MethodName: "GetLoan",
+			Handler:    _LoanService_GetLoan_Handler,
+		},
+		{
+			MethodName: "ListLoans",
+			Handler:    _LoanService_ListLoans_Handler,
+		},
+		{
+			MethodName: "AddInvestment",
+			Handler:    _LoanService_AddInvestment_Handler,
+		},
+		{
+			MethodName: "ListInvestments",
+			Handler:    _LoanService_ListInvestments_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ApproveLoan",
+			Handler:       _LoanService_ApproveLoan_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DisburseLoan",
+			Handler:       _LoanService_DisburseLoan_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "loan/v1/loan.proto",
+}